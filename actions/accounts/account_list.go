@@ -2,10 +2,15 @@ package actions_accounts
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"gorm.io/gorm"
 	"samvasta.com/bujit/actions"
 	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
 	"samvasta.com/bujit/session"
 )
 
@@ -16,11 +21,24 @@ type ListAccountAction struct {
 	MaxBalance   *models.Money
 	CategoryName string
 	AsTree       bool
+	On           time.Time // show the balances as they were at the start of this time. The zero time means now
+	Query        query.Query
 	Session      *session.Session
 }
 
+// ListAccountFields can be used to filter and order the accounts
+var ListAccountFields = query.Fields{
+	{Name: "name", Column: "accounts.name", Kind: query.Text},
+	{Name: "description", Column: "accounts.description", Kind: query.Text},
+	{Name: "category", Column: "Category.fully_qualified_name", Kind: query.Text},
+	{Name: "balance", Column: "CurrentState.balance", Kind: query.Money},
+	{Name: "created", Column: "accounts.created_at", Kind: query.Date},
+	{Name: "updated", Column: "CurrentState.effective_at", Kind: query.Date},
+}
+
 type ListAccountOutput struct {
-	Tree bool `json="tree"`
+	Tree      bool `json:"tree"`
+	IsOrdered bool `json:"ordered"` // the accounts are in the order the query asked for, rather than by category
 }
 
 func (action ListAccountAction) IsValid() bool {
@@ -50,29 +68,183 @@ func (action ListAccountAction) Execute() (actions.ActionResult, []*actions.Cons
 		conditionValues = append(conditionValues, "%"+action.CategoryName+"%")
 	}
 
+	// tx selects the accounts by the conditions so far
+	tx := func() *gorm.DB {
+		return action.Session.Db.Joins("CurrentState").Joins("Category").Where(strings.Join(conditions, " AND "), conditionValues...)
+	}
+
+	balanceColumn, q := "CurrentState.balance", action.Query
+	var balances map[uint]models.Money
+	if !action.On.IsZero() {
+		// The balances in the past are worked out from each account's states, then the query filters, orders and
+		// limits the accounts by them as if they were a column
+		var all []models.Account
+		if err := tx().Find(&all).Error; err != nil {
+			return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+		}
+		if len(all) == 0 {
+			return actions.ActionResult{Output: ListAccountOutput{Tree: action.AsTree, IsOrdered: action.Query.IsOrdered()}, IsSuccessful: true}, consequences
+		}
+
+		balances = make(map[uint]models.Money, len(all))
+		var sb strings.Builder
+		sb.WriteString("(CASE accounts.id")
+		for _, a := range all {
+			a.Session = action.Session
+			balance, err := a.BalanceAt(action.On)
+			if err != nil {
+				return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+			}
+			balances[a.ID] = balance
+			fmt.Fprintf(&sb, " WHEN %d THEN %d", a.ID, balance.Value())
+		}
+		sb.WriteString(" END)")
+
+		balanceColumn = sb.String()
+		q = q.WithColumn("balance", balanceColumn)
+	}
+
 	if action.MinBalance != nil {
 		value := (*action.MinBalance).Value()
-		conditions = append(conditions, "CurrentState.Balance >= ?")
+		conditions = append(conditions, balanceColumn+" >= ?")
 		conditionValues = append(conditionValues, fmt.Sprint(value))
 	}
 
 	if action.MaxBalance != nil {
 		value := (*action.MaxBalance).Value()
-		conditions = append(conditions, "CurrentState.Balance <= ?")
+		conditions = append(conditions, balanceColumn+" <= ?")
 		conditionValues = append(conditionValues, fmt.Sprint(value))
 	}
 
 	var accounts []models.Account
-	query := strings.Join(conditions, " AND ")
-	action.Session.Db.Joins("CurrentState").Joins("Category").Where(query, conditionValues...).Find(&accounts)
+	result := q.Apply(tx()).Find(&accounts)
+
+	if result.Error != nil {
+		return actions.ActionResult{Output: result.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
 
 	for _, a := range accounts {
 		a.Session = action.Session
+		if balances != nil {
+			a.CurrentState.Balance = balances[a.ID]
+		}
 		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: a})
 	}
 
-	output := ListAccountOutput{Tree: action.AsTree}
+	listOutput := ListAccountOutput{Tree: action.AsTree, IsOrdered: action.Query.IsOrdered()}
+
+	return actions.ActionResult{Output: listOutput, IsSuccessful: true}, consequences
+
+}
+
+// Helpers shows the accounts as a table, or as a tree of their categories. Unless the query ordered them, the accounts
+// are sorted by category, then name.
+func (lao ListAccountOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	accounts := []models.Account{}
+	for _, c := range consequences {
+		if account, ok := c.Object.(models.Account); ok {
+			accounts = append(accounts, account)
+		}
+	}
+	if !lao.IsOrdered {
+		sort.SliceStable(accounts, func(a, b int) bool {
+			if accounts[a].Category.FullyQualifiedName == accounts[b].Category.FullyQualifiedName {
+				// both accounts in same category. compare by account name
+				return accounts[a].Name < accounts[b].Name
+			}
+			return accounts[a].Category.FullyQualifiedName < accounts[b].Category.FullyQualifiedName
+		})
+	}
+
+	s := actions.ConsequenceSession(consequences)
+
+	var total models.Money = 0
+	for _, account := range accounts {
+		total += account.Balance()
+	}
+
+	if lao.Tree {
+		root := &categoryNode{}
+		for _, account := range accounts {
+			root.add(account)
+		}
+
+		group := output.EmptyOutputGroup()
+		group.UnorderedListItems(root.items(group, s), output.NormalBulletChar)
+		if total.IsNegative() {
+			group.PushStyle(output.TextStyle{Color: output.Error})
+		}
+		return group.Paragraph(fmt.Sprintf("Total: %s", total.String(s))).ToSlice()
+	}
+
+	rows := [][]output.TableCell{}
+	for _, account := range accounts {
+		rows = append(rows, []output.TableCell{
+			output.Cell(account.Name),
+			output.Cell(account.Category.FullyQualifiedName),
+			output.Cell(account.Description),
+			actions.MoneyCell(account.Balance(), s),
+		})
+	}
+
+	columns := []output.TableColumn{
+		output.MakeColumn("Name", output.TextColumn),
+		output.MakeColumn("Category", output.TextColumn),
+		output.MakeColumn("Description", output.TextColumn),
+		output.MakeColumn("Balance", output.MoneyColumn),
+	}
+	return output.EmptyOutputGroup().
+		Table(columns, rows, []output.TableCell{output.Cell("Total"), output.Cell(""), output.Cell(""), actions.MoneyCell(total, s)}).
+		ToSlice()
+}
+
+// categoryNode is a category in the tree of listed accounts, with the accounts directly in it and its subcategories in
+// the order they were first seen
+type categoryNode struct {
+	name          string
+	accounts      []models.Account
+	subcategories []*categoryNode
+}
+
+// add puts the account under its category, making the nodes for the category and its parents as needed
+func (node *categoryNode) add(account models.Account) {
+	if account.Category.FullyQualifiedName == "" {
+		node.accounts = append(node.accounts, account)
+		return
+	}
 
-	return actions.ActionResult{Output: output, IsSuccessful: true}, consequences
+	current := node
+	for _, name := range strings.Split(account.Category.FullyQualifiedName, "/") {
+		var next *categoryNode
+		for _, sub := range current.subcategories {
+			if sub.name == name {
+				next = sub
+				break
+			}
+		}
+		if next == nil {
+			next = &categoryNode{name: name}
+			current.subcategories = append(current.subcategories, next)
+		}
+		current = next
+	}
+	current.accounts = append(current.accounts, account)
+}
 
+// items lists the accounts of the node followed by its subcategories, each with their own contents nested below them
+func (node *categoryNode) items(group *output.OutputGroup, s *session.Session) []output.Text {
+	items := []output.Text{}
+	for _, account := range node.accounts {
+		item := group.Item(fmt.Sprintf("%s: %s", account.Name, account.Balance().String(s)))
+		if account.Balance().IsNegative() {
+			item.Style.Color = output.Error
+		}
+		items = append(items, item)
+	}
+	for _, sub := range node.subcategories {
+		subGroup := group.SubGroup()
+		children := subGroup.UnorderedListItems(sub.items(subGroup, s), output.NormalBulletChar).ToSlice()
+		items = append(items, group.Item(sub.name+"/", children...))
+	}
+	return items
 }
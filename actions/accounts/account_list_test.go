@@ -2,10 +2,13 @@ package actions_accounts
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"samvasta.com/bujit/actions"
 	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
 	"samvasta.com/bujit/session"
 )
 
@@ -66,3 +69,199 @@ func TestListAccountAction(t *testing.T) {
 	t.Run("as tree=true", testCase(ListAccountAction{Session: &s, AsTree: true}, []models.Account{*accounts[0], *accounts[1], *accounts[2]}))
 
 }
+
+func TestListAccountActionQuery(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	food := models.MakeCategory("food", "", nil)
+	s.Db.Create(&food)
+	for _, a := range []*models.Account{
+		{Name: "cash", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(50)}},
+		{Name: "groceries", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(150)}, CategoryID: &food.ID},
+		{Name: "dining", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(300)}, CategoryID: &food.ID},
+		{Name: "savings", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(1000)}},
+	} {
+		s.Db.Create(a)
+	}
+
+	field := func(name string) query.Field {
+		f, _ := ListAccountFields.Find(name)
+		return f
+	}
+
+	// balance > 100 and category ~ "foo" or name = CASH order by balance desc limit 2, where and binds tighter than or.
+	// cash is matched by name but is cut by the limit.
+	action := ListAccountAction{Query: query.Query{
+		Filter: query.Or{
+			Left: query.And{
+				Left:  query.Comparison{Field: field("balance"), Op: query.Greater, Value: int64(10000)},
+				Right: query.Comparison{Field: field("category"), Op: query.Contains, Value: "foo"},
+			},
+			Right: query.Comparison{Field: field("name"), Op: query.Equal, Value: "CASH"},
+		},
+		Order: []query.OrderBy{{Field: field("balance"), Desc: true}},
+		Limit: 2,
+	}, Session: &s}
+
+	result, consequences := action.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, ListAccountOutput{IsOrdered: true}, result.Output)
+
+	names := []string{}
+	for _, c := range consequences {
+		names = append(names, c.Object.(models.Account).Name)
+	}
+	assert.Equal(t, []string{"dining", "groceries"}, names)
+}
+
+func TestListAccountActionOn(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	september := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	opened := models.AccountState{EffectiveAt: september.Unix(), Balance: models.MakeMoney(100)}
+	s.Db.Create(&opened)
+	deposit := models.AccountState{EffectiveAt: september.AddDate(0, 1, 0).Unix(), Balance: models.MakeMoney(150), PrevStateID: &opened.ID}
+	s.Db.Create(&deposit)
+	s.Db.Create(&models.Account{Name: "checking", IsActive: true, CurrentStateID: &deposit.ID})
+
+	testCase := func(action ListAccountAction, expected []models.Money) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, consequences := action.Execute()
+
+			assert.True(t, result.IsSuccessful)
+			balances := []models.Money{}
+			for _, c := range consequences {
+				balances = append(balances, c.Object.(models.Account).CurrentState.Balance)
+			}
+			assert.Equal(t, expected, balances)
+		}
+	}
+
+	hundred := models.MakeMoney(100)
+
+	t.Run("now", testCase(ListAccountAction{Session: &s}, []models.Money{models.MakeMoney(150)}))
+	t.Run("on", testCase(ListAccountAction{On: september.AddDate(0, 0, 15), Session: &s}, []models.Money{hundred}))
+	t.Run("before opening", testCase(ListAccountAction{On: september, Session: &s}, []models.Money{0}))
+	t.Run("min balance on", testCase(ListAccountAction{On: september, MinBalance: &hundred, Session: &s}, []models.Money{}))
+	t.Run("max balance on", testCase(ListAccountAction{On: september.AddDate(0, 0, 15), MaxBalance: &hundred, Session: &s}, []models.Money{hundred}))
+	t.Run("nothing on", testCase(ListAccountAction{Name: "missing", On: september, Session: &s}, []models.Money{}))
+}
+
+func TestListAccountActionOnQuery(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	september := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	october := september.AddDate(0, 1, 0)
+	account := func(name string, before, after float64) {
+		opened := models.AccountState{EffectiveAt: september.Unix(), Balance: models.MakeMoney(before)}
+		s.Db.Create(&opened)
+		deposit := models.AccountState{EffectiveAt: october.Unix(), Balance: models.MakeMoney(after), PrevStateID: &opened.ID}
+		s.Db.Create(&deposit)
+		s.Db.Create(&models.Account{Name: name, IsActive: true, CurrentStateID: &deposit.ID})
+	}
+	// checking is over 50 on both days, savings only now
+	account("checking", 100, 150)
+	account("savings", 20, 200)
+
+	balance, _ := ListAccountFields.Find("balance")
+	overFifty := query.Comparison{Field: balance, Op: query.Greater, Value: int64(5000)}
+
+	testCase := func(action ListAccountAction, expectedNames []string, expectedBalances []models.Money) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, consequences := action.Execute()
+
+			assert.True(t, result.IsSuccessful)
+			names, balances := []string{}, []models.Money{}
+			for _, c := range consequences {
+				names = append(names, c.Object.(models.Account).Name)
+				balances = append(balances, c.Object.(models.Account).CurrentState.Balance)
+			}
+			assert.Equal(t, expectedNames, names)
+			assert.Equal(t, expectedBalances, balances)
+		}
+	}
+
+	mid := september.AddDate(0, 0, 15)
+	largest := query.Query{Filter: overFifty, Order: []query.OrderBy{{Field: balance, Desc: true}}, Limit: 1}
+
+	t.Run("filter now", testCase(ListAccountAction{Query: query.Query{Filter: overFifty}, Session: &s},
+		[]string{"checking", "savings"}, []models.Money{models.MakeMoney(150), models.MakeMoney(200)}))
+	t.Run("filter on", testCase(ListAccountAction{On: mid, Query: query.Query{Filter: overFifty}, Session: &s},
+		[]string{"checking"}, []models.Money{models.MakeMoney(100)}))
+	t.Run("order and limit now", testCase(ListAccountAction{Query: largest, Session: &s},
+		[]string{"savings"}, []models.Money{models.MakeMoney(200)}))
+	t.Run("order and limit on", testCase(ListAccountAction{On: mid, Query: largest, Session: &s},
+		[]string{"checking"}, []models.Money{models.MakeMoney(100)}))
+	t.Run("order on", testCase(ListAccountAction{On: mid, Query: query.Query{Order: []query.OrderBy{{Field: balance}}}, Session: &s},
+		[]string{"savings", "checking"}, []models.Money{models.MakeMoney(20), models.MakeMoney(100)}))
+}
+
+func TestListAccountOutputHelpers(t *testing.T) {
+	s := session.Session{}
+	expenses := models.MakeCategory("expenses", "", nil)
+	food := models.MakeCategory("food", "", &expenses)
+	savings := models.MakeCategory("savings", "", nil)
+
+	account := func(name string, category models.Category, balance float64) *actions.Consequence {
+		return &actions.Consequence{ConsequenceType: actions.READ, Object: models.Account{Name: name, Category: category, CurrentState: models.AccountState{Balance: models.MakeMoney(balance)}, Session: &s}}
+	}
+	consequences := []*actions.Consequence{
+		{ConsequenceType: actions.READ, Object: savings},
+		account("groceries", food, -12.5),
+		account("rainy day", savings, 100),
+		{ConsequenceType: actions.READ, Object: food},
+		account("cash", models.Category{}, 5),
+		account("rent", expenses, 800),
+	}
+
+	t.Run("table", func(t *testing.T) {
+		helpers := ListAccountOutput{}.Helpers(consequences)
+
+		assert.Len(t, helpers, 1)
+		table := helpers[0].(output.Table)
+		names := []string{}
+		for _, row := range table.Rows {
+			names = append(names, row[0].Text)
+		}
+		assert.Equal(t, []string{"cash", "rent", "groceries", "rainy day"}, names)
+		assert.Equal(t, "892.50", table.Footers[0][3].Text)
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		helpers := ListAccountOutput{Tree: true}.Helpers(consequences)
+
+		assert.Len(t, helpers, 2)
+		tree := helpers[0].(output.UnorderedList)
+		assert.Equal(t, []string{"cash: 5.00", "expenses/", "savings/"}, itemTexts(tree.Items))
+
+		expensesList := tree.Items[1].Children[0].(output.UnorderedList)
+		assert.Equal(t, 1, expensesList.Indent)
+		assert.Equal(t, []string{"rent: 800.00", "food/"}, itemTexts(expensesList.Items))
+
+		foodList := expensesList.Items[1].Children[0].(output.UnorderedList)
+		assert.Equal(t, 2, foodList.Indent)
+		assert.Equal(t, []string{"groceries: (12.50)"}, itemTexts(foodList.Items))
+		assert.Equal(t, output.Error, foodList.Items[0].Style.Color)
+
+		assert.Equal(t, []string{"rainy day: 100.00"}, itemTexts(tree.Items[2].Children[0].(output.UnorderedList).Items))
+		assert.Equal(t, "Total: 892.50", helpers[1].(output.Text).Text)
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		helpers := ListAccountOutput{IsOrdered: true}.Helpers(consequences)
+
+		names := []string{}
+		for _, row := range helpers[0].(output.Table).Rows {
+			names = append(names, row[0].Text)
+		}
+		assert.Equal(t, []string{"groceries", "rainy day", "cash", "rent"}, names)
+	})
+}
+
+func itemTexts(items []output.Text) []string {
+	texts := []string{}
+	for _, item := range items {
+		texts = append(texts, item.Text)
+	}
+	return texts
+}
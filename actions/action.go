@@ -2,6 +2,9 @@ package actions
 
 import (
 	"encoding/json"
+	"strings"
+
+	"samvasta.com/bujit/models"
 )
 
 type ActionType int
@@ -52,11 +55,47 @@ func (this ConsequenceType) String() string {
 	}
 }
 
+func (this ConsequenceType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToLower(this.String()))
+}
+
 type Consequence struct {
 	ConsequenceType ConsequenceType
 	Object          json.Marshaler
 }
 
+// ObjectKind names the type of model a consequence affected.
+func ObjectKind(object interface{}) string {
+	switch object.(type) {
+	case models.Account, *models.Account:
+		return "account"
+	case models.AccountState, *models.AccountState:
+		return "accountState"
+	case models.Category, *models.Category:
+		return "category"
+	case models.Transaction, *models.Transaction:
+		return "transaction"
+	case models.ScheduledTransaction, *models.ScheduledTransaction:
+		return "scheduledTransaction"
+	case models.Macro, *models.Macro:
+		return "macro"
+	default:
+		return "unknown"
+	}
+}
+
+func (c Consequence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ConsequenceType `json:"type"`
+		Kind   string          `json:"kind"`
+		Object json.Marshaler  `json:"object"`
+	}{
+		c.ConsequenceType,
+		ObjectKind(c.Object),
+		c.Object,
+	})
+}
+
 type ActionResult struct {
 	Output       interface{}
 	IsSuccessful bool
@@ -65,3 +104,33 @@ type ActionResult struct {
 type Actioner interface {
 	Execute() (ActionResult, []*Consequence)
 }
+
+type OutputFormat string
+
+const (
+	FormatTerminal OutputFormat = ""
+	FormatMarkdown OutputFormat = "md"
+	FormatHTML     OutputFormat = "html"
+)
+
+// FormattedOutput is the output of an action that should be rendered in a specific format instead of for the terminal
+type FormattedOutput struct {
+	Format OutputFormat
+	Output interface{}
+}
+
+// FormatAction runs another action and marks its output to be rendered in a specific format
+type FormatAction struct {
+	Action Actioner
+	Format OutputFormat
+}
+
+func (formatAction FormatAction) Execute() (ActionResult, []*Consequence) {
+	result, consequences := formatAction.Action.Execute()
+
+	if result.IsSuccessful {
+		result.Output = FormattedOutput{Format: formatAction.Format, Output: result.Output}
+	}
+
+	return result, consequences
+}
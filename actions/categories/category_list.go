@@ -0,0 +1,73 @@
+package actions_categories
+
+import (
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+)
+
+type ListCategoryAction struct {
+	Name    string
+	Query   query.Query
+	Session *session.Session
+}
+
+// ListCategoryFields can be used to filter and order the categories
+var ListCategoryFields = query.Fields{
+	{Name: "name", Column: "fully_qualified_name", Kind: query.Text},
+	{Name: "description", Column: "description", Kind: query.Text},
+	{Name: "created", Column: "created_at", Kind: query.Date},
+}
+
+type ListCategoryOutput struct{}
+
+func (action ListCategoryAction) IsValid() bool {
+	return action.Session != nil
+}
+
+func (action ListCategoryAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	consequences := []*actions.Consequence{}
+
+	tx := action.Query.Apply(action.Session.Db.Preload("SubCategories"))
+	if !action.Query.IsOrdered() {
+		tx = tx.Order("fully_qualified_name")
+	}
+	if action.Name != "" {
+		tx = tx.Where("fully_qualified_name LIKE ?", "%"+action.Name+"%")
+	}
+
+	var categories []models.Category
+	tx = tx.Find(&categories)
+
+	if tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	for _, c := range categories {
+		c.Session = action.Session
+		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: c})
+	}
+
+	return actions.ActionResult{Output: ListCategoryOutput{}, IsSuccessful: true}, consequences
+}
+
+// Helpers shows each category as a row
+func (lco ListCategoryOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	rows := [][]output.TableCell{}
+	for _, c := range consequences {
+		if category, ok := c.Object.(models.Category); ok {
+			rows = append(rows, []output.TableCell{
+				output.Cell(category.FullyQualifiedName),
+				output.Cell(category.Description),
+			})
+		}
+	}
+
+	columns := []output.TableColumn{
+		output.MakeColumn("Category", output.TextColumn),
+		output.MakeColumn("Description", output.TextColumn),
+	}
+	return output.EmptyOutputGroup().Table(columns, rows).ToSlice()
+}
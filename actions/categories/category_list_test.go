@@ -0,0 +1,40 @@
+package actions_categories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestListCategoryAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	food := models.MakeCategory("food", "", nil)
+	s.Db.Create(&food)
+	groceries := models.MakeCategory("groceries", "", &food)
+	s.Db.Create(&groceries)
+	rent := models.MakeCategory("rent", "", nil)
+	s.Db.Create(&rent)
+
+	testCase := func(action ListCategoryAction, expected []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, consequences := action.Execute()
+
+			assert.True(t, result.IsSuccessful)
+			assert.Equal(t, ListCategoryOutput{}, result.Output)
+
+			names := []string{}
+			for _, c := range consequences {
+				assert.Equal(t, actions.READ, c.ConsequenceType)
+				names = append(names, c.Object.(models.Category).FullyQualifiedName)
+			}
+			assert.Equal(t, expected, names)
+		}
+	}
+
+	t.Run("all", testCase(ListCategoryAction{Session: &s}, []string{"food", "food/groceries", "rent"}))
+	t.Run("name", testCase(ListCategoryAction{Name: "groc", Session: &s}, []string{"food/groceries"}))
+}
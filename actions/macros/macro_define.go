@@ -0,0 +1,61 @@
+package actions_macros
+
+import (
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// DefineMacroAction saves an alias or macro, replacing any existing definition with the same name in any case. The
+// parser checks the name and expansion before the action is made.
+type DefineMacroAction struct {
+	Name      string
+	Params    int
+	Expansion string
+	Session   *session.Session
+}
+
+func (action DefineMacroAction) IsValid() bool {
+	return action.Session != nil && action.Name != "" && action.Expansion != "" && action.Params >= 0
+}
+
+func (action DefineMacroAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	macro, found, err := findMacro(action.Session, action.Name)
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	consequenceType := actions.UPDATE
+	if !found {
+		consequenceType = actions.CREATE
+	}
+	macro.Name = action.Name
+	macro.Params = action.Params
+	macro.Expansion = action.Expansion
+
+	if tx := action.Session.Db.Save(&macro); tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	macro.Session = action.Session
+
+	return actions.ActionResult{Output: "", IsSuccessful: true}, []*actions.Consequence{
+		{ConsequenceType: consequenceType, Object: macro},
+	}
+}
+
+// findMacro finds the alias or macro with the name, ignoring case as the parser does when it expands one. found is false
+// when there is none.
+func findMacro(s *session.Session, name string) (macro models.Macro, found bool, err error) {
+	var macros []models.Macro
+	if err := s.Db.Find(&macros).Error; err != nil {
+		return macro, false, err
+	}
+	for _, m := range macros {
+		if strings.EqualFold(m.Name, name) {
+			return m, true, nil
+		}
+	}
+	return macro, false, nil
+}
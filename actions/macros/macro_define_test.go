@@ -0,0 +1,58 @@
+package actions_macros
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestDefineMacroAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	action := DefineMacroAction{Name: "owing", Expansion: "list account -c=credit -x=0", Session: &s}
+	assert.True(t, action.IsValid())
+
+	result, consequences := action.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 1)
+	assert.Equal(t, actions.CREATE, consequences[0].ConsequenceType)
+	assert.Equal(t, "alias owing = list account -c=credit -x=0", consequences[0].Object.(models.Macro).Definition())
+
+	// defining the same name again replaces it
+	result, consequences = DefineMacroAction{Name: "owing", Params: 1, Expansion: "list account -c=$1 -x=0", Session: &s}.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, actions.UPDATE, consequences[0].ConsequenceType)
+	assert.Equal(t, "macro owing $1 = list account -c=$1 -x=0", consequences[0].Object.(models.Macro).Definition())
+
+	var count int64
+	s.Db.Model(&models.Macro{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	assert.False(t, DefineMacroAction{Name: "empty", Session: &s}.IsValid())
+}
+
+func TestDefineMacroActionIgnoresCase(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	DefineMacroAction{Name: "coffee", Expansion: "new transaction 3 --from=cash", Session: &s}.Execute()
+	result, consequences := DefineMacroAction{Name: "Coffee", Expansion: "new transaction 4 --from=cash", Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, actions.UPDATE, consequences[0].ConsequenceType)
+
+	// the definition takes the name as it was last written
+	var macros []models.Macro
+	s.Db.Find(&macros)
+	if assert.Len(t, macros, 1) {
+		assert.Equal(t, "Coffee", macros[0].Name)
+		assert.Equal(t, "new transaction 4 --from=cash", macros[0].Expansion)
+	}
+
+	DefineMacroAction{Name: "Café", Expansion: "list account", Session: &s}.Execute()
+	result, consequences = DefineMacroAction{Name: "CAFÉ", Expansion: "list category", Session: &s}.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, actions.UPDATE, consequences[0].ConsequenceType)
+}
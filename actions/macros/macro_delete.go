@@ -0,0 +1,36 @@
+package actions_macros
+
+import (
+	"fmt"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/session"
+)
+
+type DeleteMacroAction struct {
+	Name    string
+	Session *session.Session
+}
+
+func (action DeleteMacroAction) IsValid() bool {
+	return action.Session != nil && action.Name != ""
+}
+
+func (action DeleteMacroAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	macro, found, err := findMacro(action.Session, action.Name)
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	if !found {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "No alias or macro with name '%s'"}`, action.Name), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	if tx := action.Session.Db.Delete(&macro); tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	macro.Session = action.Session
+
+	return actions.ActionResult{Output: "", IsSuccessful: true}, []*actions.Consequence{
+		{ConsequenceType: actions.DELETE, Object: macro},
+	}
+}
@@ -0,0 +1,43 @@
+package actions_macros
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestDeleteMacroAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	s.Db.Create(&models.Macro{Name: "owing", Expansion: "list account -c=credit -x=0"})
+
+	result, consequences := DeleteMacroAction{Name: "owing", Session: &s}.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 1)
+	assert.Equal(t, actions.DELETE, consequences[0].ConsequenceType)
+
+	var count int64
+	s.Db.Model(&models.Macro{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	result, consequences = DeleteMacroAction{Name: "owing", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No alias or macro with name 'owing'"}`, result.Output)
+	assert.Len(t, consequences, 0)
+}
+
+func TestDeleteMacroActionIgnoresCase(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	s.Db.Create(&models.Macro{Name: "Owing", Expansion: "list account -c=credit -x=0"})
+
+	result, _ := DeleteMacroAction{Name: "owing", Session: &s}.Execute()
+	assert.True(t, result.IsSuccessful)
+
+	var count int64
+	s.Db.Model(&models.Macro{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,54 @@
+package actions_macros
+
+import (
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+// ListMacroAction lists every alias and macro by name
+type ListMacroAction struct {
+	Session *session.Session
+}
+
+type ListMacroOutput struct{}
+
+func (action ListMacroAction) IsValid() bool {
+	return action.Session != nil
+}
+
+func (action ListMacroAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	var macros []models.Macro
+	tx := action.Session.Db.Order("name").Find(&macros)
+	if tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	consequences := []*actions.Consequence{}
+	for _, macro := range macros {
+		macro.Session = action.Session
+		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: macro})
+	}
+
+	return actions.ActionResult{Output: ListMacroOutput{}, IsSuccessful: true}, consequences
+}
+
+// Helpers shows each macro as a row with what it expands to
+func (lmo ListMacroOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	rows := [][]output.TableCell{}
+	for _, c := range consequences {
+		if macro, ok := c.Object.(models.Macro); ok {
+			rows = append(rows, []output.TableCell{
+				output.Cell(macro.Name),
+				output.Cell(macro.Definition()),
+			})
+		}
+	}
+
+	columns := []output.TableColumn{
+		output.MakeColumn("Name", output.TextColumn),
+		output.MakeColumn("Definition", output.TextColumn),
+	}
+	return output.EmptyOutputGroup().Table(columns, rows).ToSlice()
+}
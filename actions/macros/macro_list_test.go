@@ -0,0 +1,29 @@
+package actions_macros
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestListMacroAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	s.Db.Create(&models.Macro{Name: "owing", Expansion: "list account -c=credit -x=0"})
+	s.Db.Create(&models.Macro{Name: "coffee", Params: 1, Expansion: "new transaction $1 --from=cash"})
+
+	result, consequences := ListMacroAction{Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, ListMacroOutput{}, result.Output)
+
+	names := []string{}
+	for _, c := range consequences {
+		assert.Equal(t, actions.READ, c.ConsequenceType)
+		names = append(names, c.Object.(models.Macro).Name)
+	}
+	assert.Equal(t, []string{"coffee", "owing"}, names)
+}
@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+// HelperOutput is the output of an action that can be shown as output helpers, whichever format it is rendered in
+type HelperOutput interface {
+	Helpers(consequences []*Consequence) []output.Helper
+}
+
+// RawOutput is the output of an action that a terminal shows exactly as it is, so it can be copied or redirected to a
+// file
+type RawOutput interface {
+	RawText() string
+}
+
+// Helpers converts the output of an action into output helpers so it can be rendered. ok is false when the output has
+// no helper representation.
+func Helpers(item interface{}, consequences []*Consequence) (helpers []output.Helper, ok bool) {
+	switch i := item.(type) {
+	case []output.Helper:
+		return i, true
+	case FormattedOutput:
+		return Helpers(i.Output, consequences)
+	case output.Helper:
+		return []output.Helper{i}, true
+	case HelperOutput:
+		return i.Helpers(consequences), true
+	case string:
+		if i == "" {
+			return []output.Helper{}, true
+		}
+		return output.EmptyOutputGroup().Paragraph(i).ToSlice(), true
+	case nil:
+		return []output.Helper{}, true
+	default:
+		return nil, false
+	}
+}
+
+// MoneyCell formats the value as a table cell, colored as an error when negative.
+func MoneyCell(m models.Money, s *session.Session) output.TableCell {
+	if m.IsNegative() {
+		return output.ColoredCell(m.String(s), output.Error)
+	}
+	return output.Cell(m.String(s))
+}
+
+// Plural is a count of things, e.g. "1 account" or "3 categories"
+func Plural(count int, kind string) string {
+	switch {
+	case count == 1:
+		return fmt.Sprintf("%d %s", count, kind)
+	case strings.HasSuffix(kind, "y"):
+		return fmt.Sprintf("%d %sies", count, strings.TrimSuffix(kind, "y"))
+	default:
+		return fmt.Sprintf("%d %ss", count, kind)
+	}
+}
+
+// ConsequenceSession finds the session of the first consequence that has one, or an empty session if none do.
+func ConsequenceSession(consequences []*Consequence) *session.Session {
+	for _, c := range consequences {
+		if sessioner, ok := c.Object.(session.Sessioner); ok && sessioner.GetSession() != nil {
+			return sessioner.GetSession()
+		}
+	}
+	return &session.Session{}
+}
@@ -0,0 +1,100 @@
+package actions_pipeline
+
+import (
+	"strings"
+	"time"
+
+	"samvasta.com/bujit/models"
+)
+
+// field is a named value of a model. Value is a string, models.Money or time.Time.
+type field struct {
+	Name  string
+	Value interface{}
+}
+
+// fieldsOf lists the values of a model that stages sort by, sum and export. The names match the fields that the list
+// commands filter and order by. Models that stages cannot work with have none.
+func fieldsOf(object interface{}) []field {
+	switch o := object.(type) {
+	case models.Account:
+		category := o.Category.FullyQualifiedName
+		if category == "" {
+			category = o.Category.Name
+		}
+		return []field{
+			{"name", o.Name},
+			{"description", o.Description},
+			{"category", category},
+			{"balance", o.Balance()},
+			{"created", time.Unix(o.CreatedAt, 0).UTC()},
+			{"updated", time.Unix(o.CurrentState.EffectiveAt, 0).UTC()},
+		}
+	case models.Transaction:
+		from, to := "", ""
+		if o.SourceExists() {
+			from = o.Source.Name
+		}
+		if o.DestinationExists() {
+			to = o.Destination.Name
+		}
+		return []field{
+			{"date", time.Unix(o.EffectiveAt, 0).UTC()},
+			{"amount", o.Change},
+			{"from", from},
+			{"to", to},
+			{"memo", o.Memo},
+		}
+	case models.ScheduledTransaction:
+		from, to := "", ""
+		if o.Source != nil {
+			from = o.Source.Name
+		}
+		if o.Destination != nil {
+			to = o.Destination.Name
+		}
+		return []field{
+			{"next", time.Unix(o.NextDate, 0).UTC()},
+			{"amount", o.Change},
+			{"from", from},
+			{"to", to},
+			{"memo", o.Memo},
+		}
+	case models.Category:
+		return []field{
+			{"name", o.FullyQualifiedName},
+			{"description", o.Description},
+			{"created", time.Unix(o.CreatedAt, 0).UTC()},
+		}
+	case models.Macro:
+		return []field{
+			{"name", o.Name},
+			{"definition", o.Definition()},
+		}
+	default:
+		return []field{}
+	}
+}
+
+// fieldValue is the value of the named field of a model
+func fieldValue(object interface{}, name string) (interface{}, bool) {
+	for _, f := range fieldsOf(object) {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// less compares two values of the same field. Text is compared without case.
+func less(a, b interface{}) bool {
+	switch a := a.(type) {
+	case string:
+		return strings.ToLower(a) < strings.ToLower(b.(string))
+	case models.Money:
+		return a < b.(models.Money)
+	case time.Time:
+		return a.Before(b.(time.Time))
+	}
+	return false
+}
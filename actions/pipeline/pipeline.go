@@ -0,0 +1,84 @@
+package actions_pipeline
+
+import (
+	"fmt"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+)
+
+// Stage works on what the command before it in a pipeline did, e.g. the "sum" in "list account | sum"
+type Stage interface {
+	Apply(result actions.ActionResult, consequences []*actions.Consequence) (actions.ActionResult, []*actions.Consequence)
+}
+
+// PipeAction runs an action and passes its result and consequences through a stage. Nothing is passed on when the
+// action fails.
+type PipeAction struct {
+	Action actions.Actioner
+	Stage  Stage
+}
+
+func (pipe PipeAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	result, consequences := pipe.Action.Execute()
+	if !result.IsSuccessful {
+		return result, consequences
+	}
+	return pipe.Stage.Apply(result, consequences)
+}
+
+// Step is the outcome of one command in a sequence
+type Step struct {
+	Result       actions.ActionResult
+	Consequences []*actions.Consequence
+}
+
+type SequenceOutput struct {
+	Steps []Step
+}
+
+// Helpers shows the output of each command of a sequence in turn
+func (so SequenceOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	helpers := []output.Helper{}
+	for _, step := range so.Steps {
+		stepHelpers, ok := actions.Helpers(step.Result.Output, step.Consequences)
+		if !ok {
+			stepHelpers = output.EmptyOutputGroup().Paragraph(fmt.Sprint(step.Result.Output)).ToSlice()
+		}
+		helpers = append(helpers, stepHelpers...)
+	}
+	return helpers
+}
+
+// SequenceAction runs actions one after the other, as in "new account cash; list account". It stops at the first
+// action that fails.
+type SequenceAction struct {
+	Actions []actions.Actioner
+}
+
+func (sequence SequenceAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	sequenceOutput := SequenceOutput{Steps: []Step{}}
+	consequences := []*actions.Consequence{}
+
+	for _, action := range sequence.Actions {
+		result, stepConsequences := action.Execute()
+		sequenceOutput.Steps = append(sequenceOutput.Steps, Step{result, stepConsequences})
+		consequences = append(consequences, stepConsequences...)
+
+		if !result.IsSuccessful {
+			return actions.ActionResult{Output: sequenceOutput, IsSuccessful: false}, consequences
+		}
+	}
+
+	return actions.ActionResult{Output: sequenceOutput, IsSuccessful: true}, consequences
+}
+
+// IsExit is true when the sequence ends the session once it has run
+func (sequence SequenceAction) IsExit() bool {
+	for _, action := range sequence.Actions {
+		if _, ok := action.(actions.ExitAction); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package actions_pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestSequenceAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	sequence := SequenceAction{Actions: []actions.Actioner{
+		actions_accounts.CreateAccountAction{Name: "cash", Session: &s},
+		actions_accounts.ListAccountAction{Session: &s},
+	}}
+	result, consequences := sequence.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	steps := result.Output.(SequenceOutput).Steps
+	assert.Len(t, steps, 2)
+	assert.Equal(t, actions_accounts.ListAccountOutput{}, steps[1].Result.Output)
+	assert.Equal(t, "cash", steps[1].Consequences[0].Object.(models.Account).Name)
+	assert.Len(t, consequences, len(steps[0].Consequences)+len(steps[1].Consequences))
+
+	// a failure stops the sequence
+	sequence = SequenceAction{Actions: []actions.Actioner{
+		actions_accounts.CreateAccountAction{Name: "cash", Session: &s},
+		actions_accounts.CreateAccountAction{Name: "savings", Session: &s},
+	}}
+	result, _ = sequence.Execute()
+
+	assert.False(t, result.IsSuccessful)
+	assert.Len(t, result.Output.(SequenceOutput).Steps, 1)
+	var count int64
+	s.Db.Model(&models.Account{}).Where("name = ?", "savings").Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	assert.True(t, SequenceAction{Actions: []actions.Actioner{actions.VersionAction{}, actions.ExitAction{}}}.IsExit())
+	assert.False(t, sequence.IsExit())
+}
+
+func TestPipeAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.Db.Create(&models.Account{Name: "cash", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(20)}})
+	s.Db.Create(&models.Account{Name: "visa", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(-5)}})
+
+	result, consequences := PipeAction{Action: actions_accounts.ListAccountAction{Session: &s}, Stage: SumStage{}}.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, SumOutput{Total: models.MakeMoney(15), Count: 2, Kind: "account", Field: "balance"}, result.Output)
+	assert.Len(t, consequences, 2)
+
+	// nothing is passed on from an action that fails
+	result, _ = PipeAction{Action: actions_accounts.DeleteAccountAction{Name: "missing", Session: &s}, Stage: CountStage{}}.Execute()
+	assert.False(t, result.IsSuccessful)
+	_, isCount := result.Output.(CountOutput)
+	assert.False(t, isCount)
+}
@@ -0,0 +1,210 @@
+package actions_pipeline
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/util"
+)
+
+// kindOf names the models in the consequences, e.g. "account", or "item" when there are none
+func kindOf(consequences []*actions.Consequence) string {
+	if len(consequences) == 0 {
+		return "item"
+	}
+	return actions.ObjectKind(consequences[0].Object)
+}
+
+type CountOutput struct {
+	Count int
+	Kind  string
+}
+
+func (co CountOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	return output.EmptyOutputGroup().Paragraph(actions.Plural(co.Count, co.Kind)).ToSlice()
+}
+
+// CountStage counts what the command before it listed or changed
+type CountStage struct{}
+
+func (stage CountStage) Apply(result actions.ActionResult, consequences []*actions.Consequence) (actions.ActionResult, []*actions.Consequence) {
+	return actions.ActionResult{Output: CountOutput{Count: len(consequences), Kind: kindOf(consequences)}, IsSuccessful: true}, consequences
+}
+
+type SumOutput struct {
+	Total models.Money
+	Count int // how many amounts were added up
+	Kind  string
+	Field string // the field that was added up, e.g. "balance"
+}
+
+func (so SumOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	s := actions.ConsequenceSession(consequences)
+
+	group := output.EmptyOutputGroup()
+	if so.Total.IsNegative() {
+		group.PushStyle(output.TextStyle{Color: output.Error})
+	}
+	if so.Count == 0 {
+		return group.Paragraph(fmt.Sprintf("Total: %s", so.Total.String(s))).ToSlice()
+	}
+	return group.Paragraph(fmt.Sprintf("Total %s of %s: %s", so.Field, actions.Plural(so.Count, so.Kind), so.Total.String(s))).ToSlice()
+}
+
+// SumStage adds up the balances of accounts or the amounts of transactions
+type SumStage struct{}
+
+func (stage SumStage) Apply(result actions.ActionResult, consequences []*actions.Consequence) (actions.ActionResult, []*actions.Consequence) {
+	sum := SumOutput{Kind: kindOf(consequences)}
+
+	for _, c := range consequences {
+		for _, f := range fieldsOf(c.Object) {
+			if amount, ok := f.Value.(models.Money); ok {
+				sum.Total += amount
+				sum.Count++
+				sum.Field = f.Name
+				break
+			}
+		}
+	}
+
+	if sum.Count == 0 && len(consequences) > 0 {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "There is nothing to sum in a list of %s"}`, sum.Kind), IsSuccessful: false}, consequences
+	}
+	return actions.ActionResult{Output: sum, IsSuccessful: true}, consequences
+}
+
+// SortStage puts what the command before it listed in order of a field, such as "balance" or "date". Anything without
+// the field goes last.
+type SortStage struct {
+	Field string
+	Desc  bool
+}
+
+func (stage SortStage) Apply(result actions.ActionResult, consequences []*actions.Consequence) (actions.ActionResult, []*actions.Consequence) {
+	sorted := make([]*actions.Consequence, len(consequences))
+	copy(sorted, consequences)
+
+	hasField := false
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, okA := fieldValue(sorted[i].Object, stage.Field)
+		b, okB := fieldValue(sorted[j].Object, stage.Field)
+		hasField = hasField || okA || okB
+		if !okA || !okB {
+			return okA
+		}
+		if stage.Desc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	if len(sorted) > 1 && !hasField {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "A list of %s cannot be sorted by '%s'"}`, kindOf(consequences), stage.Field), IsSuccessful: false}, consequences
+	}
+
+	if listAccount, ok := result.Output.(actions_accounts.ListAccountOutput); ok {
+		// accounts are otherwise shown grouped by category
+		listAccount.IsOrdered = true
+		result.Output = listAccount
+	}
+	return result, sorted
+}
+
+type ExportOutput struct {
+	Format string `json:"format"`
+	Text   string `json:"text"`
+}
+
+func (eo ExportOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	return output.EmptyOutputGroup().Paragraph(eo.Text).ToSlice()
+}
+
+// RawText is the export as it was written, so it can be copied or redirected to a file
+func (eo ExportOutput) RawText() string {
+	return eo.Text
+}
+
+// ExportStage writes what the command before it listed as csv or json
+type ExportStage struct {
+	Format string // csv or json
+}
+
+func (stage ExportStage) Apply(result actions.ActionResult, consequences []*actions.Consequence) (actions.ActionResult, []*actions.Consequence) {
+	var text string
+	var err error
+	if stage.Format == "json" {
+		text, err = exportJSON(consequences)
+	} else {
+		text, err = exportCSV(consequences)
+	}
+
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, consequences
+	}
+	return actions.ActionResult{Output: ExportOutput{Format: stage.Format, Text: text}, IsSuccessful: true}, consequences
+}
+
+// exportCSV writes a header row with the field names of the first model, then a row for each model of the same kind
+func exportCSV(consequences []*actions.Consequence) (string, error) {
+	buffer := bytes.Buffer{}
+	writer := csv.NewWriter(&buffer)
+
+	kind := kindOf(consequences)
+	for i, c := range consequences {
+		if actions.ObjectKind(c.Object) != kind {
+			continue
+		}
+		fields := fieldsOf(c.Object)
+		if i == 0 {
+			header := make([]string, len(fields))
+			for j, f := range fields {
+				header[j] = f.Name
+			}
+			writer.Write(header)
+		}
+
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			row[j] = csvValue(f.Value)
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return buffer.String(), writer.Error()
+}
+
+// csvValue writes amounts as plain decimals and times as days, so spreadsheets can read them
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case models.Money:
+		sign := ""
+		if v.IsNegative() {
+			sign = "-"
+		}
+		return fmt.Sprintf("%s%d.%02d", sign, util.AbsI64(v.Dollars()), util.AbsI64(v.Cents()))
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func exportJSON(consequences []*actions.Consequence) (string, error) {
+	objects := make([]json.Marshaler, len(consequences))
+	for i, c := range consequences {
+		objects[i] = c.Object
+	}
+
+	b, err := json.MarshalIndent(objects, "", "  ")
+	return string(b) + "\n", err
+}
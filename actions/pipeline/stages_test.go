@@ -0,0 +1,99 @@
+package actions_pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func readAll(objects ...interface{}) []*actions.Consequence {
+	consequences := []*actions.Consequence{}
+	for _, object := range objects {
+		switch o := object.(type) {
+		case models.Account:
+			consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: o})
+		case models.Transaction:
+			consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: o})
+		case models.Macro:
+			consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: o})
+		}
+	}
+	return consequences
+}
+
+func names(consequences []*actions.Consequence) []string {
+	names := []string{}
+	for _, c := range consequences {
+		names = append(names, c.Object.(models.Account).Name)
+	}
+	return names
+}
+
+var listed = actions.ActionResult{Output: actions_accounts.ListAccountOutput{}, IsSuccessful: true}
+
+func TestCountStage(t *testing.T) {
+	result, _ := CountStage{}.Apply(listed, readAll(models.Account{Name: "cash"}, models.Account{Name: "visa"}))
+	assert.Equal(t, CountOutput{Count: 2, Kind: "account"}, result.Output)
+
+	result, _ = CountStage{}.Apply(listed, readAll())
+	assert.Equal(t, CountOutput{Count: 0, Kind: "item"}, result.Output)
+}
+
+func TestSumStage(t *testing.T) {
+	result, _ := SumStage{}.Apply(listed, readAll(
+		models.Transaction{Change: models.MakeMoney(4.5)},
+		models.Transaction{Change: models.MakeMoney(10)},
+	))
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, SumOutput{Total: models.MakeMoney(14.5), Count: 2, Kind: "transaction", Field: "amount"}, result.Output)
+
+	result, _ = SumStage{}.Apply(listed, readAll(models.Macro{Name: "owing"}))
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "There is nothing to sum in a list of macro"}`, result.Output)
+}
+
+func TestSortStage(t *testing.T) {
+	accounts := readAll(
+		models.Account{Name: "visa", CurrentState: models.AccountState{Balance: models.MakeMoney(-5)}},
+		models.Account{Name: "Cash", CurrentState: models.AccountState{Balance: models.MakeMoney(20)}},
+		models.Account{Name: "savings", CurrentState: models.AccountState{Balance: models.MakeMoney(1000)}},
+	)
+
+	result, sorted := SortStage{Field: "balance", Desc: true}.Apply(listed, accounts)
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, []string{"savings", "Cash", "visa"}, names(sorted))
+	assert.Equal(t, actions_accounts.ListAccountOutput{IsOrdered: true}, result.Output)
+
+	_, sorted = SortStage{Field: "name"}.Apply(listed, accounts)
+	assert.Equal(t, []string{"Cash", "savings", "visa"}, names(sorted))
+
+	// the consequences of the command before are left alone
+	assert.Equal(t, []string{"visa", "Cash", "savings"}, names(accounts))
+
+	result, _ = SortStage{Field: "memo"}.Apply(listed, accounts)
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "A list of account cannot be sorted by 'memo'"}`, result.Output)
+}
+
+func TestExportStage(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	transactions := readAll(
+		models.Transaction{EffectiveAt: 1792368000, Change: models.MakeMoney(-4.5), Destination: &models.Account{Name: "dining"}, Memo: "coffee, large", Session: &s},
+		models.Transaction{EffectiveAt: 1792454400, Change: models.MakeMoney(1200), Source: &models.Account{Name: "work"}, Session: &s},
+	)
+
+	result, consequences := ExportStage{Format: "csv"}.Apply(listed, transactions)
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, ExportOutput{Format: "csv", Text: "date,amount,from,to,memo\n" +
+		"2026-10-19,-4.50,,dining,\"coffee, large\"\n" +
+		"2026-10-20,1200.00,work,,\n"}, result.Output)
+	assert.Equal(t, transactions, consequences)
+
+	result, _ = ExportStage{Format: "json"}.Apply(listed, transactions[:1])
+	assert.True(t, result.IsSuccessful)
+	assert.Contains(t, result.Output.(ExportOutput).Text, `"memo": "coffee, large"`)
+}
@@ -0,0 +1,199 @@
+package actions_reports
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+type ForecastAction struct {
+	AccountName   string
+	Days          int
+	AverageMonths int // Months of history used to estimate average daily activity. 0 means only scheduled transactions are used
+	Threshold     models.Money
+	Today         time.Time // Day the forecast starts from. Defaults to the current date
+	Session       *session.Session
+}
+
+type ForecastPoint struct {
+	Date    time.Time
+	Balance models.Money
+}
+
+type ForecastOutput struct {
+	AccountName  string
+	Threshold    models.Money
+	AverageDaily models.Money
+	Points       []ForecastPoint
+	FirstBelow   *ForecastPoint // First day the balance drops below the threshold, if any
+}
+
+func (action ForecastAction) IsValid() bool {
+	return action.Session != nil && action.AccountName != "" && action.Days > 0 && action.AverageMonths >= 0
+}
+
+func (action ForecastAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+
+	var account models.Account
+	tx := action.Session.Db.Joins("CurrentState").Where("accounts.Name = ?", action.AccountName).Limit(1).Find(&account)
+
+	if tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	if tx.RowsAffected == 0 {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "No account with name '%s'"}`, action.AccountName), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	account.Session = action.Session
+
+	today := action.Today
+	if today.IsZero() {
+		today = util.Today()
+	}
+	today = util.Day(today)
+	end := today.AddDate(0, 0, action.Days+1)
+
+	averageDaily, err := action.averageDailyChange(account.ID, today)
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	scheduledChanges, err := action.scheduledChanges(account.ID, today.AddDate(0, 0, 1), end)
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	// The current balance already includes changes dated after today, so each day starts from the balance it has by
+	// the dates the changes take effect. The balance at the end of a day is the balance at the start of the next.
+	ends := make([]time.Time, action.Days+1)
+	for day := range ends {
+		ends[day] = today.AddDate(0, 0, day+1)
+	}
+	balances, err := account.BalancesAt(ends)
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	forecast := ForecastOutput{
+		AccountName:  account.Name,
+		Threshold:    action.Threshold,
+		AverageDaily: models.Money(math.Round(averageDaily)),
+	}
+
+	var scheduledTotal int64 = 0
+	for day := 0; day <= action.Days; day++ {
+		date := today.AddDate(0, 0, day)
+		scheduledTotal += scheduledChanges[date].Value()
+		balance := models.Money(balances[day].Value() + scheduledTotal + int64(math.Round(averageDaily*float64(day))))
+
+		point := ForecastPoint{Date: date, Balance: balance}
+		forecast.Points = append(forecast.Points, point)
+
+		if forecast.FirstBelow == nil && balance < action.Threshold {
+			forecast.FirstBelow = &point
+		}
+	}
+
+	consequences := []*actions.Consequence{
+		{ConsequenceType: actions.READ, Object: account},
+	}
+
+	return actions.ActionResult{Output: forecast, IsSuccessful: true}, consequences
+}
+
+// averageDailyChange returns the average net change per day to the account's balance over the last AverageMonths months
+func (action ForecastAction) averageDailyChange(accountID uint, today time.Time) (float64, error) {
+	if action.AverageMonths == 0 {
+		return 0, nil
+	}
+
+	start := today.AddDate(0, -action.AverageMonths, 0)
+
+	var transactions []models.Transaction
+	tx := action.Session.Db.
+		Where("(source_id = ? OR destination_id = ?) AND effective_at >= ? AND effective_at < ?", accountID, accountID, start.Unix(), today.Unix()).
+		Find(&transactions)
+
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	var total int64 = 0
+	for _, t := range transactions {
+		total += t.ChangeFor(accountID).Value()
+	}
+
+	days := today.Sub(start).Hours() / 24
+	return float64(total) / days, nil
+}
+
+// scheduledChanges returns the net change to the account's balance from scheduled transactions, keyed by day
+func (action ForecastAction) scheduledChanges(accountID uint, from, to time.Time) (map[time.Time]models.Money, error) {
+	var scheduled []models.ScheduledTransaction
+	tx := action.Session.Db.Where("source_id = ? OR destination_id = ?", accountID, accountID).Find(&scheduled)
+
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	changes := make(map[time.Time]models.Money)
+	for _, st := range scheduled {
+		for _, date := range st.Occurrences(from, to) {
+			day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+			changes[day] += st.ChangeFor(accountID)
+		}
+	}
+	return changes, nil
+}
+
+// Helpers summarises the forecast, then charts the balance each day and lists the days where it changes
+func (fo ForecastOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	s := actions.ConsequenceSession(consequences)
+
+	group := output.EmptyOutputGroup().
+		Header(fmt.Sprintf("Forecast for %s", fo.AccountName))
+
+	if fo.AverageDaily != 0 {
+		group.Paragraph(fmt.Sprintf("Average daily change: %s", fo.AverageDaily.String(s)))
+	}
+
+	if fo.FirstBelow != nil {
+		group.PushStyle(output.TextStyle{Color: output.Error, IsBold: true}).
+			Paragraph(fmt.Sprintf("Balance drops below %s on %s (%s)", fo.Threshold.String(s), fo.FirstBelow.Date.Format("2006-01-02"), fo.FirstBelow.Balance.String(s))).
+			PopStyle()
+	} else {
+		group.PushStyle(output.TextStyle{Color: output.Success}).
+			Paragraph(fmt.Sprintf("Balance stays above %s", fo.Threshold.String(s))).
+			PopStyle()
+	}
+
+	values := make([]float64, len(fo.Points))
+	for i, point := range fo.Points {
+		values[i] = float64(point.Balance.Value()) / 100
+	}
+	group.Sparkline("Trend", values)
+
+	// Only show the days where the balance changes
+	rows := [][]output.TableCell{}
+	for i, point := range fo.Points {
+		if i == 0 || i == len(fo.Points)-1 || point.Balance != fo.Points[i-1].Balance {
+			balance := actions.MoneyCell(point.Balance, s)
+			if point.Balance < fo.Threshold && !point.Balance.IsNegative() {
+				balance.Color = output.Warning
+			}
+			rows = append(rows, []output.TableCell{output.Cell(point.Date.Format("2006-01-02")), balance})
+		}
+	}
+	group.Table([]output.TableColumn{
+		output.MakeColumn("Date", output.DateColumn),
+		output.MakeColumn("Balance", output.MoneyColumn),
+	}, rows)
+
+	return group.ToSlice()
+}
@@ -0,0 +1,154 @@
+package actions_reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestForecastAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	today := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	opened := today.AddDate(0, -3, 0).Unix()
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100), EffectiveAt: opened}}
+	savings := models.Account{Name: "savings", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(1000), EffectiveAt: opened}}
+	s.Db.Create(&checking)
+	s.Db.Create(&savings)
+
+	// Rent leaves checking every 7 days starting in 2 days
+	s.Db.Create(&models.ScheduledTransaction{
+		NextDate:     today.AddDate(0, 0, 2).Unix(),
+		IntervalDays: 7,
+		Change:       models.MakeMoney(40),
+		SourceID:     &checking.ID,
+	})
+	// One-off transfer into checking in 5 days
+	s.Db.Create(&models.ScheduledTransaction{
+		NextDate:      today.AddDate(0, 0, 5).Unix(),
+		Change:        models.MakeMoney(25),
+		SourceID:      &savings.ID,
+		DestinationID: &checking.ID,
+	})
+
+	action := ForecastAction{AccountName: "checking", Days: 10, Threshold: models.MakeMoney(50), Today: today, Session: &s}
+	result, consequences := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 1)
+	assert.Equal(t, actions.READ, consequences[0].ConsequenceType)
+
+	forecast := result.Output.(ForecastOutput)
+	assert.Len(t, forecast.Points, 11)
+	assert.Equal(t, models.MakeMoney(100), forecast.Points[0].Balance)
+	assert.Equal(t, models.MakeMoney(60), forecast.Points[2].Balance)
+	assert.Equal(t, models.MakeMoney(85), forecast.Points[5].Balance)
+	assert.Equal(t, models.MakeMoney(45), forecast.Points[9].Balance)
+	assert.Equal(t, models.MakeMoney(45), forecast.Points[10].Balance)
+
+	assert.NotNil(t, forecast.FirstBelow)
+	assert.Equal(t, today.AddDate(0, 0, 9), forecast.FirstBelow.Date)
+}
+
+func TestForecastActionWithAverageSpending(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	today := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100), EffectiveAt: today.AddDate(0, -3, 0).Unix()}}
+	s.Db.Create(&checking)
+
+	// 28 days of history in February, $56 spent in total = $2 per day
+	s.Db.Create(&models.Transaction{CreatedAt: today.AddDate(0, 0, -10).Unix(), Change: models.MakeMoney(56), SourceID: &checking.ID})
+	// Outside the averaging window
+	s.Db.Create(&models.Transaction{CreatedAt: today.AddDate(0, -2, 0).Unix(), Change: models.MakeMoney(1000), SourceID: &checking.ID})
+
+	action := ForecastAction{AccountName: "checking", Days: 30, AverageMonths: 1, Threshold: models.MakeMoney(75), Today: today, Session: &s}
+	result, _ := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+
+	forecast := result.Output.(ForecastOutput)
+	assert.Equal(t, models.MakeMoney(-2), forecast.AverageDaily)
+	assert.Equal(t, models.MakeMoney(80), forecast.Points[10].Balance)
+	assert.Equal(t, today.AddDate(0, 0, 13), forecast.FirstBelow.Date)
+}
+
+func TestForecastActionWithFutureDatedTransactions(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	today := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// the current balance already includes the $40 that leaves the account in 3 days
+	opened := models.AccountState{Balance: models.MakeMoney(100), EffectiveAt: today.AddDate(0, -3, 0).Unix()}
+	s.Db.Create(&opened)
+	spent := models.AccountState{Balance: models.MakeMoney(60), EffectiveAt: today.AddDate(0, 0, 3).Unix(), PrevStateID: &opened.ID}
+	s.Db.Create(&spent)
+	checking := models.Account{Name: "checking", IsActive: true, CurrentStateID: &spent.ID}
+	s.Db.Create(&checking)
+	s.Db.Create(&models.Transaction{EffectiveAt: today.AddDate(0, 0, 3).Unix(), Change: models.MakeMoney(40), SourceID: &checking.ID})
+
+	action := ForecastAction{AccountName: "checking", Days: 5, Threshold: models.MakeMoney(75), Today: today, Session: &s}
+	result, _ := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+
+	forecast := result.Output.(ForecastOutput)
+	assert.Equal(t, models.MakeMoney(100), forecast.Points[0].Balance)
+	assert.Equal(t, models.MakeMoney(100), forecast.Points[2].Balance)
+	assert.Equal(t, models.MakeMoney(60), forecast.Points[3].Balance)
+	assert.Equal(t, today.AddDate(0, 0, 3), forecast.FirstBelow.Date)
+}
+
+func TestForecastActionWithFutureDatedStates(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	today := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// the balance was corrected down to 30 from tomorrow without a transaction
+	opened := models.AccountState{Balance: models.MakeMoney(100), EffectiveAt: today.AddDate(0, -3, 0).Unix()}
+	s.Db.Create(&opened)
+	corrected := models.AccountState{Balance: models.MakeMoney(30), EffectiveAt: today.AddDate(0, 0, 1).Unix(), PrevStateID: &opened.ID}
+	s.Db.Create(&corrected)
+	s.Db.Create(&models.Account{Name: "checking", IsActive: true, CurrentStateID: &corrected.ID})
+
+	action := ForecastAction{AccountName: "checking", Days: 3, Threshold: models.MakeMoney(50), Today: today, Session: &s}
+	result, _ := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+
+	forecast := result.Output.(ForecastOutput)
+	assert.Equal(t, models.MakeMoney(100), forecast.Points[0].Balance)
+	assert.Equal(t, models.MakeMoney(30), forecast.Points[1].Balance)
+	assert.Equal(t, models.MakeMoney(30), forecast.Points[3].Balance)
+	assert.Equal(t, today.AddDate(0, 0, 1), forecast.FirstBelow.Date)
+}
+
+func TestForecastActionNeverBelowThreshold(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100)}}
+	s.Db.Create(&checking)
+
+	action := ForecastAction{AccountName: "checking", Days: 30, Threshold: models.MakeMoney(0), Session: &s}
+	result, _ := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Nil(t, result.Output.(ForecastOutput).FirstBelow)
+}
+
+func TestForecastActionAccountDoesNotExist(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	action := ForecastAction{AccountName: "Does not exist", Days: 30, Session: &s}
+	result, consequences := action.Execute()
+
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No account with name 'Does not exist'"}`, result.Output)
+	assert.Len(t, consequences, 0)
+}
@@ -0,0 +1,164 @@
+package actions_reports
+
+import (
+	"fmt"
+	"time"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// TrendAction looks back at how the balance of an account, or the total balance of the accounts in a category and its
+// sub categories, changed day by day. Exactly one of AccountName and CategoryName is set.
+type TrendAction struct {
+	AccountName  string
+	CategoryName string
+	Days         int
+	Today        time.Time // Last day of the trend. Defaults to the current date
+	Session      *session.Session
+}
+
+type TrendPoint struct {
+	Date    time.Time
+	Balance models.Money // at the end of the day
+}
+
+type TrendOutput struct {
+	Name       string // of the account or category
+	IsCategory bool
+	Points     []TrendPoint // oldest first
+}
+
+func (action TrendAction) IsValid() bool {
+	return action.Session != nil && action.Days > 0 && (action.AccountName == "") != (action.CategoryName == "")
+}
+
+// Execute reads the accounts of the trend. Each account is a consequence with the balance it had at the end of the last
+// day.
+func (action TrendAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	accounts, name, result, ok := action.accounts()
+	if !ok {
+		return result, []*actions.Consequence{}
+	}
+
+	today := action.Today
+	if today.IsZero() {
+		today = util.Today()
+	}
+	today = util.Day(today)
+
+	// the balance at the end of a day is the balance at the start of the next
+	ends := make([]time.Time, action.Days)
+	for i := range ends {
+		ends[i] = today.AddDate(0, 0, i-action.Days+2)
+	}
+
+	trend := TrendOutput{Name: name, IsCategory: action.CategoryName != "", Points: make([]TrendPoint, action.Days)}
+	for i, end := range ends {
+		trend.Points[i].Date = end.AddDate(0, 0, -1)
+	}
+
+	consequences := []*actions.Consequence{}
+	for _, account := range accounts {
+		account.Session = action.Session
+		balances, err := account.BalancesAt(ends)
+		if err != nil {
+			return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+		}
+		for i, balance := range balances {
+			trend.Points[i].Balance += balance
+		}
+
+		account.CurrentState.Balance = balances[len(balances)-1]
+		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: account})
+	}
+
+	return actions.ActionResult{Output: trend, IsSuccessful: true}, consequences
+}
+
+// accounts finds the account, or the accounts in the category, by exact name. ok is false, with a result describing
+// the problem, when there is no such account or category.
+func (action TrendAction) accounts() (accounts []models.Account, name string, result actions.ActionResult, ok bool) {
+	if action.AccountName != "" {
+		tx := action.Session.Db.Joins("CurrentState").Where("accounts.name = ?", action.AccountName).Limit(1).Find(&accounts)
+		if tx.Error != nil {
+			return nil, "", actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, false
+		}
+		if len(accounts) == 0 {
+			return nil, "", actions.ActionResult{Output: fmt.Sprintf(`{"detail": "No account with name '%s'"}`, action.AccountName), IsSuccessful: false}, false
+		}
+		return accounts, accounts[0].Name, actions.ActionResult{}, true
+	}
+
+	var categories []models.Category
+	tx := action.Session.Db.Where("fully_qualified_name = ? OR name = ?", action.CategoryName, action.CategoryName).Order("fully_qualified_name").Limit(1).Find(&categories)
+	if tx.Error != nil {
+		return nil, "", actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, false
+	}
+	if len(categories) == 0 {
+		return nil, "", actions.ActionResult{Output: fmt.Sprintf(`{"detail": "No category with name '%s'"}`, action.CategoryName), IsSuccessful: false}, false
+	}
+
+	category := categories[0]
+	tx = action.Session.Db.Joins("CurrentState").Joins("Category").
+		Where("Category.fully_qualified_name = ? OR Category.fully_qualified_name LIKE ?", category.FullyQualifiedName, category.FullyQualifiedName+"/%").
+		Order("accounts.name").
+		Find(&accounts)
+	if tx.Error != nil {
+		return nil, "", actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, false
+	}
+	return accounts, category.FullyQualifiedName, actions.ActionResult{}, true
+}
+
+// Helpers charts the balance each day, then the change each week of an account or the balance of each account of a
+// category
+func (to TrendOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	s := actions.ConsequenceSession(consequences)
+
+	group := output.EmptyOutputGroup().
+		Header(fmt.Sprintf("Balance trend for %s", to.Name))
+	if len(to.Points) == 0 {
+		return group.ToSlice()
+	}
+
+	first, last := to.Points[0], to.Points[len(to.Points)-1]
+	change := last.Balance - first.Balance
+	summary := fmt.Sprintf("%s on %s, %s on %s", first.Balance.String(s), first.Date.Format("2006-01-02"), last.Balance.String(s), last.Date.Format("2006-01-02"))
+	if change.IsNegative() {
+		group.PushStyle(output.TextStyle{Color: output.Error}).Paragraph(fmt.Sprintf("%s (%s)", summary, change.String(s))).PopStyle()
+	} else {
+		group.Paragraph(fmt.Sprintf("%s (+%s)", summary, change.String(s)))
+	}
+
+	values := make([]float64, len(to.Points))
+	for i, point := range to.Points {
+		values[i] = float64(point.Balance.Value()) / 100
+	}
+	group.Sparkline("Balance", values)
+
+	if to.IsCategory {
+		bars := []output.Bar{}
+		for _, c := range consequences {
+			if account, ok := c.Object.(models.Account); ok {
+				balance := account.CurrentState.Balance
+				bars = append(bars, output.Bar{Label: account.Name, Value: float64(balance.Value()) / 100, Display: balance.String(s)})
+			}
+		}
+		return group.BarChart("Balance by account", bars).ToSlice()
+	}
+
+	// Weeks end on the last day, so the latest week is always a whole one
+	bars := []output.Bar{}
+	for end := len(to.Points) - 1; end > 0; end -= 7 {
+		start := end - 7
+		if start < 0 {
+			start = 0
+		}
+		weekly := to.Points[end].Balance - to.Points[start].Balance
+		bars = append([]output.Bar{{Label: "week to " + to.Points[end].Date.Format("2006-01-02"), Value: float64(weekly.Value()) / 100, Display: weekly.String(s)}}, bars...)
+	}
+	return group.BarChart("Change by week", bars).ToSlice()
+}
@@ -0,0 +1,86 @@
+package actions_reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// makeStates saves a chain of states with the balances, each taking effect on its day, and returns the last
+func makeStates(s *session.Session, days []time.Time, balances []float64) *uint {
+	var prev *uint
+	for i, day := range days {
+		state := models.AccountState{EffectiveAt: day.Unix(), Balance: models.MakeMoney(balances[i]), PrevStateID: prev}
+		s.Db.Create(&state)
+		prev = &state.ID
+	}
+	return prev
+}
+
+func TestTrendAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	today := time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time { return today.AddDate(0, 0, offset) }
+
+	food := models.MakeCategory("food", "", nil)
+	s.Db.Create(&food)
+	takeaway := models.MakeCategory("takeaway", "", &food)
+	s.Db.Create(&takeaway)
+
+	// 100 on day -5, 150 on day -2, and 20 entered last but dated day -4
+	s.Db.Create(&models.Account{Name: "groceries", IsActive: true, CategoryID: &food.ID, CurrentStateID: makeStates(&s, []time.Time{day(-5), day(-2), day(-4)}, []float64{100, 150, 130})})
+	s.Db.Create(&models.Account{Name: "pizza", IsActive: true, CategoryID: &takeaway.ID, CurrentStateID: makeStates(&s, []time.Time{day(-1)}, []float64{10})})
+	s.Db.Create(&models.Account{Name: "cash", IsActive: true, CurrentStateID: makeStates(&s, []time.Time{day(-3)}, []float64{5})})
+
+	balances := func(points []TrendPoint) []models.Money {
+		money := []models.Money{}
+		for _, point := range points {
+			money = append(money, point.Balance)
+		}
+		return money
+	}
+
+	result, consequences := TrendAction{AccountName: "groceries", Days: 6, Today: today, Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	trend := result.Output.(TrendOutput)
+	assert.Equal(t, "groceries", trend.Name)
+	assert.False(t, trend.IsCategory)
+	assert.Equal(t, day(-5), trend.Points[0].Date)
+	assert.Equal(t, today, trend.Points[5].Date)
+	assert.Equal(t, []models.Money{models.MakeMoney(100), models.MakeMoney(80), models.MakeMoney(80), models.MakeMoney(130), models.MakeMoney(130), models.MakeMoney(130)}, balances(trend.Points))
+	assert.Len(t, consequences, 1)
+
+	result, consequences = TrendAction{CategoryName: "food", Days: 3, Today: today, Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	trend = result.Output.(TrendOutput)
+	assert.True(t, trend.IsCategory)
+	assert.Equal(t, []models.Money{models.MakeMoney(130), models.MakeMoney(140), models.MakeMoney(140)}, balances(trend.Points))
+	if assert.Len(t, consequences, 2) {
+		assert.Equal(t, "groceries", consequences[0].Object.(models.Account).Name)
+		assert.Equal(t, models.MakeMoney(10), consequences[1].Object.(models.Account).CurrentState.Balance)
+	}
+
+	result, _ = TrendAction{AccountName: "missing", Days: 3, Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No account with name 'missing'"}`, result.Output)
+
+	result, _ = TrendAction{CategoryName: "missing", Days: 3, Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No category with name 'missing'"}`, result.Output)
+}
+
+func TestTrendActionIsValid(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	assert.True(t, TrendAction{AccountName: "cash", Days: 1, Session: &s}.IsValid())
+	assert.True(t, TrendAction{CategoryName: "food", Days: 1, Session: &s}.IsValid())
+	assert.False(t, TrendAction{Days: 1, Session: &s}.IsValid())
+	assert.False(t, TrendAction{AccountName: "cash", CategoryName: "food", Days: 1, Session: &s}.IsValid())
+	assert.False(t, TrendAction{AccountName: "cash", Session: &s}.IsValid())
+}
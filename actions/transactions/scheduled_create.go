@@ -0,0 +1,70 @@
+package actions_transactions
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// CreateScheduledTransactionAction records a transaction that is expected to happen, once or every IntervalDays days.
+// Nothing moves until it happens, but the forecast report counts it.
+type CreateScheduledTransactionAction struct {
+	Amount          models.Money
+	SourceName      string
+	DestinationName string
+	Memo            string
+	NextDate        time.Time // the day of the first occurrence. The zero time means today
+	IntervalDays    int       // days between occurrences. 0 means the transaction only happens once
+	Session         *session.Session
+}
+
+func (action CreateScheduledTransactionAction) IsValid() bool {
+	return action.Session != nil && action.Amount > 0 && action.IntervalDays >= 0 && (action.SourceName != "" || action.DestinationName != "")
+}
+
+func (action CreateScheduledTransactionAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	if action.SourceName != "" && action.SourceName == action.DestinationName {
+		return actions.ActionResult{Output: `{"detail": "Source and destination must be different accounts"}`, IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	nextDate := util.Today()
+	if !action.NextDate.IsZero() {
+		nextDate = util.Day(action.NextDate)
+	}
+	scheduled := models.ScheduledTransaction{
+		Change:       action.Amount,
+		Memo:         action.Memo,
+		NextDate:     nextDate.Unix(),
+		IntervalDays: action.IntervalDays,
+		Session:      action.Session,
+	}
+
+	if action.SourceName != "" {
+		account, result, ok := findAccount(action.SourceName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		scheduled.Source = &account
+		scheduled.SourceID = &account.ID
+	}
+	if action.DestinationName != "" {
+		account, result, ok := findAccount(action.DestinationName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		scheduled.Destination = &account
+		scheduled.DestinationID = &account.ID
+	}
+
+	if err := action.Session.Db.Omit(clause.Associations).Create(&scheduled).Error; err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	return actions.ActionResult{Output: "", IsSuccessful: true}, []*actions.Consequence{
+		{ConsequenceType: actions.CREATE, Object: scheduled},
+	}
+}
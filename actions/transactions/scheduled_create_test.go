@@ -0,0 +1,75 @@
+package actions_transactions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+func TestCreateScheduledTransactionAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100)}}
+	s.Db.Create(&checking)
+
+	first := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	action := CreateScheduledTransactionAction{Amount: models.MakeMoney(25), SourceName: "checking", Memo: "rent", NextDate: first, IntervalDays: 30, Session: &s}
+	assert.True(t, action.IsValid())
+
+	result, consequences := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 1)
+
+	assert.Equal(t, actions.CREATE, consequences[0].ConsequenceType)
+	scheduled := consequences[0].Object.(models.ScheduledTransaction)
+	assert.Equal(t, models.MakeMoney(25), scheduled.Change)
+	assert.Equal(t, "rent", scheduled.Memo)
+	assert.Equal(t, first.Unix(), scheduled.NextDate)
+	assert.Equal(t, 30, scheduled.IntervalDays)
+	assert.Equal(t, "checking", scheduled.Source.Name)
+	assert.Nil(t, scheduled.Destination)
+
+	// scheduling does not move any money
+	var reloaded models.Account
+	s.Db.Preload("CurrentState").First(&reloaded, checking.ID)
+	assert.Equal(t, models.MakeMoney(100), reloaded.Balance())
+
+	result, consequences = CreateScheduledTransactionAction{Amount: models.MakeMoney(5), DestinationName: "checking", Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, util.Today().Unix(), consequences[0].Object.(models.ScheduledTransaction).NextDate)
+	assert.Equal(t, 0, consequences[0].Object.(models.ScheduledTransaction).IntervalDays)
+
+	var count int64
+	s.Db.Model(&models.ScheduledTransaction{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestCreateScheduledTransactionActionInvalid(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	s.Db.Create(&checking)
+
+	assert.False(t, CreateScheduledTransactionAction{Amount: models.MakeMoney(40), Session: &s}.IsValid())
+	assert.False(t, CreateScheduledTransactionAction{Amount: models.MakeMoney(0), SourceName: "checking", Session: &s}.IsValid())
+	assert.False(t, CreateScheduledTransactionAction{Amount: models.MakeMoney(40), SourceName: "checking", IntervalDays: -1, Session: &s}.IsValid())
+
+	result, consequences := CreateScheduledTransactionAction{Amount: models.MakeMoney(40), SourceName: "missing", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No account with name 'missing'"}`, result.Output)
+	assert.Len(t, consequences, 0)
+
+	result, _ = CreateScheduledTransactionAction{Amount: models.MakeMoney(40), SourceName: "checking", DestinationName: "checking", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+
+	var count int64
+	s.Db.Model(&models.ScheduledTransaction{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,149 @@
+package actions_transactions
+
+import (
+	"fmt"
+	"time"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// ListScheduledTransactionAction lists the scheduled transactions that are still to happen by their next occurrence.
+// One that only happens once is not listed once its day has passed.
+type ListScheduledTransactionAction struct {
+	AccountName string    // only list scheduled transactions into or out of this account
+	Limit       int       // maximum number of scheduled transactions, soonest first. 0 means no limit
+	Today       time.Time // occurrences before this day have passed. Defaults to the current date
+	Query       query.Query
+	Session     *session.Session
+}
+
+// ListScheduledTransactionFields can be used to filter and order the scheduled transactions
+var ListScheduledTransactionFields = query.Fields{
+	{Name: "next", Column: "scheduled_transactions.next_date", Kind: query.Date},
+	{Name: "amount", Column: "scheduled_transactions.change", Kind: query.Money},
+	{Name: "from", Column: "(SELECT accounts.name FROM accounts WHERE accounts.id = scheduled_transactions.source_id)", Kind: query.Text},
+	{Name: "to", Column: "(SELECT accounts.name FROM accounts WHERE accounts.id = scheduled_transactions.destination_id)", Kind: query.Text},
+	{Name: "memo", Column: "scheduled_transactions.memo", Kind: query.Text},
+}
+
+type ListScheduledTransactionOutput struct {
+	AccountName string `json:"accountName"`
+}
+
+func (action ListScheduledTransactionAction) IsValid() bool {
+	return action.Session != nil && action.Limit >= 0
+}
+
+// nextColumn is the next occurrence on or after today, as models.ScheduledTransaction.NextOccurrence works it out
+func nextColumn(today time.Time) string {
+	interval := "(scheduled_transactions.interval_days * 86400)"
+	return fmt.Sprintf("(CASE WHEN scheduled_transactions.next_date >= %[1]d OR scheduled_transactions.interval_days <= 0 "+
+		"THEN scheduled_transactions.next_date "+
+		"ELSE scheduled_transactions.next_date + (%[1]d - scheduled_transactions.next_date + %[2]s - 1) / %[2]s * %[2]s END)",
+		today.Unix(), interval)
+}
+
+func (action ListScheduledTransactionAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	consequences := []*actions.Consequence{}
+
+	today := action.Today
+	if today.IsZero() {
+		today = util.Today()
+	}
+	today = util.Day(today)
+	next := nextColumn(today)
+
+	// The stored date is only the first occurrence, so the next one is worked out for today. The query's ordering comes
+	// first, so the soonest first ordering only breaks ties.
+	q := action.Query.WithColumn("next", next)
+	tx := q.Apply(action.Session.Db.Preload("Source").Preload("Destination")).
+		Where(next+" >= ?", today.Unix()).
+		Order(next + ", scheduled_transactions.id")
+
+	if action.AccountName != "" {
+		account, result, ok := findAccount(action.AccountName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		tx = tx.Where("source_id = ? OR destination_id = ?", account.ID, account.ID)
+	}
+
+	if action.Limit > 0 {
+		tx = tx.Limit(action.Limit)
+	}
+
+	var scheduled []models.ScheduledTransaction
+	tx = tx.Find(&scheduled)
+
+	if tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	for _, st := range scheduled {
+		st.Session = action.Session
+		if date, ok := st.NextOccurrence(today); ok {
+			st.NextDate = date.Unix()
+		}
+		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: st})
+	}
+
+	return actions.ActionResult{Output: ListScheduledTransactionOutput{AccountName: action.AccountName}, IsSuccessful: true}, consequences
+}
+
+// Helpers shows each scheduled transaction as a row, signed like the transactions of ListTransactionOutput
+func (lso ListScheduledTransactionOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	s := actions.ConsequenceSession(consequences)
+
+	rows := [][]output.TableCell{}
+	for _, c := range consequences {
+		scheduled, ok := c.Object.(models.ScheduledTransaction)
+		if !ok {
+			continue
+		}
+
+		from, to := "", ""
+		if scheduled.Source != nil {
+			from = scheduled.Source.Name
+		}
+		if scheduled.Destination != nil {
+			to = scheduled.Destination.Name
+		}
+
+		amount := scheduled.Change
+		if lso.AccountName != "" && from == lso.AccountName {
+			amount = -amount
+		}
+
+		every := "once"
+		switch {
+		case scheduled.IntervalDays == 1:
+			every = "day"
+		case scheduled.IsRecurring():
+			every = fmt.Sprintf("%d days", scheduled.IntervalDays)
+		}
+
+		rows = append(rows, []output.TableCell{
+			output.Cell(time.Unix(scheduled.NextDate, 0).UTC().Format("2006-01-02")),
+			output.Cell(every),
+			output.Cell(from),
+			output.Cell(to),
+			actions.MoneyCell(amount, s),
+			output.Cell(scheduled.Memo),
+		})
+	}
+
+	columns := []output.TableColumn{
+		output.MakeColumn("Next", output.DateColumn),
+		output.MakeColumn("Every", output.TextColumn),
+		output.MakeColumn("From", output.TextColumn),
+		output.MakeColumn("To", output.TextColumn),
+		output.MakeColumn("Amount", output.MoneyColumn),
+		output.MakeColumn("Memo", output.TextColumn),
+	}
+	return output.EmptyOutputGroup().Table(columns, rows).ToSlice()
+}
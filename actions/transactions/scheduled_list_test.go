@@ -0,0 +1,84 @@
+package actions_transactions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+func TestListScheduledTransactionAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	savings := models.Account{Name: "savings", IsActive: true}
+	s.Db.Create(&checking)
+	s.Db.Create(&savings)
+
+	day := func(month, day int) time.Time {
+		return time.Date(2026, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	today := day(10, 19)
+
+	// rent and pay started before today, so they next happen on Oct 31 and Oct 24. The gift has already happened.
+	s.Db.Create(&models.ScheduledTransaction{NextDate: day(10, 1).Unix(), IntervalDays: 30, Change: models.MakeMoney(1), SourceID: &checking.ID, Memo: "rent"})
+	s.Db.Create(&models.ScheduledTransaction{NextDate: day(10, 10).Unix(), IntervalDays: 14, Change: models.MakeMoney(2), DestinationID: &checking.ID, Memo: "pay"})
+	s.Db.Create(&models.ScheduledTransaction{NextDate: day(10, 25).Unix(), Change: models.MakeMoney(3), SourceID: &checking.ID, DestinationID: &savings.ID, Memo: "bonus"})
+	s.Db.Create(&models.ScheduledTransaction{NextDate: day(11, 20).Unix(), Change: models.MakeMoney(4), SourceID: &savings.ID, Memo: "holiday"})
+	s.Db.Create(&models.ScheduledTransaction{NextDate: day(9, 1).Unix(), Change: models.MakeMoney(5), DestinationID: &checking.ID, Memo: "gift"})
+
+	scheduledField := func(name string) query.Field {
+		f, _ := ListScheduledTransactionFields.Find(name)
+		return f
+	}
+
+	testCase := func(action ListScheduledTransactionAction, expectedMemos []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			action.Today = today
+			result, consequences := action.Execute()
+
+			assert.True(t, result.IsSuccessful)
+			assert.Equal(t, ListScheduledTransactionOutput{AccountName: action.AccountName}, result.Output)
+
+			memos := []string{}
+			for _, c := range consequences {
+				assert.Equal(t, actions.READ, c.ConsequenceType)
+				assert.Equal(t, &s, c.Object.(models.ScheduledTransaction).Session)
+				memos = append(memos, c.Object.(models.ScheduledTransaction).Memo)
+			}
+			assert.Equal(t, expectedMemos, memos)
+		}
+	}
+
+	t.Run("all", testCase(ListScheduledTransactionAction{Session: &s}, []string{"pay", "bonus", "rent", "holiday"}))
+	t.Run("limit", testCase(ListScheduledTransactionAction{Limit: 2, Session: &s}, []string{"pay", "bonus"}))
+	t.Run("account", testCase(ListScheduledTransactionAction{AccountName: "savings", Session: &s}, []string{"bonus", "holiday"}))
+
+	t.Run("query", testCase(ListScheduledTransactionAction{Query: query.Query{
+		Filter: query.Comparison{Field: scheduledField("to"), Op: query.Equal, Value: "Checking"},
+	}, Session: &s}, []string{"pay"}))
+
+	t.Run("query by next", testCase(ListScheduledTransactionAction{Query: query.Query{
+		Filter: query.Comparison{Field: scheduledField("next"), Op: query.Greater, Value: util.DateRange{Start: day(10, 26), End: day(10, 27)}},
+		Order:  []query.OrderBy{{Field: scheduledField("next"), Desc: true}},
+	}, Session: &s}, []string{"holiday", "rent"}))
+
+	t.Run("next occurrence", func(t *testing.T) {
+		_, consequences := ListScheduledTransactionAction{Today: today, Session: &s}.Execute()
+		next := []time.Time{}
+		for _, c := range consequences {
+			next = append(next, time.Unix(c.Object.(models.ScheduledTransaction).NextDate, 0).UTC())
+		}
+		assert.Equal(t, []time.Time{day(10, 24), day(10, 25), day(10, 31), day(11, 20)}, next)
+	})
+
+	t.Run("missing account", func(t *testing.T) {
+		result, _ := ListScheduledTransactionAction{AccountName: "missing", Session: &s}.Execute()
+		assert.False(t, result.IsSuccessful)
+	})
+}
@@ -0,0 +1,128 @@
+package actions_transactions
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// CreateTransactionAction records money moving out of the source account and into the destination account. Either
+// account may be left empty for money entering or leaving the ledger.
+type CreateTransactionAction struct {
+	Amount          models.Money
+	SourceName      string
+	DestinationName string
+	Memo            string
+	EffectiveAt     time.Time // the day the transaction happened. The zero time means today
+	Session         *session.Session
+}
+
+func (action CreateTransactionAction) IsValid() bool {
+	return action.Session != nil && action.Amount > 0 && (action.SourceName != "" || action.DestinationName != "")
+}
+
+func (action CreateTransactionAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	if action.SourceName != "" && action.SourceName == action.DestinationName {
+		return actions.ActionResult{Output: `{"detail": "Source and destination must be different accounts"}`, IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	effectiveAt := util.Today()
+	if !action.EffectiveAt.IsZero() {
+		effectiveAt = action.EffectiveAt
+	}
+	transaction := models.Transaction{Change: action.Amount, Memo: action.Memo, EffectiveAt: effectiveAt.Unix(), Session: action.Session}
+
+	var source, destination *models.Account
+	if action.SourceName != "" {
+		account, result, ok := findAccount(action.SourceName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		source = &account
+		transaction.SourceID = &source.ID
+	}
+	if action.DestinationName != "" {
+		account, result, ok := findAccount(action.DestinationName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		destination = &account
+		transaction.DestinationID = &destination.ID
+	}
+
+	// The transaction and the new balances of its accounts are saved together or not at all
+	err := action.Session.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&transaction).Error; err != nil {
+			return err
+		}
+		for _, account := range []*models.Account{source, destination} {
+			if account == nil {
+				continue
+			}
+			if err := changeBalance(tx, account, transaction.ChangeFor(account.ID), transaction.EffectiveAt, action.Session); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return actions.ActionResult{Output: err.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	transaction.Source = source
+	transaction.Destination = destination
+	consequences := []*actions.Consequence{
+		{ConsequenceType: actions.CREATE, Object: transaction},
+	}
+	for _, account := range []*models.Account{source, destination} {
+		if account != nil {
+			consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.UPDATE, Object: *account})
+		}
+	}
+
+	return actions.ActionResult{Output: "", IsSuccessful: true}, consequences
+}
+
+// findAccount loads the account with its current state. ok is false, with a result describing the problem, when there
+// is no account with that name.
+func findAccount(name string, session *session.Session) (account models.Account, result actions.ActionResult, ok bool) {
+	var accounts []models.Account
+	tx := session.Db.Preload("CurrentState").Where("Name = ?", name).Find(&accounts)
+
+	if tx.Error != nil {
+		return account, actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, false
+	}
+	if len(accounts) == 0 {
+		return account, actions.ActionResult{Output: fmt.Sprintf(`{"detail": "No account with name '%s'"}`, name), IsSuccessful: false}, false
+	}
+
+	account = accounts[0]
+	account.Session = session
+	return account, actions.ActionResult{}, true
+}
+
+// changeBalance records a new state for the account with its balance adjusted by change, taking effect at effectiveAt
+func changeBalance(tx *gorm.DB, account *models.Account, change models.Money, effectiveAt int64, session *session.Session) error {
+	currentState := account.CurrentState
+	nextState := models.AccountState{
+		Balance:     currentState.Balance + change,
+		PrevState:   &currentState,
+		PrevStateID: &currentState.ID,
+		IsClosed:    currentState.IsClosed,
+		EffectiveAt: effectiveAt,
+		Session:     session,
+	}
+	if err := tx.Create(&nextState).Error; err != nil {
+		return err
+	}
+
+	account.CurrentState = nextState
+	account.CurrentStateID = &nextState.ID
+	return tx.Save(account).Error
+}
@@ -0,0 +1,125 @@
+package actions_transactions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+func TestCreateTransactionAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100)}}
+	savings := models.Account{Name: "savings", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(10)}}
+	s.Db.Create(&checking)
+	s.Db.Create(&savings)
+
+	action := CreateTransactionAction{Amount: models.MakeMoney(25), SourceName: "checking", DestinationName: "savings", Memo: "rainy day", Session: &s}
+	assert.True(t, action.IsValid())
+
+	result, consequences := action.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 3)
+
+	assert.Equal(t, actions.CREATE, consequences[0].ConsequenceType)
+	transaction := consequences[0].Object.(models.Transaction)
+	assert.Equal(t, models.MakeMoney(25), transaction.Change)
+	assert.Equal(t, "rainy day", transaction.Memo)
+	assert.Equal(t, "checking", transaction.Source.Name)
+	assert.Equal(t, "savings", transaction.Destination.Name)
+
+	assert.Equal(t, actions.UPDATE, consequences[1].ConsequenceType)
+	assert.Equal(t, models.MakeMoney(75), consequences[1].Object.(models.Account).CurrentState.Balance)
+	assert.Equal(t, models.MakeMoney(35), consequences[2].Object.(models.Account).CurrentState.Balance)
+
+	var reloaded models.Account
+	s.Db.Preload("CurrentState").First(&reloaded, checking.ID)
+	assert.Equal(t, models.MakeMoney(75), reloaded.Balance())
+	assert.NotNil(t, reloaded.CurrentState.PrevStateID)
+}
+
+func TestCreateTransactionActionIncome(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	s.Db.Create(&checking)
+
+	result, consequences := CreateTransactionAction{Amount: models.MakeMoney(40), DestinationName: "checking", Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 2)
+	assert.Nil(t, consequences[0].Object.(models.Transaction).Source)
+	assert.Equal(t, models.MakeMoney(40), consequences[1].Object.(models.Account).CurrentState.Balance)
+}
+
+func TestCreateTransactionActionEffectiveDate(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	s.Db.Create(&checking)
+
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	result, consequences := CreateTransactionAction{Amount: models.MakeMoney(40), DestinationName: "checking", EffectiveAt: friday, Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, friday.Unix(), consequences[0].Object.(models.Transaction).EffectiveAt)
+	assert.Equal(t, friday.Unix(), consequences[1].Object.(models.Account).CurrentState.EffectiveAt)
+
+	result, consequences = CreateTransactionAction{Amount: models.MakeMoney(40), DestinationName: "checking", Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, util.Today().Unix(), consequences[0].Object.(models.Transaction).EffectiveAt)
+}
+
+func TestCreateTransactionActionRollsBack(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true, CurrentState: models.AccountState{Balance: models.MakeMoney(100)}}
+	s.Db.Create(&checking)
+
+	// saving the new balance fails, so the transaction must not be saved either
+	s.Db.Callback().Create().Before("gorm:create").Register("fail_account_states", func(db *gorm.DB) {
+		if db.Statement.Table == "account_states" {
+			db.AddError(errors.New("disk full"))
+		}
+	})
+
+	result, consequences := CreateTransactionAction{Amount: models.MakeMoney(40), SourceName: "checking", Session: &s}.Execute()
+
+	assert.False(t, result.IsSuccessful)
+	assert.Empty(t, consequences)
+
+	var count int64
+	s.Db.Model(&models.Transaction{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestCreateTransactionActionInvalid(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	s.Db.Create(&checking)
+
+	assert.False(t, CreateTransactionAction{Amount: models.MakeMoney(40), Session: &s}.IsValid())
+	assert.False(t, CreateTransactionAction{Amount: models.MakeMoney(0), SourceName: "checking", Session: &s}.IsValid())
+
+	result, consequences := CreateTransactionAction{Amount: models.MakeMoney(40), SourceName: "missing", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+	assert.Equal(t, `{"detail": "No account with name 'missing'"}`, result.Output)
+	assert.Len(t, consequences, 0)
+
+	result, _ = CreateTransactionAction{Amount: models.MakeMoney(40), SourceName: "checking", DestinationName: "checking", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+
+	var count int64
+	s.Db.Model(&models.Transaction{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,121 @@
+package actions_transactions
+
+import (
+	"time"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+)
+
+type ListTransactionAction struct {
+	AccountName string    // only list transactions into or out of this account
+	Limit       int       // maximum number of transactions, newest first. 0 means no limit
+	Since       time.Time // only list transactions that took effect at or after this time. The zero time means no limit
+	Until       time.Time // only list transactions that took effect before this time. The zero time means no limit
+	Query       query.Query
+	Session     *session.Session
+}
+
+// ListTransactionFields can be used to filter and order the transactions
+var ListTransactionFields = query.Fields{
+	{Name: "date", Column: "transactions.effective_at", Kind: query.Date},
+	{Name: "amount", Column: "transactions.change", Kind: query.Money},
+	{Name: "from", Column: "(SELECT accounts.name FROM accounts WHERE accounts.id = transactions.source_id)", Kind: query.Text},
+	{Name: "to", Column: "(SELECT accounts.name FROM accounts WHERE accounts.id = transactions.destination_id)", Kind: query.Text},
+	{Name: "memo", Column: "transactions.memo", Kind: query.Text},
+}
+
+type ListTransactionOutput struct {
+	AccountName string `json:"accountName"`
+}
+
+func (action ListTransactionAction) IsValid() bool {
+	return action.Session != nil && action.Limit >= 0 && (action.Since.IsZero() || action.Until.IsZero() || action.Since.Before(action.Until))
+}
+
+func (action ListTransactionAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	consequences := []*actions.Consequence{}
+
+	// The query's ordering comes first, so the newest first ordering only breaks ties
+	tx := action.Query.Apply(action.Session.Db.Preload("Source").Preload("Destination")).Order("transactions.effective_at desc, transactions.id desc")
+
+	if action.AccountName != "" {
+		account, result, ok := findAccount(action.AccountName, action.Session)
+		if !ok {
+			return result, []*actions.Consequence{}
+		}
+		tx = tx.Where("source_id = ? OR destination_id = ?", account.ID, account.ID)
+	}
+
+	if !action.Since.IsZero() {
+		tx = tx.Where("effective_at >= ?", action.Since.Unix())
+	}
+	if !action.Until.IsZero() {
+		tx = tx.Where("effective_at < ?", action.Until.Unix())
+	}
+
+	if action.Limit > 0 {
+		tx = tx.Limit(action.Limit)
+	}
+
+	var transactions []models.Transaction
+	tx = tx.Find(&transactions)
+
+	if tx.Error != nil {
+		return actions.ActionResult{Output: tx.Error.Error(), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	for _, t := range transactions {
+		t.Session = action.Session
+		consequences = append(consequences, &actions.Consequence{ConsequenceType: actions.READ, Object: t})
+	}
+
+	return actions.ActionResult{Output: ListTransactionOutput{AccountName: action.AccountName}, IsSuccessful: true}, consequences
+}
+
+// Helpers shows each transaction as a row. When the list is for a single account the amount is signed by its effect on
+// that account.
+func (lto ListTransactionOutput) Helpers(consequences []*actions.Consequence) []output.Helper {
+	s := actions.ConsequenceSession(consequences)
+
+	rows := [][]output.TableCell{}
+	for _, c := range consequences {
+		transaction, ok := c.Object.(models.Transaction)
+		if !ok {
+			continue
+		}
+
+		from, to := "", ""
+		if transaction.SourceExists() {
+			from = transaction.Source.Name
+		}
+		if transaction.DestinationExists() {
+			to = transaction.Destination.Name
+		}
+
+		amount := transaction.Change
+		if lto.AccountName != "" && from == lto.AccountName {
+			amount = -amount
+		}
+
+		rows = append(rows, []output.TableCell{
+			output.Cell(time.Unix(transaction.EffectiveAt, 0).UTC().Format("2006-01-02")),
+			output.Cell(from),
+			output.Cell(to),
+			actions.MoneyCell(amount, s),
+			output.Cell(transaction.Memo),
+		})
+	}
+
+	columns := []output.TableColumn{
+		output.MakeColumn("Date", output.DateColumn),
+		output.MakeColumn("From", output.TextColumn),
+		output.MakeColumn("To", output.TextColumn),
+		output.MakeColumn("Amount", output.MoneyColumn),
+		output.MakeColumn("Memo", output.TextColumn),
+	}
+	return output.EmptyOutputGroup().Table(columns, rows).ToSlice()
+}
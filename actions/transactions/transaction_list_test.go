@@ -0,0 +1,70 @@
+package actions_transactions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+)
+
+func field(name string) query.Field {
+	f, _ := ListTransactionFields.Find(name)
+	return f
+}
+
+func TestListTransactionAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	checking := models.Account{Name: "checking", IsActive: true}
+	savings := models.Account{Name: "savings", IsActive: true}
+	s.Db.Create(&checking)
+	s.Db.Create(&savings)
+
+	s.Db.Create(&models.Transaction{CreatedAt: 100, Change: models.MakeMoney(1), DestinationID: &checking.ID, Memo: "first"})
+	s.Db.Create(&models.Transaction{CreatedAt: 200, Change: models.MakeMoney(2), SourceID: &checking.ID, DestinationID: &savings.ID, Memo: "second"})
+	s.Db.Create(&models.Transaction{CreatedAt: 300, Change: models.MakeMoney(3), SourceID: &savings.ID, Memo: "third"})
+	// entered last but dated before the others
+	s.Db.Create(&models.Transaction{CreatedAt: 400, EffectiveAt: 50, Change: models.MakeMoney(4), DestinationID: &savings.ID, Memo: "backdated"})
+
+	testCase := func(action ListTransactionAction, expectedMemos []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, consequences := action.Execute()
+
+			assert.True(t, result.IsSuccessful)
+			assert.Equal(t, ListTransactionOutput{AccountName: action.AccountName}, result.Output)
+
+			memos := []string{}
+			for _, c := range consequences {
+				assert.Equal(t, actions.READ, c.ConsequenceType)
+				assert.Equal(t, &s, c.Object.(models.Transaction).Session)
+				memos = append(memos, c.Object.(models.Transaction).Memo)
+			}
+			assert.Equal(t, expectedMemos, memos)
+		}
+	}
+
+	t.Run("all", testCase(ListTransactionAction{Session: &s}, []string{"third", "second", "first", "backdated"}))
+	t.Run("limit", testCase(ListTransactionAction{Limit: 2, Session: &s}, []string{"third", "second"}))
+	t.Run("account", testCase(ListTransactionAction{AccountName: "checking", Session: &s}, []string{"second", "first"}))
+	t.Run("since", testCase(ListTransactionAction{Since: time.Unix(200, 0), Session: &s}, []string{"third", "second"}))
+	t.Run("until", testCase(ListTransactionAction{Until: time.Unix(200, 0), Session: &s}, []string{"first", "backdated"}))
+	t.Run("since and until", testCase(ListTransactionAction{Since: time.Unix(100, 0), Until: time.Unix(300, 0), Session: &s}, []string{"second", "first"}))
+
+	t.Run("query", testCase(ListTransactionAction{Query: query.Query{
+		Filter: query.Or{
+			Left:  query.Comparison{Field: field("from"), Op: query.Equal, Value: "Checking"},
+			Right: query.Comparison{Field: field("memo"), Op: query.Contains, Value: "dated"},
+		},
+		Order: []query.OrderBy{{Field: field("amount")}},
+	}, Session: &s}, []string{"second", "backdated"}))
+	t.Run("query limit", testCase(ListTransactionAction{Query: query.Query{Order: []query.OrderBy{{Field: field("amount"), Desc: true}}, Limit: 1}, Session: &s}, []string{"backdated"}))
+
+	assert.False(t, ListTransactionAction{Since: time.Unix(300, 0), Until: time.Unix(100, 0), Session: &s}.IsValid())
+
+	result, _ := ListTransactionAction{AccountName: "missing", Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+}
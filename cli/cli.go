@@ -5,6 +5,7 @@ package cli
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"strings"
@@ -14,9 +15,13 @@ import (
 	"github.com/muesli/termenv"
 	"github.com/olekukonko/ts"
 	"samvasta.com/bujit/actions"
+	actions_pipeline "samvasta.com/bujit/actions/pipeline"
 	"samvasta.com/bujit/cli/customtext"
+	"samvasta.com/bujit/cli/htmlview"
+	"samvasta.com/bujit/cli/markdownview"
 	"samvasta.com/bujit/cli/outputview"
-	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/config"
+	"samvasta.com/bujit/models/output"
 	"samvasta.com/bujit/parse"
 	"samvasta.com/bujit/session"
 )
@@ -27,19 +32,29 @@ var color func(s string) termenv.Color = termenv.ColorProfile().Color
 type history struct {
 	result       actions.ActionResult   // result of last command action
 	consequences []*actions.Consequence // consequences of last command action
-	prevCommands []string
+	command      string                 // the last command
+	echo         string                 // the last command with the part that did not parse marked, empty when it parsed
 	err          error
 	exit         bool
 }
 
-func StartInteractive() {
+func StartInteractive(args []string) {
+	flags := flag.NewFlagSet("cli", flag.ExitOnError)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	outputFlag := flags.String("output", "text", "output format: text, json or jsonl")
+	flags.Parse(args)
 
-	session := session.InMemorySession(models.MigrateSchema)
+	mode, err := ParseOutputMode(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	session := OpenLedger(*ledger)
 
 	history := history{}
 
 	for !history.exit {
-		model := initialModel(&session, &history)
+		model := initialModel(session, &history)
 		// Get user command
 		p := tea.NewProgram(model)
 
@@ -47,11 +62,20 @@ func StartInteractive() {
 			log.Fatal(err)
 		}
 
-		if history.err == nil {
+		if history.err == nil && mode.IsJSON() {
+			if history.exit {
+				break
+			}
+			fmt.Print(formatResult(mode, history.command, history.result, history.consequences))
+		} else if history.err == nil {
 			// print output
 			sb := strings.Builder{}
 
-			sb.WriteString(outputview.View(history.result.Output, history.consequences))
+			if history.echo != "" {
+				sb.WriteString(history.echo)
+				sb.WriteString("\n")
+			}
+			sb.WriteString(render(history.result.Output, history.consequences))
 			for _, c := range history.consequences {
 				json, err := json.Marshal(c.Object)
 				if err == nil {
@@ -71,12 +95,27 @@ func StartInteractive() {
 	}
 }
 
+// render converts the output of an action to text in the format it asks for
+func render(item interface{}, consequences []*actions.Consequence) string {
+	if formatted, ok := item.(actions.FormattedOutput); ok {
+		switch formatted.Format {
+		case actions.FormatMarkdown:
+			return markdownview.View(formatted.Output, consequences)
+		case actions.FormatHTML:
+			return htmlview.View(formatted.Output, consequences)
+		}
+	}
+	return outputview.View(item, consequences)
+}
+
 type model struct {
 	session    *session.Session
 	textInput  customtext.Model
 	suggestion parse.AutoSuggestion
-	historyIdx int
+	historyIdx int // the entry of the history shown by the up and down keys
 	history    *history
+	search     *historySearch  // the Ctrl-R search in progress, nil when not searching
+	menu       *completionMenu // the completions Tab is cycling through, nil when it is not
 }
 
 func initialModel(session *session.Session, history *history) model {
@@ -91,18 +130,56 @@ func initialModel(session *session.Session, history *history) model {
 	return model{
 		textInput:  ti,
 		session:    session,
-		historyIdx: len(history.prevCommands),
+		historyIdx: len(historyEntries(session)),
 		history:    history,
 	}
 }
 
+// historyEntries are the commands typed at the prompt of the ledger, oldest first
+func historyEntries(s *session.Session) []string {
+	if s == nil || s.History == nil {
+		return []string{}
+	}
+	return s.History.Entries
+}
+
 func (m model) Init() tea.Cmd {
 	return textinput.Blink
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+
+	if _, isKey := msg.(tea.KeyMsg); isKey {
+		// any key may have changed the input, so color it again
+		_, suggestion := parse.ParseExpression(m.textInput.Value(), m.session)
+		m.textInput.SetStyles(inputStyles(m.textInput.Value(), suggestion))
+	}
+	return m, cmd
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if key, ok := msg.(tea.KeyMsg); ok && (m.search != nil || key.Type == tea.KeyCtrlR) {
+		if m.updateSearch(key) {
+			return m, nil
+		}
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Type == tea.KeyTab || key.Type == tea.KeyShiftTab:
+			m.updateMenu(key)
+			return m, nil
+		case m.menu != nil && key.Type == tea.KeyEsc:
+			m.closeMenu(true)
+			return m, nil
+		case m.menu != nil:
+			m.closeMenu(false)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
@@ -124,35 +201,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyUp:
 			if m.historyIdx > 0 {
 				m.historyIdx--
-				m.textInput.SetValue(m.history.prevCommands[m.historyIdx])
+				m.textInput.SetValue(historyEntries(m.session)[m.historyIdx])
 			}
 		case tea.KeyDown:
-			if m.historyIdx < len(m.history.prevCommands)-1 {
+			entries := historyEntries(m.session)
+			if m.historyIdx < len(entries)-1 {
 				m.historyIdx++
-				m.textInput.SetValue(m.history.prevCommands[m.historyIdx])
+				m.textInput.SetValue(entries[m.historyIdx])
 			} else {
-				if m.historyIdx < len(m.history.prevCommands) {
+				if m.historyIdx < len(entries) {
 					m.historyIdx++
 				}
 				m.textInput.SetValue("")
 			}
 		case tea.KeyEnter:
+			command := currentText
 
 			if m.session != nil {
-				action, _ := parse.ParseExpression(currentText, m.session)
+				action, suggestion := parse.ParseExpression(currentText, m.session)
+				m.history.echo = markDiagnostic(m.textInput.Prompt, currentText, suggestion.Diagnostic)
+				if _, isExit := action.(actions.ExitAction); isExit {
+					m.history.exit = true
+				}
+				if sequence, ok := action.(actions_pipeline.SequenceAction); ok && sequence.IsExit() {
+					m.history.exit = true
+				}
+				if rerun, ok := action.(parse.HistoryAction); ok {
+					// record the command that is run again rather than its number, which changes
+					if rerunCommand, ok := rerun.Command(); ok {
+						command = rerunCommand
+					}
+				}
 				if action != nil {
 					result, consequences := action.Execute()
 					m.history.result = result
 					m.history.consequences = consequences
 				} else {
-					m.history.result = actions.ActionResult{IsSuccessful: false}
+					problem := output.EmptyOutputGroup().PushStyle(output.TextStyle{Color: output.Error}).Paragraph(suggestion.Problem(currentText)).ToSlice()
+					m.history.result = actions.ActionResult{Output: problem, IsSuccessful: false}
 					m.history.consequences = []*actions.Consequence{}
 				}
 			}
 
 			// have to turn off cursor so the view doesn't include it when we save to history
 			m.textInput.SetCursorMode(customtext.CursorHide)
-			m.history.prevCommands = append(m.history.prevCommands, m.textInput.Value())
+			m.history.command = currentText
+			if m.session != nil {
+				m.session.History.Add(command)
+			}
 			m.suggestion = parse.EmptySuggestions
 			return m, tea.Quit
 		default:
@@ -160,11 +256,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(currentText) > 0 {
 				_, suggestion := parse.ParseExpression(currentText, m.session)
 				m.suggestion = suggestion
-				for _, next := range suggestion.NextArgs {
-					if strings.HasPrefix(next, currentText) {
-						m.textInput.SetSuggestValue(strings.TrimPrefix(next, currentText))
-						break
-					}
+				if m.textInput.Position() == len([]rune(currentText)) {
+					m.textInput.SetSuggestValue(ghostText(parse.Complete(currentText, m.textInput.Position(), m.session)))
 				}
 			} else {
 				m.suggestion = parse.EmptySuggestions
@@ -180,8 +273,17 @@ func (m model) View() string {
 
 	sb.WriteString(m.textInput.View())
 
-	if !m.history.exit {
+	if !m.history.exit && m.menu != nil {
+		sb.WriteString("\n")
+		sb.WriteString(m.menu.View())
 		sb.WriteString("\n")
+		sb.WriteString("(tab for the next, shift+tab for the previous, esc to undo)")
+	} else if !m.history.exit {
+		sb.WriteString("\n")
+		if m.suggestion.Preview != "" {
+			sb.WriteString(termenv.String(m.suggestion.Preview).Foreground(outputview.TerminalColor(output.Info)).String())
+			sb.WriteString("  ")
+		}
 		sb.WriteString(termenv.String(strings.Join(m.suggestion.NextArgs, "  ")).
 			Foreground(color("10")).
 			Background(color("")).
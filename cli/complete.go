@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"samvasta.com/bujit/config"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+//go:embed completions
+var completionScripts embed.FS
+
+var shells = []string{"bash", "zsh", "fish"}
+
+// Complete writes the suggestions for a partial command. The output is JSON unless a shell is named, in which case it is
+// one suggestion per line for the completion scripts. Returns the exit code for the process.
+func Complete(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("complete", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	line := flags.String("line", "", "the partial command")
+	cursor := flags.Int("cursor", -1, "cursor position in characters, defaults to the end of the line")
+	shell := flags.String("shell", "", "write plain lines for a shell completion script: bash, zsh or fish")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *shell != "" && !isShell(*shell) {
+		fmt.Fprintf(stderr, "unknown shell %q, expected one of: bash, zsh, fish\n", *shell)
+		return exitUsage
+	}
+
+	completion := parse.Complete(*line, *cursor, completionLedger(*ledger))
+
+	if *shell == "" {
+		b, err := json.MarshalIndent(completion, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		fmt.Fprintln(stdout, string(b))
+		return exitSuccess
+	}
+
+	for _, c := range completion.Completions {
+		fmt.Fprintln(stdout, c)
+	}
+	if *shell != "fish" {
+		// fish has no way to show a hint without inserting it
+		for _, p := range completion.Placeholders {
+			fmt.Fprintln(stdout, p)
+		}
+	}
+	return exitSuccess
+}
+
+// completionLedger opens the ledger to complete names from. Pressing tab should not create a ledger, so when there is none
+// yet an empty one is kept in memory instead.
+func completionLedger(path string) *session.Session {
+	if !strings.HasPrefix(path, "file:") {
+		if _, err := os.Stat(path); err != nil {
+			s := session.InMemorySession(models.MigrateSchema)
+			return &s
+		}
+	}
+	return OpenLedger(path)
+}
+
+// Completion writes the completion script for a shell. Returns the exit code for the process.
+func Completion(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 || !isShell(args[0]) {
+		fmt.Fprintln(stderr, "usage: bujit completion <bash|zsh|fish>")
+		return exitUsage
+	}
+
+	script, err := completionScripts.ReadFile("completions/bujit." + args[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	stdout.Write(script)
+	return exitSuccess
+}
+
+func isShell(name string) bool {
+	for _, shell := range shells {
+		if shell == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/parse"
+)
+
+func TestCompleteJSON(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Complete([]string{"--ledger=file::memory:", "--line=new acc", "--cursor=7"}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+
+	var completion parse.Completion
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &completion))
+	assert.Equal(t, "acc", completion.Word)
+	assert.Equal(t, 4, completion.Start)
+	assert.ElementsMatch(t, []string{"account"}, completion.Completions)
+}
+
+func TestCompleteShell(t *testing.T) {
+	testCase := func(shell string, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+			code := Complete([]string{"--ledger=file::memory:", "--shell=" + shell, "--line=new account "}, &stdout, &stderr)
+
+			assert.Equal(t, exitSuccess, code, stderr.String())
+			assert.Equal(t, expected, stdout.String())
+		}
+	}
+
+	t.Run("bash", testCase("bash", "--help\n<name>\n"))
+	t.Run("fish", testCase("fish", "--help\n"))
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	assert.Equal(t, exitUsage, Complete([]string{"--shell=tcsh"}, &stdout, &stderr))
+}
+
+func TestCompleteLedger(t *testing.T) {
+	dir := t.TempDir()
+
+	// pressing tab before there is a ledger should not make one
+	missing := filepath.Join(dir, "missing", "ledger.db")
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := Complete([]string{"--ledger=" + missing, "--shell=bash", "--line=delete account "}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+	assert.Equal(t, "--help\n<name>\n", stdout.String())
+	assert.NoDirExists(t, filepath.Dir(missing))
+
+	existing := filepath.Join(dir, "ledger.db")
+	stdout, stderr = bytes.Buffer{}, bytes.Buffer{}
+	assert.Equal(t, exitSuccess, Run([]string{"--ledger=" + existing, "new", "account", "savings"}, &stdout, &stderr), stderr.String())
+
+	stdout, stderr = bytes.Buffer{}, bytes.Buffer{}
+	code = Complete([]string{"--ledger=" + existing, "--shell=bash", "--line=delete account "}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "savings\n")
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range shells {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+		code := Completion([]string{shell}, &stdout, &stderr)
+
+		assert.Equal(t, exitSuccess, code)
+		assert.Contains(t, stdout.String(), "bujit complete --shell="+shell)
+	}
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	assert.Equal(t, exitUsage, Completion([]string{}, &stdout, &stderr))
+}
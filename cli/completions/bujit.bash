@@ -0,0 +1,46 @@
+# bash completion for bujit
+# Load it in the current shell with: source <(bujit completion bash)
+
+_bujit() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "cli tui run exec serve complete completion" -- "$cur"))
+        return
+    fi
+
+    case "${COMP_WORDS[1]}" in
+        completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            ;;
+        exec)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            ;;
+        run)
+            # everything typed after "run" up to the cursor is the partial command
+            local line="${COMP_LINE:0:COMP_POINT}"
+            line="${line#*run}"
+            line="${line#"${line%%[![:space:]]*}"}"
+
+            local item
+            local -a completions=() placeholders=()
+            while IFS= read -r item; do
+                [[ -z "$item" ]] && continue
+                if [[ "$item" == \<* ]]; then
+                    placeholders+=("$item")
+                else
+                    completions+=("$item")
+                fi
+            done < <(bujit complete --shell=bash --line="$line")
+
+            if [[ ${#completions[@]} -gt 0 ]]; then
+                COMPREPLY=("${completions[@]}")
+            elif [[ ${#placeholders[@]} -gt 0 ]]; then
+                # list the values that are expected without inserting them
+                COMPREPLY=("${placeholders[@]}" " ")
+            fi
+            ;;
+    esac
+}
+
+complete -F _bujit bujit
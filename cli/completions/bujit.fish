@@ -0,0 +1,14 @@
+# fish completion for bujit
+# Load it in the current shell with: bujit completion fish | source
+
+function __bujit_complete_command
+    # everything typed after "run" up to the cursor is the partial command
+    set -l line (commandline -cp | string replace -r '^.*?\brun\s+' '')
+    bujit complete --shell=fish --line="$line"
+end
+
+complete -c bujit -f
+complete -c bujit -n __fish_use_subcommand -a "cli tui run exec serve complete completion"
+complete -c bujit -n "__fish_seen_subcommand_from completion" -a "bash zsh fish"
+complete -c bujit -n "__fish_seen_subcommand_from exec" -F
+complete -c bujit -n "__fish_seen_subcommand_from run" -a "(__bujit_complete_command)"
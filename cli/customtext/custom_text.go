@@ -29,6 +29,14 @@ type blinkCanceled struct{}
 type pasteMsg string
 type pasteErrMsg struct{ error }
 
+// Style colors the runes of the value from Start up to End.
+type Style struct {
+	Start     int
+	End       int
+	Color     string
+	Underline bool
+}
+
 // EchoMode sets the input behavior of the text input field.
 type EchoMode int
 
@@ -74,6 +82,7 @@ type Model struct {
 	BackgroundColor  string
 	PlaceholderColor string
 	SuggestionColor  string
+	HighlightColor   string
 	CursorColor      string
 	EchoMode         EchoMode
 	EchoCharacter    rune
@@ -91,6 +100,14 @@ type Model struct {
 	value        []rune
 	suggestValue []rune
 
+	// The runes of the value from highlightStart up to highlightEnd are shown
+	// in the HighlightColor.
+	highlightStart int
+	highlightEnd   int
+
+	// Colors for parts of the value, such as the tokens of a command.
+	styles []Style
+
 	// Focus indicates whether user input focus should be on this input
 	// component. When false, don't blink and ignore keyboard input.
 	focus bool
@@ -121,6 +138,7 @@ func NewModel() Model {
 		BlinkSpeed:       defaultBlinkSpeed,
 		TextColor:        "",
 		PlaceholderColor: "240",
+		HighlightColor:   "11",
 		CursorColor:      "",
 		EchoCharacter:    '*',
 		CharLimit:        0,
@@ -171,6 +189,36 @@ func (m Model) SuggestValue() string {
 	return string(m.suggestValue)
 }
 
+// SetHighlight shows the runes of the value from start up to end in the
+// HighlightColor. An empty range removes the highlight.
+func (m *Model) SetHighlight(start, end int) {
+	m.highlightStart = start
+	m.highlightEnd = end
+}
+
+func (m Model) isHighlighted(pos int) bool {
+	return pos >= m.highlightStart && pos < m.highlightEnd
+}
+
+// SetStyles colors parts of the value. Runes that no style covers are shown
+// in the TextColor, and the highlight is shown over any style.
+func (m *Model) SetStyles(styles []Style) {
+	m.styles = styles
+}
+
+// styleAt is how the rune at the given position of the value is shown
+func (m Model) styleAt(pos int) Style {
+	if m.isHighlighted(pos) {
+		return Style{Color: m.HighlightColor, Underline: true}
+	}
+	for _, style := range m.styles {
+		if pos >= style.Start && pos < style.End {
+			return Style{Color: style.Color, Underline: style.Underline}
+		}
+	}
+	return Style{Color: m.TextColor}
+}
+
 // SetCursor start moves the cursor to the given position. If the position is
 // out of bounds the cursor will be moved to the start or end accordingly.
 // Returns whether or nor the cursor timer should be reset.
@@ -185,6 +233,11 @@ func (m *Model) SetCursor(pos int) bool {
 	return m.cursorMode == CursorBlink
 }
 
+// Position returns the cursor position, in runes from the start of the value.
+func (m Model) Position() int {
+	return m.pos
+}
+
 // CursorStart moves the cursor to the start of the field. Returns whether or
 // not the curosr blink should be reset.
 func (m *Model) CursorStart() bool {
@@ -323,6 +376,39 @@ func (m *Model) colorText(s string) string {
 		String()
 }
 
+// colorStyled colorizes a given string according to a style, on the
+// BackgroundColor of the model.
+func (m *Model) colorStyled(s string, style Style) string {
+	styled := termenv.
+		String(s).
+		Foreground(color(style.Color)).
+		Background(color(m.BackgroundColor))
+	if style.Underline {
+		styled = styled.Underline()
+	}
+	return styled.String()
+}
+
+// colorValue colorizes the visible runes of the value from start up to end,
+// one run of runes with the same style at a time.
+func (m *Model) colorValue(start, end int) string {
+	if start >= end {
+		return m.colorText("")
+	}
+
+	v := ""
+	for i := start; i < end; {
+		style := m.styleAt(m.offset + i)
+		j := i + 1
+		for j < end && m.styleAt(m.offset+j) == style {
+			j++
+		}
+		v += m.colorStyled(m.echoTransform(string(m.value[m.offset+i:m.offset+j])), style)
+		i = j
+	}
+	return v
+}
+
 // colorSuggestion colorizes a given string according to the SuggestionColor value of the
 // model.
 func (m *Model) colorSuggestion(s string) string {
@@ -599,11 +685,11 @@ func (m Model) View() string {
 
 	value := m.value[m.offset:m.offsetRight]
 	pos := max(0, m.pos-m.offset)
-	v := m.colorText(m.echoTransform(string(value[:pos])))
+	v := m.colorValue(0, pos)
 
 	if pos < len(value) {
 		v += m.normalCursorView(m.echoTransform(string(value[pos]))) // cursor and text under it
-		v += m.colorText(m.echoTransform(string(value[pos+1:])))     // text after cursor
+		v += m.colorValue(pos+1, len(value))                         // text after cursor
 		v += m.colorSuggestion(string(m.suggestValue))
 		// v += termenv.String(" ").Background(color(m.BackgroundColor)).String() // add extra space so moving cursor back doesn't shift the suggestion
 	} else {
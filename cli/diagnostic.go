@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	"samvasta.com/bujit/cli/outputview"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/parse"
+)
+
+// markDiagnostic echoes the command after the prompt with the span the diagnostic points at underlined, and a line of
+// carets beneath it. Returns an empty string when there is no diagnostic.
+func markDiagnostic(prompt, command string, diagnostic *parse.Diagnostic) string {
+	if diagnostic == nil {
+		return ""
+	}
+
+	before := prompt + command[:diagnostic.Start]
+	span := command[diagnostic.Start:diagnostic.End]
+	after := command[diagnostic.End:]
+
+	width := rw.StringWidth(span)
+	if width == 0 {
+		// something is missing, so point just past the end of the input
+		width = 1
+		if !strings.HasSuffix(before, " ") {
+			before += " "
+		}
+	}
+
+	errorColor := outputview.TerminalColor(output.Error)
+
+	sb := strings.Builder{}
+	sb.WriteString(before)
+	sb.WriteString(termenv.String(span).Underline().Foreground(errorColor).String())
+	sb.WriteString(after)
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", rw.StringWidth(before)))
+	sb.WriteString(termenv.String(strings.Repeat("^", width)).Foreground(errorColor).String())
+
+	return sb.String()
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestMarkDiagnostic(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(command string, expectedEcho, expectedMarker string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, suggestion := parse.ParseExpression(command, &s)
+
+			lines := strings.Split(ansiSequence.ReplaceAllString(markDiagnostic("> ", command, suggestion.Diagnostic), ""), "\n")
+
+			assert.Equal(t, []string{expectedEcho, expectedMarker}, lines)
+		}
+	}
+
+	t.Run("unknown token", testCase("list acount -n=cash", "> list acount -n=cash", "       ^^^^^^"))
+	t.Run("missing token", testCase("new account", "> new account ", "              ^"))
+	t.Run("parsed", func(t *testing.T) {
+		_, suggestion := parse.ParseExpression("list account", &s)
+		assert.Empty(t, markDiagnostic("> ", "list account", suggestion.Diagnostic))
+	})
+}
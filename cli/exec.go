@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"samvasta.com/bujit/cli/jsonview"
+	"samvasta.com/bujit/config"
+	"samvasta.com/bujit/parse"
+)
+
+// Exec runs the script named in args, or stdin when the name is -, one command per line. Returns the exit code for the
+// process.
+func Exec(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	outputFlag := flags.String("output", "text", "output format: text, json or jsonl")
+	continueOnError := flags.Bool("continue-on-error", false, "keep running after a command fails")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	mode, err := ParseOutputMode(*outputFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: bujit exec [--ledger=<path>] [--output=text|json|jsonl] [--continue-on-error] <file|->")
+		return exitUsage
+	}
+
+	script := stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+		defer file.Close()
+		script = file
+	}
+
+	executor := parse.ScriptExecutor{
+		Session:         OpenLedger(*ledger),
+		ContinueOnError: *continueOnError,
+		OnLine: func(line parse.ScriptLine) {
+			writeScriptLine(mode, line, stdout, stderr)
+		},
+	}
+
+	result, err := executor.Execute(script)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+
+	if !mode.IsJSON() {
+		fmt.Fprint(stdout, render(result.Summary(), nil))
+	}
+
+	if !result.IsSuccessful() {
+		return exitFailure
+	}
+	return exitSuccess
+}
+
+// writeScriptLine writes the output of one line of a script as soon as it has run
+func writeScriptLine(mode OutputMode, line parse.ScriptLine, stdout, stderr io.Writer) {
+	if mode.IsJSON() {
+		if !line.Parsed {
+			fmt.Fprint(stdout, mode.writeDocument(jsonview.ParseErrorDocument(line.Command, fmt.Sprintf("%s: %s", line.Location(), line.Problem))))
+		} else {
+			fmt.Fprint(stdout, formatResult(mode, line.Command, line.Result, line.Consequences))
+		}
+		return
+	}
+
+	if !line.Parsed {
+		fmt.Fprintf(stderr, "%s: %s\n", line.Location(), line.Problem)
+		return
+	}
+	fmt.Fprint(stdout, render(line.Result.Output, line.Consequences))
+}
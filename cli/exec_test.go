@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecStdin(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	stdin := strings.NewReader("# accounts\nnew account cash\nlist account\n")
+
+	code := Exec([]string{"--ledger=file::memory:", "-"}, stdin, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "cash")
+	assert.Contains(t, stdout.String(), "Ran 2 commands, 0 failed")
+}
+
+func TestExecStopsAtFailure(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	stdin := strings.NewReader("new account cash\nlsit account\nnew account savings\n")
+
+	code := Exec([]string{"--ledger=file::memory:", "--output=jsonl", "-"}, stdin, &stdout, &stderr)
+
+	assert.Equal(t, exitFailure, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `2:1: unknown command \"lsit\"`)
+}
+
+func TestExecContinueOnError(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	stdin := strings.NewReader("new account cash\nlsit account\nnew account savings\n")
+
+	code := Exec([]string{"--ledger=file::memory:", "--continue-on-error", "-"}, stdin, &stdout, &stderr)
+
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr.String(), `2:1: unknown command "lsit" — did you mean "list"?`)
+	assert.Contains(t, stdout.String(), "Ran 3 commands, 1 failed")
+}
+
+func TestExecWithoutScript(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Exec([]string{"--ledger=file::memory:"}, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "usage")
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"unicode/utf8"
+
+	"samvasta.com/bujit/cli/customtext"
+	"samvasta.com/bujit/parse"
+)
+
+// tokenColors are the colors of each kind of token in the input
+var tokenColors = map[parse.TokenKind]string{
+	parse.VerbToken:      "12",
+	parse.NounToken:      "14",
+	parse.FlagToken:      "13",
+	parse.ValueToken:     "",
+	parse.KeywordToken:   "3",
+	parse.SeparatorToken: "8",
+	parse.InvalidToken:   "9",
+}
+
+// inputStyles colors the tokens of the input by what they are, with the token the parser stopped at underlined in red
+func inputStyles(input string, suggestion parse.AutoSuggestion) []customtext.Style {
+	styles := []customtext.Style{}
+	for _, span := range parse.Highlight(input, suggestion) {
+		// the input is edited in runes, but spans are in bytes
+		start := utf8.RuneCountInString(input[:span.Start])
+		end := start + utf8.RuneCountInString(input[span.Start:span.End])
+		styles = append(styles, customtext.Style{Start: start, End: end, Color: tokenColors[span.Kind], Underline: span.Kind == parse.InvalidToken})
+	}
+	return styles
+}
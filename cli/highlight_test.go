@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/cli/customtext"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+func TestInputStyles(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	input := "new account café -d=lätte --hrad"
+	_, suggestion := parse.ParseExpression(input, &s)
+
+	assert.Equal(t, []customtext.Style{
+		{Start: 0, End: 3, Color: "12"},
+		{Start: 4, End: 11, Color: "14"},
+		{Start: 12, End: 16, Color: ""},
+		{Start: 17, End: 20, Color: "13"},
+		{Start: 20, End: 25, Color: ""},
+		{Start: 26, End: 32, Color: "9", Underline: true},
+	}, inputStyles(input, suggestion))
+}
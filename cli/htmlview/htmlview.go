@@ -0,0 +1,245 @@
+package htmlview
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+)
+
+const stylesheet = `body { font-family: sans-serif; max-width: 60em; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.2em 0.8em; }
+thead th { border-bottom: 1px solid #888; }
+tfoot td { border-top: 1px solid #888; font-weight: bold; }
+.align-left { text-align: left; }
+.align-center { text-align: center; }
+.align-right { text-align: right; }
+.bar { display: inline-block; height: 0.8em; background: currentColor; }
+.color-body { color: inherit; }
+.color-subtle { color: #888; }
+.color-primary { color: #0aa; }
+.color-success { color: #2a2; }
+.color-info { color: #36c; }
+.color-warning { color: #c90; }
+.color-error { color: #c22; }
+`
+
+// View renders the output of an action as a standalone HTML document.
+func View(item interface{}, consequences []*actions.Consequence) string {
+	helpers, ok := actions.Helpers(item, consequences)
+	if !ok {
+		return Document(fmt.Sprintf("<p>%s</p>\n", html.EscapeString(fmt.Sprintf("%v", item))))
+	}
+	return Document(Render(helpers))
+}
+
+// Document wraps body in a complete HTML page with the stylesheet for the color classes.
+func Document(body string) string {
+	sb := strings.Builder{}
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>bujit</title>\n")
+	sb.WriteString("<style>\n" + stylesheet + "</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// Render converts the helpers to an HTML fragment.
+func Render(helpers []output.Helper) string {
+	sb := strings.Builder{}
+	for _, helper := range helpers {
+		sb.WriteString(renderHelper(helper))
+	}
+	return sb.String()
+}
+
+func renderHelper(helper output.Helper) string {
+	switch h := helper.(type) {
+	case output.Text:
+		return TextView(h)
+	case output.HorizontalRule:
+		return fmt.Sprintf("<hr class=\"%s\">\n", ColorClass(h.Style.Color))
+	case output.UnorderedList:
+		return UnorderedListView(h)
+	case output.OrderedList:
+		return OrderedListView(h)
+	case output.Table:
+		return TableView(h)
+	case output.BarChart:
+		return BarChartView(h)
+	case output.Sparkline:
+		return SparklineView(h)
+	default:
+		return ""
+	}
+}
+
+// ColorClass is the CSS class for a color hint. Unknown hints fall back to the body color.
+func ColorClass(color output.ColorHint) string {
+	switch color {
+	case output.Subtle, output.Primary, output.Success, output.Info, output.Warning, output.Error:
+		return "color-" + string(color)
+	default:
+		return "color-" + string(output.Body)
+	}
+}
+
+func styledText(text string, style output.TextStyle) string {
+	text = html.EscapeString(text)
+	if style.IsBold {
+		text = "<strong>" + text + "</strong>"
+	}
+	if style.IsItalic {
+		text = "<em>" + text + "</em>"
+	}
+	if style.IsUnderline {
+		text = "<u>" + text + "</u>"
+	}
+	return text
+}
+
+func indentAttr(indent int) string {
+	if indent <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" style=\"margin-left: %dem\"", indent*2)
+}
+
+func TextView(t output.Text) string {
+	if strings.TrimSpace(t.Text) == "" {
+		return strings.Repeat("<br>\n", strings.Count(t.Text, "\n"))
+	}
+
+	if t.Style == *output.HeaderStyle {
+		return fmt.Sprintf("<h3 class=\"%s\"%s>%s</h3>\n", ColorClass(t.Style.Color), indentAttr(t.Indent), html.EscapeString(t.Text))
+	}
+	return fmt.Sprintf("<p class=\"%s\"%s>%s</p>\n", ColorClass(t.Style.Color), indentAttr(t.Indent), styledText(t.Text, t.Style))
+}
+
+func listItems(items []output.Text) string {
+	sb := strings.Builder{}
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("<li class=\"%s\">%s", ColorClass(item.Style.Color), styledText(item.Text, item.Style)))
+		if len(item.Children) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(Render(item.Children))
+		}
+		sb.WriteString("</li>\n")
+	}
+	return sb.String()
+}
+
+func UnorderedListView(ul output.UnorderedList) string {
+	return fmt.Sprintf("<ul class=\"%s\" style=\"list-style-type: '%s '\">\n%s</ul>\n",
+		ColorClass(ul.Style.Color), html.EscapeString(ul.BulletChar), listItems(ul.Items))
+}
+
+// ListType is the value of the HTML type attribute for an ordered list bullet style.
+func ListType(style output.BulletStyle) string {
+	switch style {
+	case output.UpperAlpha:
+		return "A"
+	case output.LowerAlpha:
+		return "a"
+	case output.UpperRoman:
+		return "I"
+	case output.LowerRoman:
+		return "i"
+	default:
+		return "1"
+	}
+}
+
+func OrderedListView(ol output.OrderedList) string {
+	return fmt.Sprintf("<ol class=\"%s\" type=\"%s\">\n%s</ol>\n", ColorClass(ol.Style.Color), ListType(ol.BulletStyle), listItems(ol.Items))
+}
+
+func tableCells(tag string, columns []output.TableColumn, row []output.TableCell, defaultColor output.ColorHint) string {
+	sb := strings.Builder{}
+	sb.WriteString("<tr>")
+	for i, col := range columns {
+		var cell output.TableCell
+		if i < len(row) {
+			cell = row[i]
+		}
+		color := defaultColor
+		if cell.Color != "" {
+			color = cell.Color
+		}
+		sb.WriteString(fmt.Sprintf("<%s class=\"align-%s %s %s\">%s</%s>", tag, col.Align, col.Kind, ColorClass(color), html.EscapeString(cell.Text), tag))
+	}
+	sb.WriteString("</tr>\n")
+	return sb.String()
+}
+
+func TableView(t output.Table) string {
+	headers := make([]output.TableCell, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = output.Cell(col.Header)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<table%s>\n", indentAttr(t.Indent)))
+	sb.WriteString("<thead>\n" + tableCells("th", t.Columns, headers, t.HeaderStyle.Color) + "</thead>\n")
+
+	sb.WriteString("<tbody>\n")
+	for _, row := range t.Rows {
+		sb.WriteString(tableCells("td", t.Columns, row, t.Style.Color))
+	}
+	sb.WriteString("</tbody>\n")
+
+	if len(t.Footers) > 0 {
+		sb.WriteString("<tfoot>\n")
+		for _, row := range t.Footers {
+			sb.WriteString(tableCells("td", t.Columns, row, t.Style.Color))
+		}
+		sb.WriteString("</tfoot>\n")
+	}
+
+	sb.WriteString("</table>\n")
+	return sb.String()
+}
+
+// BarChartView renders the chart as a table where each bar's width is a percentage of the largest magnitude.
+func BarChartView(bc output.BarChart) string {
+	maxMagnitude := 0.0
+	for _, bar := range bc.Bars {
+		maxMagnitude = math.Max(maxMagnitude, math.Abs(bar.Value))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<table class=\"bar-chart\"%s>\n", indentAttr(bc.Indent)))
+	if bc.Title != "" {
+		sb.WriteString(fmt.Sprintf("<caption>%s</caption>\n", html.EscapeString(bc.Title)))
+	}
+	for _, bar := range bc.Bars {
+		color := bc.Style.Color
+		if bar.Value < 0 {
+			color = output.Error
+		} else if bar.Color != "" {
+			color = bar.Color
+		}
+
+		percent := 0.0
+		if maxMagnitude > 0 {
+			percent = math.Abs(bar.Value) / maxMagnitude * 100
+		}
+
+		sb.WriteString(fmt.Sprintf("<tr class=\"%s\"><td>%s</td><td style=\"width: 20em\"><span class=\"bar\" style=\"width: %.1f%%\"></span></td><td class=\"align-right\">%s</td></tr>\n",
+			ColorClass(color), html.EscapeString(bar.Label), percent, html.EscapeString(bar.Display)))
+	}
+	sb.WriteString("</table>\n")
+	return sb.String()
+}
+
+func SparklineView(sl output.Sparkline) string {
+	label := ""
+	if sl.Label != "" {
+		label = html.EscapeString(sl.Label) + " "
+	}
+	return fmt.Sprintf("<p class=\"sparkline %s\"%s>%s<span>%s</span></p>\n", ColorClass(sl.Style.Color), indentAttr(sl.Indent), label, output.SparklineText(sl.Values))
+}
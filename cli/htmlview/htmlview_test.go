@@ -0,0 +1,64 @@
+package htmlview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models/output"
+)
+
+func TestRender(t *testing.T) {
+	group := output.EmptyOutputGroup()
+	nested := group.SubGroup().OrderedList([]string{"child"}, output.LowerRoman).ToSlice()
+
+	helpers := group.
+		Header("Syntax: new account <name>").
+		PushStyle(output.TextStyle{Color: output.Warning, IsItalic: true}).
+		Paragraph("Careful & quick").
+		PopStyle().
+		UnorderedListItems([]output.Text{group.Item("parent", nested...)}, "•").
+		Table([]output.TableColumn{
+			output.MakeColumn("Name", output.TextColumn),
+			output.MakeColumn("Balance", output.MoneyColumn),
+		}, [][]output.TableCell{
+			{output.Cell("cash"), output.ColoredCell("(1.00)", output.Error)},
+		}).
+		ToSlice()
+
+	expected := `<h3 class="color-body">Syntax: new account &lt;name&gt;</h3>
+<p class="color-warning"><em>Careful &amp; quick</em></p>
+<ul class="color-body" style="list-style-type: '• '">
+<li class="color-body">parent
+<ol class="color-body" type="i">
+<li class="color-body">child</li>
+</ol>
+</li>
+</ul>
+<table>
+<thead>
+<tr><th class="align-left text color-body">Name</th><th class="align-right money color-body">Balance</th></tr>
+</thead>
+<tbody>
+<tr><td class="align-left text color-body">cash</td><td class="align-right money color-error">(1.00)</td></tr>
+</tbody>
+</table>
+`
+
+	assert.Equal(t, expected, Render(helpers))
+}
+
+func TestColorClass(t *testing.T) {
+	assert.Equal(t, "color-error", ColorClass(output.Error))
+	assert.Equal(t, "color-subtle", ColorClass(output.Subtle))
+	assert.Equal(t, "color-body", ColorClass("not a color"))
+}
+
+func TestView(t *testing.T) {
+	document := View(output.EmptyOutputGroup().BarChart("", []output.Bar{{Label: "credit", Value: -1, Display: "(1.00)"}}).ToSlice(), nil)
+
+	assert.True(t, strings.HasPrefix(document, "<!DOCTYPE html>"))
+	assert.Contains(t, document, `<tr class="color-error"><td>credit</td>`)
+	assert.Contains(t, document, `width: 100.0%`)
+	assert.True(t, strings.HasSuffix(document, "</html>\n"))
+}
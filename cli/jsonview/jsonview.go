@@ -0,0 +1,65 @@
+package jsonview
+
+import (
+	"encoding/json"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+)
+
+// Document is the machine readable result of running one command.
+type Document struct {
+	Command      string                 `json:"command"`
+	Success      bool                   `json:"success"`
+	Output       interface{}            `json:"output"` // []output.Helper when the output can be rendered, otherwise the raw output
+	Consequences []*actions.Consequence `json:"consequences"`
+}
+
+func MakeDocument(command string, result actions.ActionResult, consequences []*actions.Consequence) Document {
+	var out interface{} = result.Output
+	if helpers, ok := actions.Helpers(result.Output, consequences); ok {
+		if helpers == nil {
+			helpers = []output.Helper{}
+		}
+		out = helpers
+	}
+
+	if consequences == nil {
+		consequences = []*actions.Consequence{}
+	}
+
+	return Document{Command: command, Success: result.IsSuccessful, Output: out, Consequences: consequences}
+}
+
+// ParseErrorDocument describes a command that could not be parsed.
+func ParseErrorDocument(command string, message string) Document {
+	return Document{
+		Command:      command,
+		Success:      false,
+		Output:       output.EmptyOutputGroup().PushStyle(output.TextStyle{Color: output.Error}).Paragraph(message).ToSlice(),
+		Consequences: []*actions.Consequence{},
+	}
+}
+
+// View renders the document as indented JSON.
+func View(doc Document) string {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return errorJSON(err)
+	}
+	return string(b) + "\n"
+}
+
+// Line renders the document as JSON on a single line, for streaming one document per command.
+func Line(doc Document) string {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return errorJSON(err)
+	}
+	return string(b) + "\n"
+}
+
+func errorJSON(err error) string {
+	b, _ := json.Marshal(map[string]interface{}{"success": false, "error": err.Error()})
+	return string(b) + "\n"
+}
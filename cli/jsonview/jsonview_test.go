@@ -0,0 +1,61 @@
+package jsonview
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+func TestMakeDocument(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	account := models.Account{ID: 3, Name: "cash", IsActive: true, Session: &s}
+	result := actions.ActionResult{Output: output.EmptyOutputGroup().Paragraph("created").ToSlice(), IsSuccessful: true}
+	consequences := []*actions.Consequence{{ConsequenceType: actions.CREATE, Object: account}}
+
+	doc := MakeDocument("new account cash", result, consequences)
+
+	var decoded struct {
+		Command      string            `json:"command"`
+		Success      bool              `json:"success"`
+		Output       []json.RawMessage `json:"output"`
+		Consequences []struct {
+			Type   string                 `json:"type"`
+			Kind   string                 `json:"kind"`
+			Object map[string]interface{} `json:"object"`
+		} `json:"consequences"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(View(doc)), &decoded))
+
+	assert.Equal(t, "new account cash", decoded.Command)
+	assert.True(t, decoded.Success)
+	assert.Len(t, decoded.Output, 1)
+
+	helper, err := output.DecodeHelper(decoded.Output[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "created", helper.(output.Text).Text)
+
+	assert.Len(t, decoded.Consequences, 1)
+	assert.Equal(t, "create", decoded.Consequences[0].Type)
+	assert.Equal(t, "account", decoded.Consequences[0].Kind)
+	assert.Equal(t, "cash", decoded.Consequences[0].Object["name"])
+}
+
+func TestMakeDocumentWithoutOutput(t *testing.T) {
+	doc := MakeDocument("help", actions.ActionResult{IsSuccessful: false}, nil)
+
+	assert.Equal(t, `{"command":"help","success":false,"output":[],"consequences":[]}`+"\n", Line(doc))
+}
+
+func TestParseErrorDocument(t *testing.T) {
+	line := Line(ParseErrorDocument("lsit", "invalid command: lsit"))
+
+	assert.Equal(t, 1, strings.Count(line, "\n"))
+	assert.Contains(t, line, `"success":false`)
+	assert.Contains(t, line, "invalid command: lsit")
+}
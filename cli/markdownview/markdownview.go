@@ -0,0 +1,178 @@
+package markdownview
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+)
+
+const chartWidth = 20
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`|`, `\|`,
+)
+
+// View renders the output of an action as GitHub flavoured markdown.
+func View(item interface{}, consequences []*actions.Consequence) string {
+	helpers, ok := actions.Helpers(item, consequences)
+	if !ok {
+		return escaper.Replace(fmt.Sprintf("%v", item)) + "\n"
+	}
+	return Render(helpers)
+}
+
+// Render converts the helpers to markdown blocks separated by blank lines.
+func Render(helpers []output.Helper) string {
+	blocks := []string{}
+	for _, helper := range helpers {
+		if block := renderHelper(helper, ""); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, "\n")
+}
+
+func renderHelper(helper output.Helper, prefix string) string {
+	switch h := helper.(type) {
+	case output.Text:
+		return TextView(h)
+	case output.HorizontalRule:
+		return "---\n"
+	case output.UnorderedList:
+		return listView(h.Items, func(int) string { return "-" }, prefix)
+	case output.OrderedList:
+		return listView(h.Items, func(n int) string { return fmt.Sprintf("%d.", n) }, prefix)
+	case output.Table:
+		return TableView(h)
+	case output.BarChart:
+		return BarChartView(h)
+	case output.Sparkline:
+		return SparklineView(h)
+	default:
+		return ""
+	}
+}
+
+func emphasis(text string, style output.TextStyle) string {
+	if style.IsBold {
+		text = "**" + text + "**"
+	}
+	if style.IsItalic {
+		text = "_" + text + "_"
+	}
+	return text
+}
+
+func TextView(t output.Text) string {
+	if strings.TrimSpace(t.Text) == "" {
+		return ""
+	}
+
+	text := escaper.Replace(t.Text)
+
+	if t.Style == *output.HeaderStyle {
+		return fmt.Sprintf("### %s\n", text)
+	}
+	return emphasis(text, t.Style) + "\n"
+}
+
+// listView writes one item per line. Nested lists are indented to line up with the text of their parent item.
+func listView(items []output.Text, marker func(n int) string, prefix string) string {
+	sb := strings.Builder{}
+	for i, item := range items {
+		m := marker(i + 1)
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", prefix, m, emphasis(escaper.Replace(item.Text), item.Style)))
+
+		childPrefix := prefix + strings.Repeat(" ", len(m)+1)
+		for _, child := range item.Children {
+			sb.WriteString(renderHelper(child, childPrefix))
+		}
+	}
+	return sb.String()
+}
+
+func tableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+func TableView(t output.Table) string {
+	headers := make([]string, len(t.Columns))
+	alignments := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = escaper.Replace(col.Header)
+		switch col.Align {
+		case output.AlignRight:
+			alignments[i] = "---:"
+		case output.AlignCenter:
+			alignments[i] = ":---:"
+		default:
+			alignments[i] = "---"
+		}
+	}
+
+	cells := func(row []output.TableCell, bold bool) []string {
+		texts := make([]string, len(t.Columns))
+		for i := 0; i < len(row) && i < len(texts); i++ {
+			texts[i] = escaper.Replace(row[i].Text)
+			if bold && texts[i] != "" {
+				texts[i] = "**" + texts[i] + "**"
+			}
+		}
+		return texts
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(tableRow(headers))
+	sb.WriteString(tableRow(alignments))
+	for _, row := range t.Rows {
+		sb.WriteString(tableRow(cells(row, false)))
+	}
+	for _, row := range t.Footers {
+		sb.WriteString(tableRow(cells(row, true)))
+	}
+	return sb.String()
+}
+
+// BarChartView renders the chart as a table with a fixed width bar column.
+func BarChartView(bc output.BarChart) string {
+	maxMagnitude := 0.0
+	for _, bar := range bc.Bars {
+		maxMagnitude = math.Max(maxMagnitude, math.Abs(bar.Value))
+	}
+
+	sb := strings.Builder{}
+	if bc.Title != "" {
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", escaper.Replace(bc.Title)))
+	}
+	sb.WriteString(tableRow([]string{"", "", ""}))
+	sb.WriteString(tableRow([]string{"---", "---", "---:"}))
+	for _, bar := range bc.Bars {
+		length := 0
+		if maxMagnitude > 0 {
+			length = int(math.Round(math.Abs(bar.Value) / maxMagnitude * chartWidth))
+		}
+		sb.WriteString(tableRow([]string{escaper.Replace(bar.Label), strings.Repeat("█", length), escaper.Replace(bar.Display)}))
+	}
+	return sb.String()
+}
+
+func SparklineView(sl output.Sparkline) string {
+	line := output.SparklineText(sl.Values)
+
+	if sl.Label != "" {
+		return fmt.Sprintf("%s: `%s`\n", escaper.Replace(sl.Label), line)
+	}
+	return fmt.Sprintf("`%s`\n", line)
+}
@@ -0,0 +1,60 @@
+package markdownview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models/output"
+)
+
+func TestRender(t *testing.T) {
+	group := output.EmptyOutputGroup()
+	nested := group.SubGroup().OrderedList([]string{"child"}, output.UpperRoman).ToSlice()
+
+	helpers := group.
+		Header("Syntax: new account <name>").
+		HorizontalRule("═").
+		Paragraph("Creates an *account*.").
+		EmptyLines(1).
+		UnorderedListItems([]output.Text{group.Item("parent", nested...), group.Item("sibling")}, output.NormalBulletChar).
+		Table([]output.TableColumn{
+			output.MakeColumn("Name", output.TextColumn),
+			output.MakeColumn("Balance", output.MoneyColumn),
+		}, [][]output.TableCell{
+			{output.Cell("cash|wallet"), output.ColoredCell("(1.00)", output.Error)},
+		}, []output.TableCell{output.Cell("Total"), output.Cell("(1.00)")}).
+		BarChart("Balances", []output.Bar{{Label: "cash", Value: 2, Display: "2.00"}, {Label: "credit", Value: -1, Display: "(1.00)"}}).
+		Sparkline("Trend", []float64{0, 7}).
+		ToSlice()
+
+	expected := `### Syntax: new account \<name\>
+
+---
+
+Creates an \*account\*.
+
+- parent
+  1. child
+- sibling
+
+| Name | Balance |
+| --- | ---: |
+| cash\|wallet | (1.00) |
+| **Total** | **(1.00)** |
+
+**Balances**
+
+|  |  |  |
+| --- | --- | ---: |
+| cash | ████████████████████ | 2.00 |
+| credit | ██████████ | (1.00) |
+
+Trend: ` + "`▁█`" + `
+`
+
+	assert.Equal(t, expected, Render(helpers))
+}
+
+func TestView(t *testing.T) {
+	assert.Equal(t, "goodbye\n", View("goodbye", nil))
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	"samvasta.com/bujit/cli/outputview"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/parse"
+)
+
+// menuHeight is the most completions shown under the prompt at once
+const menuHeight = 8
+
+// completionMenu is shown under the prompt while Tab cycles through the completions of the word before the cursor
+type completionMenu struct {
+	completion parse.Completion // the completions of the line as it was before the first Tab
+	selected   int              // the completion that is in the input
+}
+
+func (menu completionMenu) current() string {
+	return menu.completion.Completions[menu.selected]
+}
+
+// updateMenu puts the next completion in the input on Tab, or the previous one on Shift-Tab. The first Tab puts in the
+// first completion, which is the one shown as the ghost text.
+func (m *model) updateMenu(key tea.KeyMsg) {
+	if m.menu == nil {
+		completion := parse.Complete(m.textInput.Value(), m.textInput.Position(), m.session)
+		if len(completion.Completions) == 0 {
+			return
+		}
+		m.menu = &completionMenu{completion: completion, selected: -1}
+	}
+
+	count := len(m.menu.completion.Completions)
+	switch {
+	case key.Type == tea.KeyShiftTab && m.menu.selected < 0:
+		m.menu.selected = count - 1
+	case key.Type == tea.KeyShiftTab:
+		m.menu.selected = (m.menu.selected + count - 1) % count
+	default:
+		m.menu.selected = (m.menu.selected + 1) % count
+	}
+
+	m.replaceWord(m.menu.current())
+	m.textInput.SetSuggestValue("")
+	m.suggestion = parse.EmptySuggestions
+}
+
+// replaceWord puts text in place of the word that was completed, keeping what was after the cursor
+func (m *model) replaceWord(text string) {
+	line := []rune(m.menu.completion.Line)
+	start, cursor := m.menu.completion.Start, m.menu.completion.Cursor
+
+	value := string(line[:start]) + text + string(line[cursor:])
+	m.textInput.SetValue(value)
+	m.textInput.SetCursor(start + len([]rune(text)))
+}
+
+// closeMenu leaves the completion that was chosen in the input. When restore is true the line goes back to how it was
+// before the first Tab.
+func (m *model) closeMenu(restore bool) {
+	if restore {
+		m.textInput.SetValue(m.menu.completion.Line)
+		m.textInput.SetCursor(m.menu.completion.Cursor)
+	}
+	m.menu = nil
+}
+
+// ghostText is the rest of the first completion of the word before the cursor, shown after the cursor so Tab can
+// accept it. It is empty unless the cursor is at the end of a word that has been started.
+func ghostText(completion parse.Completion) string {
+	if completion.Word == "" || len(completion.Completions) == 0 {
+		return ""
+	}
+	first, word := []rune(completion.Completions[0]), []rune(completion.Word)
+	if len(first) < len(word) {
+		return ""
+	}
+	return string(first[len(word):])
+}
+
+// View lists the completions around the selected one, each with its description when it has one
+func (menu completionMenu) View() string {
+	completions := menu.completion.Completions
+
+	first := 0
+	if menu.selected >= menuHeight {
+		first = menu.selected - menuHeight + 1
+	}
+	last := first + menuHeight
+	if last > len(completions) {
+		last = len(completions)
+	}
+
+	width := 0
+	for _, c := range completions[first:last] {
+		if w := rw.StringWidth(c); w > width {
+			width = w
+		}
+	}
+
+	lines := []string{}
+	for i := first; i < last; i++ {
+		name := termenv.String(" " + rw.FillRight(completions[i], width) + " ")
+		if i == menu.selected {
+			name = name.Reverse()
+		}
+
+		line := "  " + name.String()
+		if description, ok := menu.completion.Descriptions[completions[i]]; ok {
+			line += " " + termenv.String(description).Foreground(outputview.TerminalColor(output.Info)).String()
+		}
+		lines = append(lines, line)
+	}
+	if len(completions) > menuHeight {
+		lines = append(lines, termenv.String(fmt.Sprintf("  %d of %d", menu.selected+1, len(completions))).Foreground(color("240")).String())
+	}
+	return strings.Join(lines, "\n")
+}
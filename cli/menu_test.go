@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+func TestCompletionMenu(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	press := func(m model, keys ...tea.KeyMsg) model {
+		for _, key := range keys {
+			updated, _ := m.Update(key)
+			m = updated.(model)
+		}
+		return m
+	}
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	shiftTab := tea.KeyMsg{Type: tea.KeyShiftTab}
+	typed := func(text string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+	}
+
+	m := press(initialModel(&s, &history{}), typed("new a"))
+	assert.Equal(t, "ccount", m.textInput.SuggestValue())
+
+	// tab accepts the ghost text
+	m = press(m, tab)
+	assert.Equal(t, "new account", m.textInput.Value())
+	assert.Equal(t, "", m.textInput.SuggestValue())
+
+	// repeated tab cycles through every completion and shift+tab goes back
+	m = press(m, typed(" cash -"), tab)
+	assert.Equal(t, "new account cash --description", m.textInput.Value())
+	m = press(m, tab)
+	assert.Equal(t, "new account cash --category", m.textInput.Value())
+	m = press(m, shiftTab, shiftTab)
+	assert.Equal(t, "new account cash --balance", m.textInput.Value())
+	assert.Contains(t, m.View(), "the balance the account starts with")
+
+	// any other key keeps the completion
+	m = press(m, typed("="))
+	assert.Nil(t, m.menu)
+	assert.Equal(t, "new account cash --balance=", m.textInput.Value())
+
+	// esc puts back the word that was being completed
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlU}, typed("new "), tab, tab, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, m.menu)
+	assert.False(t, m.history.exit)
+	assert.Equal(t, "new ", m.textInput.Value())
+}
+
+func TestGhostText(t *testing.T) {
+	assert.Equal(t, "st", ghostText(parse.Completion{Word: "li", Completions: []string{"list"}}))
+	assert.Equal(t, "", ghostText(parse.Completion{Word: "", Completions: []string{"list"}}))
+	assert.Equal(t, "", ghostText(parse.Completion{Word: "lx", Completions: []string{}}))
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/cli/jsonview"
+)
+
+// OutputMode selects how the result of a command is written.
+type OutputMode string
+
+const (
+	OutputText      OutputMode = ""      // rendered for a person to read
+	OutputJSON      OutputMode = "json"  // one indented JSON document per command
+	OutputJSONLines OutputMode = "jsonl" // one JSON document per line, for streaming several commands
+)
+
+// ParseOutputMode validates the value of an --output flag.
+func ParseOutputMode(value string) (OutputMode, error) {
+	switch mode := OutputMode(value); mode {
+	case OutputText, "text", OutputJSON, OutputJSONLines:
+		if mode == "text" {
+			return OutputText, nil
+		}
+		return mode, nil
+	default:
+		return OutputText, fmt.Errorf("unknown output mode %q, expected one of: text, json, jsonl", value)
+	}
+}
+
+func (mode OutputMode) IsJSON() bool {
+	return mode == OutputJSON || mode == OutputJSONLines
+}
+
+// writeDocument renders doc in the JSON flavor chosen by mode
+func (mode OutputMode) writeDocument(doc jsonview.Document) string {
+	if mode == OutputJSONLines {
+		return jsonview.Line(doc)
+	}
+	return jsonview.View(doc)
+}
+
+// formatResult renders the result of command in the given mode
+func formatResult(mode OutputMode, command string, result actions.ActionResult, consequences []*actions.Consequence) string {
+	if mode.IsJSON() {
+		return mode.writeDocument(jsonview.MakeDocument(command, result, consequences))
+	}
+	return render(result.Output, consequences)
+}
@@ -0,0 +1,142 @@
+package outputview
+
+import (
+	"math"
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"samvasta.com/bujit/models/output"
+)
+
+// Eighths of a full block, from empty to full
+var barBlocks = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+func BarChartView(bc output.BarChart) string {
+	return RenderBarChart(bc, terminalWidth())
+}
+
+// RenderBarChart renders one bar per line, scaled so the largest magnitude fills the space left over after the labels
+// and values. Negative values are drawn with the error color.
+func RenderBarChart(bc output.BarChart, width int) string {
+	indent := strings.Repeat("  ", bc.Indent)
+
+	labelWidth, displayWidth := 0, 0
+	maxMagnitude := 0.0
+	for _, bar := range bc.Bars {
+		labelWidth = max(labelWidth, rw.StringWidth(bar.Label))
+		displayWidth = max(displayWidth, rw.StringWidth(bar.Display))
+		maxMagnitude = math.Max(maxMagnitude, math.Abs(bar.Value))
+	}
+
+	barWidth := width - len(indent) - labelWidth - displayWidth - 2*len(columnGap)
+	if barWidth < 1 {
+		// truncate the labels to make room for at least one block
+		labelWidth = max(minColumnWidth, labelWidth+barWidth-1)
+		barWidth = 1
+	}
+
+	sb := strings.Builder{}
+
+	if bc.Title != "" {
+		titleStyle := bc.Style
+		titleStyle.IsBold = true
+		sb.WriteString(indent + styled(bc.Title, titleStyle) + "\n")
+	}
+
+	for _, bar := range bc.Bars {
+		style := bc.Style
+		if bar.Value < 0 {
+			style.Color = output.Error
+		} else if bar.Color != "" {
+			style.Color = bar.Color
+		}
+
+		eighths := 0
+		if maxMagnitude > 0 {
+			eighths = int(math.Round(math.Abs(bar.Value) / maxMagnitude * float64(barWidth*8)))
+		}
+
+		sb.WriteString(indent)
+		sb.WriteString(alignCell(bar.Label, labelWidth, output.AlignLeft))
+		sb.WriteString(columnGap)
+		sb.WriteString(styled(alignCell(blockBar(eighths), barWidth, output.AlignLeft), style))
+		sb.WriteString(columnGap)
+		sb.WriteString(styled(alignCell(bar.Display, displayWidth, output.AlignRight), style))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// blockBar draws a bar that is eighths/8 characters long
+func blockBar(eighths int) string {
+	bar := strings.Repeat(string(barBlocks[8]), eighths/8)
+	if eighths%8 > 0 {
+		bar += string(barBlocks[eighths%8])
+	}
+	return bar
+}
+
+func SparklineView(sl output.Sparkline) string {
+	return RenderSparkline(sl, terminalWidth())
+}
+
+// RenderSparkline draws each value as a block whose height is relative to the smallest and largest values. When there
+// are more values than room on the line, neighbouring values are averaged together.
+func RenderSparkline(sl output.Sparkline, width int) string {
+	indent := strings.Repeat("  ", sl.Indent)
+
+	prefix := indent
+	if sl.Label != "" {
+		prefix += sl.Label + columnGap
+	}
+
+	values := resample(sl.Values, width-rw.StringWidth(prefix))
+	if len(values) == 0 {
+		return prefix + "\n"
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(styled(prefix, sl.Style))
+
+	for i, level := range output.SparkLevels(values) {
+		style := sl.Style
+		if values[i] < 0 {
+			style.Color = output.Error
+		}
+		sb.WriteString(styled(string(output.SparkBlocks[level]), style))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// resample averages neighbouring values so at most width values remain
+func resample(values []float64, width int) []float64 {
+	if width < 1 {
+		return []float64{}
+	}
+	if len(values) <= width {
+		return values
+	}
+
+	resampled := make([]float64, width)
+	for i := range resampled {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+
+		total := 0.0
+		for _, v := range values[start:end] {
+			total += v
+		}
+		resampled[i] = total / float64(end-start)
+	}
+	return resampled
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
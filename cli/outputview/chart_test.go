@@ -0,0 +1,66 @@
+package outputview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models/output"
+)
+
+func TestRenderBarChart(t *testing.T) {
+	chart := output.EmptyOutputGroup().
+		BarChart("Balances", []output.Bar{
+			{Label: "cash", Value: 10, Display: "10.00"},
+			{Label: "checking", Value: 5, Display: "5.00"},
+			{Label: "credit", Value: -2.5, Display: "(2.50)"},
+		}).
+		ToSlice()[0].(output.BarChart)
+
+	rendered := stripANSI(RenderBarChart(chart, 30))
+
+	expected :=
+		`Balances
+cash      ████████████   10.00
+checking  ██████          5.00
+credit    ███           (2.50)
+`
+	assert.Equal(t, expected, rendered)
+}
+
+func TestRenderBarChart_NegativeIsError(t *testing.T) {
+	chart := output.BarChart{Bars: []output.Bar{{Label: "credit", Value: -1, Display: "(1.00)"}}, Style: *output.DefaultStyle}
+
+	rendered := RenderBarChart(chart, 30)
+
+	assert.Contains(t, rendered, TerminalColor(output.Error).Sequence(false))
+}
+
+func TestRenderBarChart_PartialBlocks(t *testing.T) {
+	assert.Equal(t, "", blockBar(0))
+	assert.Equal(t, "▌", blockBar(4))
+	assert.Equal(t, "█▏", blockBar(9))
+}
+
+func TestRenderSparkline(t *testing.T) {
+	sparkline := output.Sparkline{Label: "trend", Values: []float64{0, 1, 2, 3, 4, 5, 6, 7}}
+
+	rendered := stripANSI(RenderSparkline(sparkline, 80))
+
+	assert.Equal(t, "trend  ▁▂▃▄▅▆▇█\n", rendered)
+}
+
+func TestRenderSparkline_Resampled(t *testing.T) {
+	sparkline := output.Sparkline{Values: []float64{0, 0, 7, 7, 0, 0, 7, 7}}
+
+	rendered := stripANSI(RenderSparkline(sparkline, 4))
+
+	assert.Equal(t, "▁█▁█\n", rendered)
+}
+
+func TestRenderSparkline_Flat(t *testing.T) {
+	sparkline := output.Sparkline{Values: []float64{3, 3, 3}}
+
+	rendered := stripANSI(RenderSparkline(sparkline, 80))
+
+	assert.Equal(t, "███\n", rendered)
+}
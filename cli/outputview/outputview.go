@@ -3,14 +3,12 @@ package outputview
 import (
 	"fmt"
 	"regexp"
-	"sort"
+	"strconv"
 	"strings"
 
+	rw "github.com/mattn/go-runewidth"
 	"github.com/muesli/termenv"
-	"github.com/olekukonko/ts"
 	"samvasta.com/bujit/actions"
-	actions_accounts "samvasta.com/bujit/actions/accounts"
-	"samvasta.com/bujit/models"
 	"samvasta.com/bujit/models/output"
 )
 
@@ -36,74 +34,47 @@ func TerminalColor(col output.ColorHint) termenv.Color {
 }
 
 func View(item interface{}, consequences []*actions.Consequence) string {
+	return ViewWidth(item, consequences, terminalWidth())
+}
+
+// ViewWidth renders the output of an action to fit a terminal that is width columns wide.
+func ViewWidth(item interface{}, consequences []*actions.Consequence, width int) string {
 	switch i := item.(type) {
-	case actions_accounts.ListAccountOutput:
-		return ListAccountView(i, consequences)
 	case []output.Helper:
 		sb := strings.Builder{}
 		for _, helper := range i {
-			sb.WriteString(View(helper, consequences))
+			sb.WriteString(ViewWidth(helper, consequences, width))
 			sb.WriteString("\n")
 		}
 		return sb.String()
 	case output.Text:
-		return TextView(i)
+		return RenderText(i, width)
 	case output.UnorderedList:
-		return UnorderedListView(i)
+		return RenderUnorderedList(i, width)
+	case output.OrderedList:
+		return RenderOrderedList(i, width)
 	case output.HorizontalRule:
-		return HorizontalRuleView(i)
-	default:
-		return termenv.
-			String(fmt.Sprintf("!!Type %T not supported yet.!!", i)).
-			Background(termenv.ANSIBrightRed).
-			Foreground(termenv.ANSIBrightWhite).
-			String()
+		return RenderHorizontalRule(i, width)
+	case output.Table:
+		return RenderTable(i, width)
+	case output.BarChart:
+		return RenderBarChart(i, width)
+	case output.Sparkline:
+		return RenderSparkline(i, width)
+	case actions.RawOutput:
+		// written as it is, so it can be copied or redirected to a file
+		return i.RawText()
 	}
-}
-
-func ListAccountView(lao actions_accounts.ListAccountOutput, consequences []*actions.Consequence) string {
-	sortedConsequences := []*actions.Consequence{}
-	copy(sortedConsequences, consequences)
-	sort.Slice(sortedConsequences, func(a, b int) bool {
-		accountA, okA := sortedConsequences[a].Object.(models.Account)
-		accountB, okB := sortedConsequences[b].Object.(models.Account)
-
-		if !okA && !okB {
-			return true
-		}
-		if !okA {
-			return false
-		}
-		if !okB {
-			return true
-		}
 
-		if accountA.Category.FullyQualifiedName == accountB.Category.FullyQualifiedName {
-			// both accounts in same category. compare by account name
-			return accountA.Name < accountB.Name
-		} else if accountA.Category.FullyQualifiedName < accountB.Category.FullyQualifiedName {
-			return true
-		} else {
-			return false
-		}
-	})
-
-	sortedAccountNames := []string{}
-	for _, a := range sortedConsequences {
-		account, ok := a.Object.(models.Account)
-		if ok {
-			sortedAccountNames = append(sortedAccountNames, account.Name)
-		}
+	if helpers, ok := actions.Helpers(item, consequences); ok {
+		return ViewWidth(helpers, consequences, width)
 	}
 
-	if lao.Tree {
-		return "tree: "
-	} else {
-		view := output.EmptyOutputGroup().
-			UnorderedList(sortedAccountNames, output.NormalBulletChar).
-			ToSlice()
-		return View(view, consequences)
-	}
+	return termenv.
+		String(fmt.Sprintf("!!Type %T not supported yet.!!", item)).
+		Background(termenv.ANSIBrightRed).
+		Foreground(termenv.ANSIBrightWhite).
+		String()
 }
 
 var wordsRegex = regexp.MustCompile(`\s`)
@@ -178,60 +149,116 @@ func WrappedString(text string, startCol, minCol, maxCol int, terminalWidth int)
 }
 
 func TextView(t output.Text) string {
-	size, _ := ts.GetSize()
-	output := termenv.
-		String(WrappedString(t.Text, 0, t.Indent*2, size.Col(), size.Col())).
-		Foreground(TerminalColor(t.Style.Color))
+	return RenderText(t, terminalWidth())
+}
+
+func RenderText(t output.Text, width int) string {
+	indent := t.Indent * 2
+	width = max(width, indent+minColumnWidth)
 
-	if t.Style.IsBold {
-		output = output.Bold()
+	return styled(WrappedString(t.Text, 0, indent, width, width), t.Style)
+}
+
+func HorizontalRuleView(hr output.HorizontalRule) string {
+	return RenderHorizontalRule(hr, terminalWidth())
+}
+
+func RenderHorizontalRule(hr output.HorizontalRule, width int) string {
+	return styled(strings.Repeat(hr.RuleChar, width), hr.Style)
+}
+
+func UnorderedListView(ul output.UnorderedList) string {
+	return RenderUnorderedList(ul, terminalWidth())
+}
+
+func RenderUnorderedList(ul output.UnorderedList, width int) string {
+	markers := make([]string, len(ul.Items))
+	for i := range ul.Items {
+		markers[i] = ul.BulletChar
 	}
-	if t.Style.IsItalic {
-		output = output.Italic()
+	return renderList(ul.Items, markers, ul.Indent, width)
+}
+
+func OrderedListView(ol output.OrderedList) string {
+	return RenderOrderedList(ol, terminalWidth())
+}
+
+func RenderOrderedList(ol output.OrderedList, width int) string {
+	markers := make([]string, len(ol.Items))
+	markerWidth := 0
+	for i := range ol.Items {
+		markers[i] = OrderedBullet(ol.BulletStyle, i+1) + "."
+		markerWidth = max(markerWidth, rw.StringWidth(markers[i]))
 	}
-	if t.Style.IsUnderline {
-		output = output.Underline()
+	// right align the markers so the item text lines up
+	for i := range markers {
+		markers[i] = alignCell(markers[i], markerWidth, output.AlignRight)
 	}
-
-	return output.String()
+	return renderList(ol.Items, markers, ol.Indent, width)
 }
 
-func HorizontalRuleView(hr output.HorizontalRule) string {
-	size, _ := ts.GetSize()
-	output := termenv.String(strings.Repeat(hr.RuleChar, size.Col())).
-		Foreground(TerminalColor(hr.Style.Color))
+// renderList writes each item after its marker, wrapping long items so they line up with the start of the item text.
+// Nested lists are rendered below their parent item.
+func renderList(items []output.Text, markers []string, indent int, width int) string {
+	sb := strings.Builder{}
+	for i, item := range items {
+		prefix := strings.Repeat("  ", indent) + markers[i] + " "
+		textCol := rw.StringWidth(prefix)
+		itemWidth := max(width, textCol+minColumnWidth)
 
-	if hr.Style.IsBold {
-		output = output.Bold()
+		sb.WriteString(prefix)
+		sb.WriteString(styled(WrappedString(item.Text, textCol, textCol, itemWidth, itemWidth), item.Style))
+
+		for _, child := range item.Children {
+			sb.WriteString(ViewWidth(child, nil, width))
+		}
 	}
-	if hr.Style.IsItalic {
-		output = output.Italic()
+	return sb.String()
+}
+
+// OrderedBullet formats the number n (starting from 1) in the given style. Unknown styles are shown as decimals.
+func OrderedBullet(style output.BulletStyle, n int) string {
+	switch style {
+	case output.UpperAlpha:
+		return alphaBullet(n)
+	case output.LowerAlpha:
+		return strings.ToLower(alphaBullet(n))
+	case output.UpperRoman:
+		return romanBullet(n)
+	case output.LowerRoman:
+		return strings.ToLower(romanBullet(n))
+	default:
+		return strconv.Itoa(n)
 	}
-	if hr.Style.IsUnderline {
-		output = output.Underline()
+}
+
+// alphaBullet counts A, B, ... Z, AA, AB, ...
+func alphaBullet(n int) string {
+	bullet := ""
+	for n > 0 {
+		n--
+		bullet = string(rune('A'+n%26)) + bullet
+		n /= 26
 	}
+	return bullet
+}
 
-	return output.String()
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
 }
 
-func UnorderedListView(ul output.UnorderedList) string {
-	size, _ := ts.GetSize()
+func romanBullet(n int) string {
 	sb := strings.Builder{}
-	for _, item := range ul.Items {
-		sb.WriteString(strings.Repeat("  ", ul.Indent))
-		sb.WriteString(ul.BulletChar + "  ")
-		text := termenv.String(WrappedString(item.Text, ul.Indent*3+1, ul.Indent*5, size.Col(), size.Col())).Foreground(TerminalColor(item.Style.Color))
-		if item.Style.IsBold {
-			text = text.Bold()
-		}
-		if item.Style.IsItalic {
-			text = text.Italic()
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			sb.WriteString(r.numeral)
+			n -= r.value
 		}
-		if item.Style.IsUnderline {
-			text = text.Underline()
-		}
-
-		sb.WriteString(text.String())
 	}
 	return sb.String()
 }
@@ -1,9 +1,12 @@
 package outputview
 
 import (
+	"strings"
 	"testing"
 
+	rw "github.com/mattn/go-runewidth"
 	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models/output"
 )
 
 func TestWrappedString(t *testing.T) {
@@ -64,3 +67,110 @@ func TestWrappedString_StartIndent2(t *testing.T) {
 `
 	assert.Equal(t, expected, output)
 }
+
+func TestOrderedBullet(t *testing.T) {
+	testCase := func(style output.BulletStyle, n int, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, OrderedBullet(style, n))
+		}
+	}
+
+	t.Run("decimal", testCase(output.Decimal, 12, "12"))
+	t.Run("upper alpha", testCase(output.UpperAlpha, 3, "C"))
+	t.Run("upper alpha past Z", testCase(output.UpperAlpha, 28, "AB"))
+	t.Run("lower alpha", testCase(output.LowerAlpha, 26, "z"))
+	t.Run("upper roman", testCase(output.UpperRoman, 14, "XIV"))
+	t.Run("lower roman", testCase(output.LowerRoman, 1994, "mcmxciv"))
+	t.Run("unknown style", testCase("unknown", 4, "4"))
+}
+
+func TestRenderOrderedList(t *testing.T) {
+	items := []string{"one", "two", "three", "four"}
+
+	testCase := func(style output.BulletStyle, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			ol := output.EmptyOutputGroup().OrderedList(items, style).ToSlice()[0].(output.OrderedList)
+			assert.Equal(t, expected, stripANSI(RenderOrderedList(ol, 40)))
+		}
+	}
+
+	t.Run("decimal", testCase(output.Decimal, "1. one\n2. two\n3. three\n4. four\n"))
+	t.Run("upper alpha", testCase(output.UpperAlpha, "A. one\nB. two\nC. three\nD. four\n"))
+	t.Run("lower alpha", testCase(output.LowerAlpha, "a. one\nb. two\nc. three\nd. four\n"))
+	t.Run("upper roman", testCase(output.UpperRoman, "  I. one\n II. two\nIII. three\n IV. four\n"))
+	t.Run("lower roman", testCase(output.LowerRoman, "  i. one\n ii. two\niii. three\n iv. four\n"))
+}
+
+func TestRenderOrderedList_Wrapped(t *testing.T) {
+	ol := output.EmptyOutputGroup().
+		Indent().
+		OrderedList([]string{"this item is long enough to wrap"}, output.Decimal).
+		ToSlice()[0].(output.OrderedList)
+
+	expected :=
+		`  1. this item is
+     long enough to
+     wrap
+`
+	assert.Equal(t, expected, stripANSI(RenderOrderedList(ol, 21)))
+}
+
+func TestRenderNestedList(t *testing.T) {
+	group := output.EmptyOutputGroup()
+	grandchildren := group.SubGroup().SubGroup().UnorderedList([]string{"grandchild"}, "-").ToSlice()
+	sub := group.SubGroup()
+	children := sub.OrderedListItems([]output.Text{sub.Item("child 1", grandchildren...), sub.Item("child 2")}, output.LowerAlpha).ToSlice()
+	items := group.
+		UnorderedListItems([]output.Text{group.Item("parent", children...), group.Item("sibling")}, "•").
+		ToSlice()
+
+	expected :=
+		`• parent
+  a. child 1
+    - grandchild
+  b. child 2
+• sibling
+`
+	assert.Equal(t, expected, stripANSI(ViewWidth(items[0], nil, 40)))
+}
+
+func TestRenderEveryKindAtFixedWidths(t *testing.T) {
+	group := output.EmptyOutputGroup()
+	nested := group.SubGroup().UnorderedList([]string{"a nested item which needs to wrap at narrow widths"}, "-").ToSlice()
+
+	helpers := group.
+		Header("A header that is longer than the narrowest width").
+		HorizontalRule("═").
+		Paragraph("A paragraph of text that will need to wrap onto several lines when the terminal is narrow.").
+		Indent().
+		UnorderedList([]string{"first bullet point", "a second bullet point that is much longer than the first"}, output.NormalBulletChar).
+		Unindent().
+		OrderedList([]string{"first", "second", "third"}, output.UpperRoman).
+		UnorderedListItems([]output.Text{group.Item("parent item", nested...)}, "*").
+		Table([]output.TableColumn{
+			output.MakeColumn("Name", output.TextColumn),
+			output.MakeColumn("Description", output.TextColumn),
+			output.MakeColumn("Balance", output.MoneyColumn),
+		}, [][]output.TableCell{
+			{output.Cell("checking"), output.Cell("the main account used for bills"), output.Cell("1,234.56")},
+		}).
+		BarChart("Balances", []output.Bar{{Label: "checking", Value: 1234.56, Display: "1,234.56"}, {Label: "credit", Value: -50, Display: "(50.00)"}}).
+		Sparkline("Trend", []float64{1, 5, 2, 8, 3, 9, 4, 7, 1, 5, 2, 8, 3, 9, 4, 7, 1, 5, 2, 8, 3, 9, 4, 7, 1, 5, 2, 8, 3, 9, 4, 7}).
+		ToSlice()
+
+	for _, width := range []int{20, 40, 80} {
+		for _, helper := range helpers {
+			rendered := stripANSI(ViewWidth(helper, nil, width))
+
+			assert.NotContains(t, rendered, "not supported", "%T at width %d", helper, width)
+			for _, line := range strings.Split(rendered, "\n") {
+				assert.LessOrEqual(t, rw.StringWidth(line), width, "%T at width %d: %q", helper, width, line)
+			}
+		}
+	}
+}
+
+func TestViewStringOutput(t *testing.T) {
+	assert.Equal(t, "goodbye\n\n", stripANSI(ViewWidth("goodbye", nil, 80)))
+	assert.Equal(t, "", ViewWidth("", nil, 80))
+}
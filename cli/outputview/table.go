@@ -0,0 +1,161 @@
+package outputview
+
+import (
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	"github.com/olekukonko/ts"
+	"samvasta.com/bujit/models/output"
+)
+
+const columnGap = "  "
+const minColumnWidth = 3
+const truncationTail = "…"
+
+// terminalWidth returns the width of the terminal, or a sensible default when there is no terminal.
+func terminalWidth() int {
+	size, err := ts.GetSize()
+	if err != nil || size.Col() < 1 {
+		return 80
+	}
+	return size.Col()
+}
+
+func styled(text string, style output.TextStyle) string {
+	s := termenv.String(text).Foreground(TerminalColor(style.Color))
+
+	if style.IsBold {
+		s = s.Bold()
+	}
+	if style.IsItalic {
+		s = s.Italic()
+	}
+	if style.IsUnderline {
+		s = s.Underline()
+	}
+
+	return s.String()
+}
+
+func TableView(t output.Table) string {
+	return RenderTable(t, terminalWidth())
+}
+
+// RenderTable renders the table to fit within width columns. When the table is too wide, the widest columns are
+// truncated first.
+func RenderTable(t output.Table, width int) string {
+	indent := strings.Repeat("  ", t.Indent)
+	widths := columnWidths(t, width-len(indent))
+
+	totalWidth := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		totalWidth += w
+	}
+
+	sb := strings.Builder{}
+
+	headerCells := make([]output.TableCell, len(t.Columns))
+	for i, col := range t.Columns {
+		headerCells[i] = output.Cell(col.Header)
+	}
+	writeTableRow(&sb, indent, t.Columns, widths, headerCells, t.HeaderStyle)
+	sb.WriteString(indent + styled(strings.Repeat("─", totalWidth), t.Style) + "\n")
+
+	for _, row := range t.Rows {
+		writeTableRow(&sb, indent, t.Columns, widths, row, t.Style)
+	}
+
+	if len(t.Footers) > 0 {
+		sb.WriteString(indent + styled(strings.Repeat("─", totalWidth), t.Style) + "\n")
+
+		footerStyle := t.Style
+		footerStyle.IsBold = true
+		for _, row := range t.Footers {
+			writeTableRow(&sb, indent, t.Columns, widths, row, footerStyle)
+		}
+	}
+
+	return sb.String()
+}
+
+// columnWidths finds the natural width of every column, then shrinks the widest until the table fits.
+func columnWidths(t output.Table, available int) []int {
+	widths := make([]int, len(t.Columns))
+
+	measure := func(row []output.TableCell) {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if w := rw.StringWidth(row[i].Text); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	for i, col := range t.Columns {
+		widths[i] = rw.StringWidth(col.Header)
+	}
+	for _, row := range t.Rows {
+		measure(row)
+	}
+	for _, row := range t.Footers {
+		measure(row)
+	}
+
+	total := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+func writeTableRow(sb *strings.Builder, indent string, columns []output.TableColumn, widths []int, row []output.TableCell, style output.TextStyle) {
+	sb.WriteString(indent)
+	for i, col := range columns {
+		if i > 0 {
+			sb.WriteString(columnGap)
+		}
+
+		var cell output.TableCell
+		if i < len(row) {
+			cell = row[i]
+		}
+
+		cellStyle := style
+		if cell.Color != "" {
+			cellStyle.Color = cell.Color
+		}
+
+		sb.WriteString(styled(alignCell(cell.Text, widths[i], col.Align), cellStyle))
+	}
+	sb.WriteString("\n")
+}
+
+func alignCell(text string, width int, align output.Alignment) string {
+	text = rw.Truncate(text, width, truncationTail)
+	padding := width - rw.StringWidth(text)
+
+	switch align {
+	case output.AlignRight:
+		return strings.Repeat(" ", padding) + text
+	case output.AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", padding-left)
+	default:
+		return text + strings.Repeat(" ", padding)
+	}
+}
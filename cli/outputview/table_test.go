@@ -0,0 +1,72 @@
+package outputview
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models/output"
+)
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+func testTable() output.Table {
+	return output.EmptyOutputGroup().
+		Table([]output.TableColumn{
+			output.MakeColumn("Name", output.TextColumn),
+			output.MakeColumn("Description", output.TextColumn),
+			output.MakeColumn("Balance", output.MoneyColumn),
+		}, [][]output.TableCell{
+			{output.Cell("cash"), output.Cell("wallet"), output.Cell("12.50")},
+			{output.Cell("checking"), output.Cell("main bank account"), output.ColoredCell("(3.00)", output.Error)},
+		}, []output.TableCell{output.Cell("Total"), output.Cell(""), output.Cell("9.50")}).
+		ToSlice()[0].(output.Table)
+}
+
+func TestRenderTable(t *testing.T) {
+	rendered := stripANSI(RenderTable(testTable(), 80))
+
+	expected :=
+		`Name      Description        Balance
+────────────────────────────────────
+cash      wallet               12.50
+checking  main bank account   (3.00)
+────────────────────────────────────
+Total                           9.50
+`
+	assert.Equal(t, expected, rendered)
+}
+
+func TestRenderTable_Truncated(t *testing.T) {
+	rendered := stripANSI(RenderTable(testTable(), 30))
+
+	expected :=
+		`Name      Description  Balance
+──────────────────────────────
+cash      wallet         12.50
+checking  main bank …   (3.00)
+──────────────────────────────
+Total                     9.50
+`
+	assert.Equal(t, expected, rendered)
+}
+
+func TestRenderTable_Indented(t *testing.T) {
+	table := testTable()
+	table.Indent = 1
+	table.Footers = nil
+
+	rendered := stripANSI(RenderTable(table, 80))
+
+	expected :=
+		`  Name      Description        Balance
+  ────────────────────────────────────
+  cash      wallet               12.50
+  checking  main bank account   (3.00)
+`
+	assert.Equal(t, expected, rendered)
+}
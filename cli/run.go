@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"samvasta.com/bujit/cli/jsonview"
+	"samvasta.com/bujit/config"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+const (
+	exitSuccess = 0
+	exitFailure = 1 // the command ran but was not successful
+	exitUsage   = 2 // the command could not be parsed
+)
+
+// OpenLedger opens the ledger database at path, creating it if it does not exist yet, along with the history of the
+// commands typed at its prompt.
+func OpenLedger(path string) *session.Session {
+	if !strings.HasPrefix(path, "file:") {
+		os.MkdirAll(filepath.Dir(path), 0700)
+	}
+
+	s := session.SQLiteSession(path, models.MigrateSchema)
+	// a history that cannot be read starts empty
+	s.History, _ = session.LoadHistory(config.HistoryPath(path), session.DefaultHistoryLimit)
+	return &s
+}
+
+// Run executes the command given in args and writes the rendered output to stdout. Returns the exit code for the
+// process.
+func Run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	outputFlag := flags.String("output", "text", "output format: text, json or jsonl")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	mode, err := ParseOutputMode(*outputFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	command := JoinArgs(flags.Args())
+	if command == "" {
+		fmt.Fprintln(stderr, "usage: bujit run [--ledger=<path>] [--output=text|json|jsonl] <command>")
+		return exitUsage
+	}
+
+	return RunCommand(command, OpenLedger(*ledger), mode, stdout, stderr)
+}
+
+// RunCommand parses and executes a single command. Returns the exit code for the process. In the JSON output modes a
+// document is written to stdout even when the command cannot be parsed.
+func RunCommand(command string, s *session.Session, mode OutputMode, stdout, stderr io.Writer) int {
+	action, suggestion := parse.ParseExpression(command, s)
+
+	if action == nil {
+		if mode.IsJSON() {
+			fmt.Fprint(stdout, mode.writeDocument(jsonview.ParseErrorDocument(command, suggestion.Problem(command))))
+		}
+		fmt.Fprintln(stderr, suggestion.Problem(command))
+		if len(suggestion.NextArgs) > 0 {
+			fmt.Fprintf(stderr, "expected one of: %s\n", strings.Join(suggestion.NextArgs, ", "))
+		}
+		return exitUsage
+	}
+
+	result, consequences := action.Execute()
+
+	fmt.Fprint(stdout, formatResult(mode, command, result, consequences))
+
+	if !result.IsSuccessful {
+		return exitFailure
+	}
+	return exitSuccess
+}
+
+// JoinArgs rebuilds a command line from shell arguments. A single argument is taken as the whole command line, as in
+// bujit run "list account". Several arguments are each kept as one token, quoted by parse.JoinTokens when needed.
+func JoinArgs(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	return parse.JoinTokens(args)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+func TestRunCommand(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(command string, expectedCode int, expectedOutput string) func(t *testing.T) {
+		return func(t *testing.T) {
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+			code := RunCommand(command, &s, OutputText, &stdout, &stderr)
+
+			assert.Equal(t, expectedCode, code, stderr.String())
+			assert.Contains(t, stdout.String(), expectedOutput)
+		}
+	}
+
+	t.Run("create", testCase("new account cash -b=12.50", exitSuccess, ""))
+	t.Run("list", testCase("list account", exitSuccess, "cash"))
+	t.Run("unsuccessful action", testCase("report forecast --account=missing", exitFailure, "No account with name 'missing'"))
+	t.Run("parse error", testCase("lsit account", exitUsage, ""))
+	t.Run("incomplete command", testCase("new account", exitUsage, ""))
+}
+
+func TestRunWithoutCommand(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Run([]string{"--ledger=file::memory:"}, &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "usage")
+}
+
+func TestRunWithLedger(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Run([]string{"--ledger=file::memory:", "help"}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code)
+	assert.Contains(t, stdout.String(), "Bujit General Help")
+}
+
+func TestRunWithQuotedCommand(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Run([]string{"--ledger=file::memory:", "list account"}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+}
+
+func TestJoinArgs(t *testing.T) {
+	assert.Equal(t, "list account -c=food", JoinArgs([]string{"list", "account", "-c=food"}))
+	assert.Equal(t, `new account "My Account"`, JoinArgs([]string{"new", "account", "My Account"}))
+	assert.Equal(t, `new account 'Say "hi"'`, JoinArgs([]string{"new", "account", `Say "hi"`}))
+	assert.Equal(t, `new account "O'Brien"`, JoinArgs([]string{"new", "account", "O'Brien"}))
+	assert.Equal(t, `new account "Say \"it's\" \\o/"`, JoinArgs([]string{"new", "account", `Say "it's" \o/`}))
+	assert.Equal(t, "", JoinArgs([]string{}))
+	assert.Equal(t, "list account -c=food", JoinArgs([]string{"list account -c=food"}))
+	assert.Equal(t, `new account "My Account"`, JoinArgs([]string{`new account "My Account"`}))
+
+	for _, arg := range []string{"My Account", `Say "hi"`, "O'Brien", `Say "it's" \o/`, `-d=weekly groceries`} {
+		assert.Equal(t, []string{"new", arg}, parse.Tokenize(JoinArgs([]string{"new", arg})), arg)
+	}
+}
+
+func TestRunCommandJSON(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(command string, mode OutputMode, expectedCode int, expectedSuccess bool) func(t *testing.T) {
+		return func(t *testing.T) {
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+			code := RunCommand(command, &s, mode, &stdout, &stderr)
+
+			assert.Equal(t, expectedCode, code, stderr.String())
+			if mode == OutputJSONLines {
+				assert.Equal(t, 1, strings.Count(stdout.String(), "\n"))
+			} else {
+				assert.Greater(t, strings.Count(stdout.String(), "\n"), 1)
+			}
+
+			var doc struct {
+				Command string `json:"command"`
+				Success bool   `json:"success"`
+			}
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &doc))
+			assert.Equal(t, command, doc.Command)
+			assert.Equal(t, expectedSuccess, doc.Success)
+		}
+	}
+
+	t.Run("jsonl create", testCase("new account cash", OutputJSONLines, exitSuccess, true))
+	t.Run("jsonl parse error", testCase("lsit account", OutputJSONLines, exitUsage, false))
+	t.Run("json unsuccessful", testCase("report forecast --account=missing", OutputJSON, exitFailure, false))
+}
+
+func TestRunWithOutputMode(t *testing.T) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	code := Run([]string{"--ledger=file::memory:", "--output=jsonl", "new", "account", "cash"}, &stdout, &stderr)
+
+	assert.Equal(t, exitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"kind":"account"`)
+
+	code = Run([]string{"--ledger=file::memory:", "--output=xml", "help"}, &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "unknown output mode")
+}
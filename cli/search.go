@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"samvasta.com/bujit/parse"
+)
+
+// historySearch is a reverse incremental search of the history, started with Ctrl-R. Each key typed narrows the
+// search, Ctrl-R again finds an older match, Esc puts back what was typed before and any other key keeps the match.
+type historySearch struct {
+	text     string // what is searched for
+	index    int    // the entry that matches, or the number of entries when nothing has matched yet
+	failed   bool   // nothing older matches the text
+	original string // the input before the search started
+	prompt   string // the prompt before the search started
+}
+
+// searchPrompt is shown in place of the prompt while searching, like in bash
+func (search historySearch) searchPrompt() string {
+	if search.failed {
+		return fmt.Sprintf("(failed reverse-i-search)`%s': ", search.text)
+	}
+	return fmt.Sprintf("(reverse-i-search)`%s': ", search.text)
+}
+
+// updateSearch handles a key while searching the history, or the Ctrl-R that starts the search. Returns false when the
+// search ended with a key that the prompt should still handle, such as enter.
+func (m *model) updateSearch(key tea.KeyMsg) bool {
+	entries := historyEntries(m.session)
+
+	if m.search == nil {
+		m.search = &historySearch{index: len(entries), original: m.textInput.Value(), prompt: m.textInput.Prompt}
+		m.suggestion = parse.EmptySuggestions
+		m.textInput.SetSuggestValue("")
+		m.showSearch()
+		return true
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlR:
+		m.findMatch(m.search.index)
+	case tea.KeyRunes:
+		m.search.text += string(key.Runes)
+		// the current match is kept while it still contains the text
+		m.findMatch(m.search.index + 1)
+	case tea.KeyBackspace:
+		if m.search.text == "" {
+			break
+		}
+		runes := []rune(m.search.text)
+		m.search.text = string(runes[:len(runes)-1])
+		m.findMatch(len(entries))
+	case tea.KeyEsc, tea.KeyCtrlG, tea.KeyCtrlC:
+		m.textInput.SetValue(m.search.original)
+		m.textInput.CursorEnd()
+		m.endSearch()
+	default:
+		m.textInput.CursorEnd()
+		m.endSearch()
+		return false
+	}
+	return true
+}
+
+// findMatch shows the newest entry before index that contains the search text
+func (m *model) findMatch(before int) {
+	if index, offset, ok := m.session.History.Search(m.search.text, before); ok {
+		m.search.index, m.search.failed = index, false
+		entry := historyEntries(m.session)[index]
+		start := utf8.RuneCountInString(entry[:offset])
+		m.textInput.SetValue(entry)
+		m.textInput.SetCursor(start)
+		m.textInput.SetHighlight(start, start+utf8.RuneCountInString(m.search.text))
+	} else {
+		m.search.failed = true
+	}
+	m.showSearch()
+}
+
+func (m *model) showSearch() {
+	m.textInput.Prompt = m.search.searchPrompt()
+}
+
+// endSearch keeps what the search found in the input and puts the prompt back
+func (m *model) endSearch() {
+	m.textInput.Prompt = m.search.prompt
+	m.textInput.SetHighlight(0, 0)
+	m.historyIdx = len(historyEntries(m.session))
+	if m.search.index < m.historyIdx {
+		m.historyIdx = m.search.index
+	}
+	m.search = nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestHistorySearch(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.History = &session.History{Entries: []string{"new account cash", "list account", "new account visa"}}
+
+	press := func(m model, keys ...tea.KeyMsg) model {
+		for _, key := range keys {
+			updated, _ := m.Update(key)
+			m = updated.(model)
+		}
+		return m
+	}
+	ctrlR := tea.KeyMsg{Type: tea.KeyCtrlR}
+	typed := func(text string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+	}
+
+	m := press(initialModel(&s, &history{}), ctrlR, typed("acc"))
+	assert.Equal(t, "new account visa", m.textInput.Value())
+	assert.Equal(t, "(reverse-i-search)`acc': ", m.textInput.Prompt)
+
+	m = press(m, ctrlR)
+	assert.Equal(t, "list account", m.textInput.Value())
+
+	m = press(m, typed("ount c"))
+	assert.Equal(t, "new account cash", m.textInput.Value())
+
+	m = press(m, typed("x"))
+	assert.Equal(t, "(failed reverse-i-search)`account cx': ", m.textInput.Prompt)
+	assert.Equal(t, "new account cash", m.textInput.Value())
+
+	// any other key keeps the match and goes back to the prompt
+	m = press(m, tea.KeyMsg{Type: tea.KeyRight})
+	assert.Nil(t, m.search)
+	assert.Equal(t, "> ", m.textInput.Prompt)
+	assert.Equal(t, "new account cash", m.textInput.Value())
+
+	// esc puts back what was typed
+	m = press(m, ctrlR, typed("list"), tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, m.search)
+	assert.False(t, m.history.exit)
+	assert.Equal(t, "new account cash", m.textInput.Value())
+}
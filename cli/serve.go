@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+
+	"samvasta.com/bujit/config"
+	"samvasta.com/bujit/server"
+)
+
+const defaultServeAddr = "127.0.0.1:8080"
+
+// Serve starts the HTTP API for the ledger and blocks until the server stops. Returns the exit code for the process.
+func Serve(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	addr := flags.String("addr", defaultServeAddr, "address to listen on")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	fmt.Fprintf(stdout, "listening on http://%s\n", *addr)
+	if err := http.ListenAndServe(*addr, server.NewServer(OpenLedger(*ledger))); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitSuccess
+}
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"samvasta.com/bujit/cli/tui"
+	"samvasta.com/bujit/config"
+)
+
+// StartTUI runs the full screen dashboard until it is quit
+func StartTUI(args []string) {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	ledger := flags.String("ledger", config.DefaultLedgerPath(), "path to the ledger database")
+	flags.Parse(args)
+
+	if err := tea.NewProgram(tui.New(OpenLedger(*ledger))).Start(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+// color is a helper for returning colors.
+var color func(s string) termenv.Color = termenv.ColorProfile().Color
+
+// cursor is the selected row of a pane and the first row that is shown
+type cursor struct {
+	selected int
+	offset   int
+}
+
+// move selects the row delta rows away, staying within count rows
+func (c *cursor) move(delta, count int) {
+	c.selected = clamp(c.selected+delta, 0, count-1)
+}
+
+// show scrolls so the selected row is one of the height rows that are shown
+func (c *cursor) show(height int) {
+	if c.selected < c.offset {
+		c.offset = c.selected
+	}
+	if height > 0 && c.selected >= c.offset+height {
+		c.offset = c.selected - height + 1
+	}
+	c.offset = max(c.offset, 0)
+}
+
+// fit pads or cuts a line to exactly width columns. Escape sequences, such as colors, take no columns and are kept.
+func fit(line string, width int) string {
+	sb := strings.Builder{}
+	used := 0
+	escaped := false
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\x1b' {
+			// copy the whole sequence, up to the letter that ends it
+			j := i + 1
+			for j < len(runes) && !isEscapeEnd(runes[j], j == i+1) {
+				j++
+			}
+			j = min(j, len(runes)-1)
+			sb.WriteString(string(runes[i : j+1]))
+			i = j
+			escaped = true
+			continue
+		}
+
+		w := rw.RuneWidth(r)
+		if used+w > width {
+			if escaped {
+				sb.WriteString(termenv.CSI + termenv.ResetSeq + "m")
+			}
+			return sb.String() + strings.Repeat(" ", width-used)
+		}
+		sb.WriteRune(r)
+		used += w
+	}
+	return sb.String() + strings.Repeat(" ", width-used)
+}
+
+// isEscapeEnd is true for the rune that ends an escape sequence. The [ that starts a sequence does not end it.
+func isEscapeEnd(r rune, first bool) bool {
+	if first {
+		return r != '['
+	}
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// pane draws a title line above the lines, fitted to width and height. The title of the focused pane stands out.
+func pane(title string, lines []string, width, height int, focused bool) []string {
+	heading := termenv.String(fit(" "+title, width))
+	if focused {
+		heading = heading.Reverse().Bold()
+	} else {
+		heading = heading.Foreground(color("8")).Underline()
+	}
+
+	drawn := []string{heading.String()}
+	for i := 0; i < height-1; i++ {
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+		}
+		drawn = append(drawn, fit(line, width))
+	}
+	return drawn
+}
+
+// beside joins the lines of two columns with a separator between them
+func beside(left, right []string, separator string) []string {
+	joined := []string{}
+	for i := 0; i < max(len(left), len(right)); i++ {
+		l, r := "", ""
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		joined = append(joined, l+separator+r)
+	}
+	return joined
+}
+
+// window is the rows of a list from offset that fit in height
+func window(rows []string, offset, height int) []string {
+	offset = clamp(offset, 0, len(rows))
+	return rows[offset:min(len(rows), offset+height)]
+}
+
+func clamp(v, low, high int) int {
+	return max(low, min(high, v))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
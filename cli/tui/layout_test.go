@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+	assert.Equal(t, "cash  ", fit("cash", 6))
+	assert.Equal(t, "sav", fit("savings", 3))
+	assert.Equal(t, "日本 ", fit("日本語", 5))
+	assert.Equal(t, "\x1b[31mre\x1b[0m", fit("\x1b[31mred\x1b[0m", 2))
+	assert.Equal(t, "\x1b[31mred\x1b[0m  ", fit("\x1b[31mred\x1b[0m", 5))
+}
+
+func TestCursor(t *testing.T) {
+	c := cursor{}
+	c.move(7, 10)
+	c.show(3)
+	assert.Equal(t, cursor{selected: 7, offset: 5}, c)
+
+	c.move(-20, 10)
+	c.show(3)
+	assert.Equal(t, cursor{selected: 0, offset: 0}, c)
+
+	c.move(1, 0)
+	assert.Equal(t, 0, c.selected)
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"time"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// registerPane shows the transactions into and out of one account, newest first
+type registerPane struct {
+	account      *models.Account // nil when no account is selected
+	transactions []models.Transaction
+	cursor       cursor
+}
+
+// load reads the transactions of the account. The selection is kept when the account is the same.
+func (register *registerPane) load(s *session.Session, account *models.Account) {
+	if account == nil || register.account == nil || account.ID != register.account.ID {
+		register.cursor = cursor{}
+	}
+	register.account = account
+	register.transactions = []models.Transaction{}
+	if account == nil {
+		return
+	}
+
+	_, consequences := actions_transactions.ListTransactionAction{AccountName: account.Name, Session: s}.Execute()
+	for _, c := range consequences {
+		if transaction, ok := c.Object.(models.Transaction); ok {
+			register.transactions = append(register.transactions, transaction)
+		}
+	}
+	register.cursor.move(0, len(register.transactions))
+}
+
+func (register registerPane) title() string {
+	if register.account == nil {
+		return "Register"
+	}
+	return "Register: " + register.account.Name
+}
+
+// entry is what a transaction means for the account: the other account and the amount, negative when money left
+func (register registerPane) entry(transaction models.Transaction) (other string, amount models.Money) {
+	if transaction.SourceID != nil && *transaction.SourceID == register.account.ID {
+		if transaction.Destination != nil {
+			other = "→ " + transaction.Destination.Name
+		}
+		return other, -transaction.Change
+	}
+	if transaction.Source != nil {
+		other = "← " + transaction.Source.Name
+	}
+	return other, transaction.Change
+}
+
+func (register *registerPane) lines(s *session.Session, width, height int, focused bool) []string {
+	register.cursor.show(height)
+
+	if register.account == nil {
+		return []string{termenv.String("Select an account to see its transactions").Foreground(color("8")).String()}
+	}
+	if len(register.transactions) == 0 {
+		return []string{termenv.String("No transactions yet").Foreground(color("8")).String()}
+	}
+
+	lines := []string{}
+	for i, transaction := range register.transactions {
+		other, amount := register.entry(transaction)
+		date := time.Unix(transaction.EffectiveAt, 0).UTC().Format("2006-01-02")
+		money := amount.String(s)
+
+		// date, other account, memo and the amount on the right
+		rest := max(width-rw.StringWidth(date)-rw.StringWidth(money)-3, 0)
+		otherWidth := min(rw.StringWidth(other), rest/2)
+		text := date + " " + rw.FillRight(rw.Truncate(other, otherWidth, "…"), otherWidth) + " " +
+			rw.FillRight(rw.Truncate(transaction.Memo, rest-otherWidth, "…"), rest-otherWidth) + " "
+
+		line := text + money
+		switch {
+		case i == register.cursor.selected && focused:
+			line = termenv.String(fit(line, width)).Reverse().String()
+		case amount.IsNegative():
+			line = text + termenv.String(money).Foreground(color("9")).String()
+		}
+		lines = append(lines, line)
+	}
+	return window(lines, register.cursor.offset, height)
+}
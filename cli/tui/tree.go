@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_categories "samvasta.com/bujit/actions/categories"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// treeRow is a category or an account in the tree
+type treeRow struct {
+	depth    int
+	category *models.Category // nil for accounts
+	account  *models.Account  // nil for categories
+}
+
+// key identifies the category or account of a row, so it stays selected when the tree is loaded again
+func (row treeRow) key() string {
+	if row.account != nil {
+		return fmt.Sprintf("account:%d", row.account.ID)
+	}
+	return fmt.Sprintf("category:%d", row.category.ID)
+}
+
+// treePane shows every category with its accounts under it. Accounts without a category come last.
+type treePane struct {
+	categories []models.Category
+	accounts   []models.Account
+	collapsed  map[uint]bool // categories whose accounts and sub categories are hidden
+	rows       []treeRow
+	cursor     cursor
+}
+
+func newTreePane() treePane {
+	return treePane{collapsed: map[uint]bool{}}
+}
+
+// load reads the categories and accounts of the ledger again, keeping the same row selected
+func (tree *treePane) load(s *session.Session) {
+	selected, hasSelected := tree.selected()
+
+	_, consequences := actions_categories.ListCategoryAction{Session: s}.Execute()
+	tree.categories = []models.Category{}
+	for _, c := range consequences {
+		if category, ok := c.Object.(models.Category); ok {
+			tree.categories = append(tree.categories, category)
+		}
+	}
+
+	_, consequences = actions_accounts.ListAccountAction{Session: s}.Execute()
+	tree.accounts = []models.Account{}
+	for _, c := range consequences {
+		if account, ok := c.Object.(models.Account); ok {
+			tree.accounts = append(tree.accounts, account)
+		}
+	}
+	sort.SliceStable(tree.accounts, func(i, j int) bool {
+		return strings.ToLower(tree.accounts[i].Name) < strings.ToLower(tree.accounts[j].Name)
+	})
+
+	tree.build()
+	if hasSelected {
+		tree.selectKey(selected.key())
+	}
+}
+
+// build lists the rows that are shown, leaving out what is under a collapsed category
+func (tree *treePane) build() {
+	tree.rows = []treeRow{}
+
+	hidden := []string{} // names of the collapsed categories above the current one
+	for i := range tree.categories {
+		category := &tree.categories[i]
+		if isUnder(category.FullyQualifiedName, hidden) {
+			continue
+		}
+
+		depth := strings.Count(category.FullyQualifiedName, "/")
+		tree.rows = append(tree.rows, treeRow{depth: depth, category: category})
+		if tree.collapsed[category.ID] {
+			hidden = append(hidden, category.FullyQualifiedName)
+			continue
+		}
+		for j := range tree.accounts {
+			if account := &tree.accounts[j]; account.CategoryID != nil && *account.CategoryID == category.ID {
+				tree.rows = append(tree.rows, treeRow{depth: depth + 1, account: account})
+			}
+		}
+	}
+
+	for j := range tree.accounts {
+		if account := &tree.accounts[j]; account.CategoryID == nil {
+			tree.rows = append(tree.rows, treeRow{depth: 0, account: account})
+		}
+	}
+
+	tree.cursor.move(0, len(tree.rows))
+}
+
+func isUnder(name string, parents []string) bool {
+	for _, parent := range parents {
+		if strings.HasPrefix(name, parent+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (tree *treePane) selectKey(key string) {
+	for i, row := range tree.rows {
+		if row.key() == key {
+			tree.cursor.selected = i
+			return
+		}
+	}
+}
+
+func (tree treePane) selected() (treeRow, bool) {
+	if tree.cursor.selected >= len(tree.rows) {
+		return treeRow{}, false
+	}
+	return tree.rows[tree.cursor.selected], true
+}
+
+// selectedAccount is the account of the selected row, or nil when a category is selected
+func (tree treePane) selectedAccount() *models.Account {
+	if row, ok := tree.selected(); ok {
+		return row.account
+	}
+	return nil
+}
+
+// toggle collapses or expands the selected category
+func (tree *treePane) toggle() {
+	if row, ok := tree.selected(); ok && row.category != nil {
+		tree.setCollapsed(row.category.ID, !tree.collapsed[row.category.ID])
+	}
+}
+
+func (tree *treePane) setCollapsed(id uint, collapsed bool) {
+	tree.collapsed[id] = collapsed
+	key := fmt.Sprintf("category:%d", id)
+	tree.build()
+	tree.selectKey(key)
+}
+
+// collapse hides what is under the selected category, or moves from an account up to its category
+func (tree *treePane) collapse() {
+	row, ok := tree.selected()
+	switch {
+	case !ok:
+	case row.category != nil && !tree.collapsed[row.category.ID]:
+		tree.setCollapsed(row.category.ID, true)
+	default:
+		for i := tree.cursor.selected - 1; i >= 0; i-- {
+			if tree.rows[i].depth < row.depth {
+				tree.cursor.selected = i
+				return
+			}
+		}
+	}
+}
+
+func (tree *treePane) expand() {
+	if row, ok := tree.selected(); ok && row.category != nil {
+		tree.setCollapsed(row.category.ID, false)
+	}
+}
+
+func (tree *treePane) lines(s *session.Session, width, height int, focused bool) []string {
+	tree.cursor.show(height)
+
+	lines := []string{}
+	for i, row := range tree.rows {
+		indent := strings.Repeat("  ", row.depth)
+		var line string
+		if row.category != nil {
+			marker := "▾ "
+			if tree.collapsed[row.category.ID] {
+				marker = "▸ "
+			}
+			line = fit(indent+marker+row.category.Name, width)
+		} else {
+			balance := row.account.CurrentState.Balance.String(s)
+			name := rw.Truncate(indent+"  "+row.account.Name, max(width-rw.StringWidth(balance)-1, 0), "…")
+			line = rw.FillRight(name, width-rw.StringWidth(balance)) + balance
+		}
+
+		styled := termenv.String(fit(line, width))
+		if i == tree.cursor.selected && focused {
+			styled = styled.Reverse()
+		} else if i == tree.cursor.selected {
+			styled = styled.Underline()
+		} else if row.category != nil {
+			styled = styled.Bold()
+		}
+		lines = append(lines, styled.String())
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, termenv.String("No accounts yet. Try: new account cash").Foreground(color("8")).String())
+	}
+	return window(lines, tree.cursor.offset, height)
+}
@@ -0,0 +1,397 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+	"samvasta.com/bujit/actions"
+	actions_pipeline "samvasta.com/bujit/actions/pipeline"
+	"samvasta.com/bujit/cli/customtext"
+	"samvasta.com/bujit/cli/outputview"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+)
+
+// focus is the pane that keys go to
+type focus int
+
+const (
+	focusTree focus = iota
+	focusRegister
+	focusOutput
+	focusCommand
+	focusCount
+)
+
+// farAway is more rows than any pane has, so moving by it goes to the first or last row
+const farAway = 1 << 30
+
+// the terminal is assumed to be this big until it says otherwise
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Model is the full screen dashboard: the categories and accounts on the left, the register of the selected account
+// and the output of the last command on the right, and a command bar at the bottom.
+type Model struct {
+	session *session.Session
+	width   int
+	height  int
+	focus   focus
+	back    focus // the pane to go back to when the command bar is left
+
+	tree     treePane
+	register registerPane
+
+	command    customtext.Model
+	suggestion parse.AutoSuggestion
+	historyIdx int
+
+	result       actions.ActionResult   // of the last command
+	consequences []*actions.Consequence // of the last command
+	ran          string                 // the last command
+	outputScroll int
+	quitting     bool
+}
+
+// New creates the dashboard for a ledger and loads its accounts
+func New(s *session.Session) Model {
+	command := customtext.NewModel()
+	command.Placeholder = "type a command, such as help"
+	command.SuggestionColor = "10"
+	command.CharLimit = 0
+
+	m := Model{
+		session: s,
+		width:   defaultWidth,
+		height:  defaultHeight,
+		tree:    newTreePane(),
+		command: command,
+		back:    focusTree,
+	}
+	m.historyIdx = len(m.historyEntries())
+	m.tree.load(s)
+	m.register.load(s, m.tree.selectedAccount())
+	m.resize()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(tea.EnterAltScreen, customtext.Blink)
+}
+
+func (m *Model) resize() {
+	m.command.Width = m.width - len(m.command.Prompt) - 1
+}
+
+func (m Model) historyEntries() []string {
+	if m.session == nil || m.session.History == nil {
+		return []string{}
+	}
+	return m.session.History.Entries
+}
+
+// setFocus moves the keys to another pane. Only the command bar shows a cursor.
+func (m *Model) setFocus(f focus) {
+	if f == focusCommand && m.focus != focusCommand {
+		m.back = m.focus
+		m.command.Focus()
+	} else if f != focusCommand {
+		m.command.Blur()
+	}
+	m.focus = f
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if m.focus == focusCommand {
+			return m.updateCommand(msg)
+		}
+		return m.updatePane(msg)
+	}
+
+	var cmd tea.Cmd
+	m.command, cmd = m.command.Update(msg)
+	return m, cmd
+}
+
+// updatePane handles keys for the tree, the register and the output
+func (m Model) updatePane(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.bodyHeight() / 2
+
+	switch key.String() {
+	case "q":
+		m.quitting = true
+		return m, tea.Quit
+	case "tab":
+		m.setFocus((m.focus + 1) % focusCount)
+	case "shift+tab":
+		m.setFocus((m.focus + focusCount - 1) % focusCount)
+	case ":", "/":
+		m.setFocus(focusCommand)
+	case "up", "k":
+		m.moveSelection(-1)
+	case "down", "j":
+		m.moveSelection(1)
+	case "pgup":
+		m.moveSelection(-page)
+	case "pgdown":
+		m.moveSelection(page)
+	case "home", "g":
+		m.moveSelection(-farAway)
+	case "end", "G":
+		m.moveSelection(farAway)
+	case "left", "h":
+		if m.focus == focusTree {
+			m.tree.collapse()
+			m.register.load(m.session, m.tree.selectedAccount())
+		}
+	case "right", "l":
+		if m.focus == focusTree {
+			m.tree.expand()
+		}
+	case "enter", " ":
+		if m.focus == focusTree {
+			if m.tree.selectedAccount() != nil {
+				m.setFocus(focusRegister)
+			} else {
+				m.tree.toggle()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) moveSelection(delta int) {
+	switch m.focus {
+	case focusTree:
+		m.tree.cursor.move(delta, len(m.tree.rows))
+		m.register.load(m.session, m.tree.selectedAccount())
+	case focusRegister:
+		m.register.cursor.move(delta, len(m.register.transactions))
+	case focusOutput:
+		m.outputScroll = clamp(m.outputScroll+delta, 0, len(m.outputLines())-1)
+	}
+}
+
+// updateCommand handles keys for the command bar
+func (m Model) updateCommand(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.command.Reset()
+		m.suggestion = parse.EmptySuggestions
+		m.setFocus(m.back)
+		return m, nil
+	case tea.KeyTab:
+		if ghost := m.command.SuggestValue(); ghost != "" {
+			m.command.SetValue(m.command.Value() + ghost)
+			m.command.CursorEnd()
+			m.suggest()
+		} else {
+			m.setFocus(focusTree)
+		}
+		return m, nil
+	case tea.KeyShiftTab:
+		m.setFocus(focusOutput)
+		return m, nil
+	case tea.KeyUp:
+		if m.historyIdx > 0 {
+			m.historyIdx--
+			m.command.SetValue(m.historyEntries()[m.historyIdx])
+			m.command.CursorEnd()
+			m.suggest()
+		}
+		return m, nil
+	case tea.KeyDown:
+		if entries := m.historyEntries(); m.historyIdx < len(entries)-1 {
+			m.historyIdx++
+			m.command.SetValue(entries[m.historyIdx])
+		} else {
+			m.historyIdx = len(entries)
+			m.command.SetValue("")
+		}
+		m.command.CursorEnd()
+		m.suggest()
+		return m, nil
+	case tea.KeyEnter:
+		return m.run()
+	}
+
+	var cmd tea.Cmd
+	m.command, cmd = m.command.Update(key)
+	m.suggest()
+	return m, cmd
+}
+
+// suggest parses what has been typed so far to show what can come next
+func (m *Model) suggest() {
+	text := m.command.Value()
+	m.command.SetSuggestValue("")
+	if text == "" {
+		m.suggestion = parse.EmptySuggestions
+		return
+	}
+
+	_, m.suggestion = parse.ParseExpression(text, m.session)
+	if m.command.Position() == len([]rune(text)) {
+		completion := parse.Complete(text, m.command.Position(), m.session)
+		if completion.Word != "" && len(completion.Completions) > 0 {
+			first, word := []rune(completion.Completions[0]), []rune(completion.Word)
+			if len(first) >= len(word) {
+				m.command.SetSuggestValue(string(first[len(word):]))
+			}
+		}
+	}
+}
+
+// run executes the command in the command bar and refreshes the panes it changed
+func (m Model) run() (tea.Model, tea.Cmd) {
+	text := strings.TrimSpace(m.command.Value())
+	if text == "" {
+		return m, nil
+	}
+
+	action, suggestion := parse.ParseExpression(text, m.session)
+	recorded := text
+	if rerun, ok := action.(parse.HistoryAction); ok {
+		if command, ok := rerun.Command(); ok {
+			recorded = command
+		}
+	}
+	m.session.History.Add(recorded)
+	m.historyIdx = len(m.historyEntries())
+	m.ran = text
+	m.outputScroll = 0
+
+	m.command.Reset()
+	m.suggestion = parse.EmptySuggestions
+
+	if action == nil {
+		problem := output.EmptyOutputGroup().PushStyle(output.TextStyle{Color: output.Error}).Paragraph(suggestion.Problem(text)).ToSlice()
+		m.result = actions.ActionResult{Output: problem, IsSuccessful: false}
+		m.consequences = []*actions.Consequence{}
+		return m, nil
+	}
+
+	if isExit(action) {
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	m.result, m.consequences = action.Execute()
+	m.refresh(m.consequences)
+	return m, nil
+}
+
+func isExit(action actions.Actioner) bool {
+	if _, ok := action.(actions.ExitAction); ok {
+		return true
+	}
+	sequence, ok := action.(actions_pipeline.SequenceAction)
+	return ok && sequence.IsExit()
+}
+
+// refresh loads the panes again when the consequences of a command changed what they show
+func (m *Model) refresh(consequences []*actions.Consequence) {
+	changed := false
+	for _, c := range consequences {
+		if c.ConsequenceType == actions.READ {
+			continue
+		}
+		switch c.Object.(type) {
+		case models.Account, *models.Account, models.AccountState, *models.AccountState,
+			models.Category, *models.Category, models.Transaction, *models.Transaction:
+			changed = true
+		}
+	}
+
+	if changed {
+		m.tree.load(m.session)
+		m.register.load(m.session, m.tree.selectedAccount())
+	}
+}
+
+func (m Model) bodyHeight() int {
+	// the command bar, its suggestions and the key help are below the panes
+	return max(m.height-3, 4)
+}
+
+func (m Model) treeWidth() int {
+	return clamp(m.width/3, 20, 40)
+}
+
+func (m Model) rightWidth() int {
+	return max(m.width-m.treeWidth()-3, 10)
+}
+
+func (m Model) outputLines() []string {
+	if m.ran == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimRight(outputview.ViewWidth(m.result.Output, m.consequences, m.rightWidth()), "\n"), "\n")
+}
+
+func (m Model) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	body := m.bodyHeight()
+	registerHeight := body / 2
+	outputHeight := body - registerHeight
+
+	left := pane("Accounts", m.tree.lines(m.session, m.treeWidth(), body-1, m.focus == focusTree), m.treeWidth(), body, m.focus == focusTree)
+
+	outputTitle := "Output"
+	if m.ran != "" {
+		outputTitle = "Output: " + m.ran
+		if !m.result.IsSuccessful {
+			outputTitle += " (failed)"
+		}
+	}
+	right := append(
+		pane(m.register.title(), m.register.lines(m.session, m.rightWidth(), registerHeight-1, m.focus == focusRegister), m.rightWidth(), registerHeight, m.focus == focusRegister),
+		pane(outputTitle, window(m.outputLines(), m.outputScroll, outputHeight-1), m.rightWidth(), outputHeight, m.focus == focusOutput)...)
+
+	separator := termenv.String(" │ ").Foreground(color("8")).String()
+
+	sb := strings.Builder{}
+	sb.WriteString(strings.Join(beside(left, right, separator), "\n"))
+	sb.WriteString("\n")
+	sb.WriteString(m.command.View())
+	sb.WriteString("\n")
+
+	suggestions := strings.Join(m.suggestion.NextArgs, "  ")
+	if m.suggestion.Preview != "" {
+		suggestions = m.suggestion.Preview + "  " + suggestions
+	}
+	sb.WriteString(fit(termenv.String(suggestions).Foreground(color("10")).String(), m.width))
+	sb.WriteString("\n")
+	sb.WriteString(termenv.String(fit(m.keyHelp(), m.width)).Foreground(color("8")).String())
+	return sb.String()
+}
+
+// keyHelp lists the keys that work in the focused pane
+func (m Model) keyHelp() string {
+	switch m.focus {
+	case focusCommand:
+		return "enter run  tab complete  ↑/↓ history  esc back  ctrl+c quit"
+	case focusTree:
+		return "↑/↓ select  ←/→ collapse/expand  enter open  tab next pane  : command  q quit"
+	default:
+		return "↑/↓ scroll  pgup/pgdown page  tab next pane  : command  q quit"
+	}
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func press(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func typed(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+var enter = tea.KeyMsg{Type: tea.KeyEnter}
+
+// run types a command into the command bar and runs it
+func run(m Model, command string) Model {
+	return press(m, typed(":"), typed(command), enter, tea.KeyMsg{Type: tea.KeyEsc})
+}
+
+func rowNames(tree treePane) []string {
+	names := []string{}
+	for _, row := range tree.rows {
+		name := strings.Repeat("  ", row.depth)
+		if row.category != nil {
+			name += row.category.Name + "/"
+		} else {
+			name += row.account.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestTree(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	m := New(&s)
+	assert.Contains(t, m.View(), "No accounts yet")
+
+	assets := models.Category{Name: "assets", FullyQualifiedName: "assets"}
+	s.Db.Create(&assets)
+	bank := models.Category{Name: "bank", FullyQualifiedName: "assets/bank", SuperCategoryID: &assets.ID}
+	s.Db.Create(&bank)
+	s.Db.Create(&models.Account{Name: "cash", IsActive: true, CategoryID: &assets.ID})
+	s.Db.Create(&models.Account{Name: "savings", IsActive: true, CategoryID: &bank.ID})
+
+	// the tree is loaded again after a command changes an account
+	m = run(m, "new account visa")
+	assert.Equal(t, []string{"assets/", "  cash", "  bank/", "    savings", "visa"}, rowNames(m.tree))
+
+	// collapsing a category hides everything under it, and the selection stays on it
+	m = press(m, tea.KeyMsg{Type: tea.KeyLeft})
+	assert.Equal(t, []string{"assets/", "visa"}, rowNames(m.tree))
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyRight})
+	assert.Len(t, m.tree.rows, 5)
+
+	// left on an account moves up to its category
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyLeft})
+	assert.Equal(t, "bank", m.tree.rows[m.tree.cursor.selected].category.Name)
+}
+
+func TestRegisterFollowsCommands(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	m := New(&s)
+
+	m = run(m, "new account cash -b=100")
+	m = run(m, "new account dining")
+	assert.Equal(t, "cash", m.register.account.Name)
+
+	m = run(m, `new transaction 12.50 -f=cash -t=dining -m="lunch"`)
+	assert.True(t, m.result.IsSuccessful)
+	assert.Len(t, m.register.transactions, 1)
+	_, amount := m.register.entry(m.register.transactions[0])
+	assert.Equal(t, models.MakeMoney(-12.5), amount)
+
+	view := m.View()
+	assert.Contains(t, view, "Register: cash")
+	assert.Contains(t, view, "→ dining")
+	assert.Contains(t, view, "lunch")
+	assert.Contains(t, view, "87.50")
+
+	// selecting the other account shows its side of the transaction
+	m = press(m, typed("j"))
+	assert.Equal(t, "dining", m.register.account.Name)
+	_, amount = m.register.entry(m.register.transactions[0])
+	assert.Equal(t, models.MakeMoney(12.5), amount)
+
+	// a command that does not parse is shown in the output
+	m = run(m, "lsit account")
+	assert.False(t, m.result.IsSuccessful)
+	assert.Contains(t, m.View(), `did you mean "list"?`)
+}
+
+func TestFocus(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	m := New(&s)
+	assert.Equal(t, focusTree, m.focus)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, focusOutput, m.focus)
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, focusCommand, m.focus)
+
+	// keys go to the command bar, and tab accepts the suggestion there
+	m = press(m, typed("ver"), tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, "version", m.command.Value())
+	assert.Equal(t, focusCommand, m.focus)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, focusOutput, m.focus)
+	assert.Equal(t, "", m.command.Value())
+
+	_, cmd := m.Update(typed("q"))
+	assert.NotNil(t, cmd)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLedgerPath is the database used when no ledger is given. It can be changed with the BUJIT_LEDGER environment
+// variable.
+func DefaultLedgerPath() string {
+	if path := os.Getenv("BUJIT_LEDGER"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "bujit.db"
+	}
+	return filepath.Join(home, ".bujit", "ledger.db")
+}
+
+// HistoryPath is the file the commands typed at the prompt are saved to, next to the ledger. It is empty for ledgers
+// that are not files, whose history is not saved.
+func HistoryPath(ledgerPath string) string {
+	if strings.HasPrefix(ledgerPath, "file:") {
+		return ""
+	}
+	return ledgerPath + ".history"
+}
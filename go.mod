@@ -3,16 +3,16 @@ module samvasta.com/bujit
 go 1.16
 
 require (
-	github.com/atotto/clipboard v0.1.2 // indirect
+	github.com/atotto/clipboard v0.1.2
 	github.com/charmbracelet/bubbles v0.7.6
 	github.com/charmbracelet/bubbletea v0.13.1
 	github.com/dustin/go-humanize v1.0.0
 	github.com/jinzhu/now v1.1.2 // indirect
-	github.com/mattn/go-runewidth v0.0.10 // indirect
+	github.com/mattn/go-runewidth v0.0.10
 	github.com/mattn/go-sqlite3 v1.14.6 // indirect
-	github.com/muesli/termenv v0.7.4 // indirect
+	github.com/muesli/termenv v0.7.4
 	github.com/olekukonko/ts v0.0.0-20171002115256-78ecb04241c0
-	github.com/stretchr/testify v1.7.0 // indirect
-	gorm.io/driver/sqlite v1.1.4 // indirect
-	gorm.io/gorm v1.21.4 // indirect
+	github.com/stretchr/testify v1.7.0
+	gorm.io/driver/sqlite v1.1.4
+	gorm.io/gorm v1.21.4
 )
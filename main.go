@@ -1,15 +1,56 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"samvasta.com/bujit/cli"
 )
 
+const usage = `usage: bujit <mode> [args]
+
+modes:
+  cli                 start the interactive prompt
+  tui                 start the full screen dashboard
+  run <command>       run a single command and exit
+  exec <file|->       run a script, one command per line, reading stdin when the file is -
+  serve               serve the ledger as an HTTP/JSON API
+  complete            print suggestions for a partial command as JSON
+  completion <shell>  print the completion script for bash, zsh or fish
+
+options:
+  --ledger=<path>     ledger database to use
+  --output=<format>   text, json or jsonl
+  --continue-on-error keep running a script after a command fails (exec only)
+  --addr=<host:port>  address to listen on (serve only, default 127.0.0.1:8080)
+  --line=<command>    partial command to complete (complete only)
+  --cursor=<n>        cursor position in the partial command (complete only)`
+
 func main() {
 	args := os.Args[1:]
 
-	if args[0] == "cli" {
-		cli.StartInteractive()
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "cli":
+		cli.StartInteractive(args[1:])
+	case "tui":
+		cli.StartTUI(args[1:])
+	case "run":
+		os.Exit(cli.Run(args[1:], os.Stdout, os.Stderr))
+	case "exec":
+		os.Exit(cli.Exec(args[1:], os.Stdin, os.Stdout, os.Stderr))
+	case "serve":
+		os.Exit(cli.Serve(args[1:], os.Stdout, os.Stderr))
+	case "complete":
+		os.Exit(cli.Complete(args[1:], os.Stdout, os.Stderr))
+	case "completion":
+		os.Exit(cli.Completion(args[1:], os.Stdout, os.Stderr))
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
 	}
 }
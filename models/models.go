@@ -3,10 +3,12 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
 )
 
 type Category struct {
@@ -84,6 +86,7 @@ func (cat Category) MarshalJSON() ([]byte, error) {
 type AccountState struct {
 	ID          uint  `gorm:"primaryKey"`
 	CreatedAt   int64 `gorm:"autoCreateTime"`
+	EffectiveAt int64 `gorm:"index"` // unix timestamp of the day the balance took effect. Defaults to today
 	Balance     Money
 	PrevStateID *uint
 	PrevState   *AccountState
@@ -95,11 +98,21 @@ func (this AccountState) GetSession() *session.Session {
 	return this.Session
 }
 
+func (as *AccountState) BeforeCreate(tx *gorm.DB) error {
+	if as.EffectiveAt == 0 && as.CreatedAt != 0 {
+		as.EffectiveAt = as.CreatedAt
+	} else if as.EffectiveAt == 0 {
+		as.EffectiveAt = util.Today().Unix()
+	}
+	return nil
+}
+
 func (as AccountState) MarshalJSON() ([]byte, error) {
 	details := make(map[string]interface{})
 	details["id"] = as.ID
 
 	details["timestamp"] = time.Unix(as.CreatedAt, 0).UTC()
+	details["effectiveDate"] = time.Unix(as.EffectiveAt, 0).UTC()
 	details["balance"] = as.Balance.String(as.Session)
 
 	return json.Marshal(details)
@@ -126,6 +139,44 @@ func (account *Account) Balance() Money {
 	return account.CurrentState.Balance
 }
 
+// BalanceAt is the balance of the account at the start of day, going by when each change took effect rather than when
+// it was entered.
+func (account *Account) BalanceAt(day time.Time) (Money, error) {
+	balances, err := account.BalancesAt([]time.Time{day})
+	if err != nil {
+		return 0, err
+	}
+	return balances[0], nil
+}
+
+// BalancesAt is the balance of the account at the start of each of the days. Every state of the account changed the
+// balance of the state before it, so the balance on a day is the sum of the changes that took effect before it.
+func (account *Account) BalancesAt(days []time.Time) ([]Money, error) {
+	states := []AccountState{}
+	for id := account.CurrentStateID; id != nil; {
+		var state AccountState
+		if err := account.Session.Db.First(&state, *id).Error; err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+		id = state.PrevStateID
+	}
+
+	balances := make([]Money, len(days))
+	for i, state := range states {
+		change := state.Balance
+		if i+1 < len(states) {
+			change -= states[i+1].Balance
+		}
+		for j, day := range days {
+			if state.EffectiveAt < day.Unix() {
+				balances[j] += change
+			}
+		}
+	}
+	return balances, nil
+}
+
 func (account Account) MarshalJSON() ([]byte, error) {
 	details := make(map[string]interface{})
 	details["id"] = account.ID
@@ -148,6 +199,7 @@ func (account Account) MarshalJSON() ([]byte, error) {
 type Transaction struct {
 	ID            uint  `gorm:"primaryKey"`
 	CreatedAt     int64 `gorm:"autoCreateTime"`
+	EffectiveAt   int64 `gorm:"index"` // unix timestamp of the day the transaction happened. Defaults to today
 	Change        Money
 	SourceID      *uint
 	Source        *Account `gorm:"foreignkey:SourceID"`
@@ -157,6 +209,15 @@ type Transaction struct {
 	Session       *session.Session `gorm:"-"` // Ignored by ORM
 }
 
+func (tran *Transaction) BeforeCreate(tx *gorm.DB) error {
+	if tran.EffectiveAt == 0 && tran.CreatedAt != 0 {
+		tran.EffectiveAt = tran.CreatedAt
+	} else if tran.EffectiveAt == 0 {
+		tran.EffectiveAt = util.Today().Unix()
+	}
+	return nil
+}
+
 func (this Transaction) GetSession() *session.Session {
 	return this.Session
 }
@@ -168,10 +229,23 @@ func (tran *Transaction) DestinationExists() bool {
 	return tran.Destination != nil
 }
 
+// ChangeFor returns the effect this transaction has on the balance of the account with the given id.
+func (tran *Transaction) ChangeFor(accountID uint) Money {
+	var change int64 = 0
+	if tran.DestinationID != nil && *tran.DestinationID == accountID {
+		change += tran.Change.Value()
+	}
+	if tran.SourceID != nil && *tran.SourceID == accountID {
+		change -= tran.Change.Value()
+	}
+	return Money(change)
+}
+
 func (tran Transaction) MarshalJSON() ([]byte, error) {
 	details := make(map[string]interface{})
 	details["id"] = tran.ID
 	details["timestamp"] = time.Unix(tran.CreatedAt, 0).UTC()
+	details["date"] = time.Unix(tran.EffectiveAt, 0).UTC().Format("2006-01-02")
 	details["amount"] = tran.Change.String(tran.Session)
 
 	if tran.SourceExists() {
@@ -186,9 +260,143 @@ func (tran Transaction) MarshalJSON() ([]byte, error) {
 	return json.Marshal(details)
 }
 
+// ScheduledTransaction is a transaction that is expected to happen in the future, optionally repeating
+// every IntervalDays days.
+type ScheduledTransaction struct {
+	ID            uint  `gorm:"primaryKey"`
+	CreatedAt     int64 `gorm:"autoCreateTime"`
+	NextDate      int64 // unix timestamp of the next occurrence
+	IntervalDays  int   // days between occurrences. 0 means the transaction only happens once
+	Change        Money
+	SourceID      *uint
+	Source        *Account `gorm:"foreignkey:SourceID"`
+	DestinationID *uint
+	Destination   *Account `gorm:"foreignkey:DestinationID"`
+	Memo          string
+	Session       *session.Session `gorm:"-"` // Ignored by ORM
+}
+
+func (this ScheduledTransaction) GetSession() *session.Session {
+	return this.Session
+}
+
+func (st *ScheduledTransaction) IsRecurring() bool {
+	return st.IntervalDays > 0
+}
+
+// Occurrences returns the dates of every occurrence of the scheduled transaction in the range [from, to).
+func (st *ScheduledTransaction) Occurrences(from, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	next := time.Unix(st.NextDate, 0).UTC()
+
+	for next.Before(to) {
+		if !next.Before(from) {
+			occurrences = append(occurrences, next)
+		}
+		if !st.IsRecurring() {
+			break
+		}
+		next = next.AddDate(0, 0, st.IntervalDays)
+	}
+	return occurrences
+}
+
+// NextOccurrence returns the date of the first occurrence on or after from. ok is false when the transaction only
+// happens once and that day has passed.
+func (st *ScheduledTransaction) NextOccurrence(from time.Time) (next time.Time, ok bool) {
+	next = time.Unix(st.NextDate, 0).UTC()
+	if next.Before(from) && st.IsRecurring() {
+		periods := int(from.Sub(next).Hours()/24) / st.IntervalDays
+		next = next.AddDate(0, 0, periods*st.IntervalDays)
+		for next.Before(from) {
+			next = next.AddDate(0, 0, st.IntervalDays)
+		}
+	}
+	return next, !next.Before(from)
+}
+
+// ChangeFor returns the effect this transaction has on the balance of the account with the given id.
+func (st *ScheduledTransaction) ChangeFor(accountID uint) Money {
+	var change int64 = 0
+	if st.DestinationID != nil && *st.DestinationID == accountID {
+		change += st.Change.Value()
+	}
+	if st.SourceID != nil && *st.SourceID == accountID {
+		change -= st.Change.Value()
+	}
+	return Money(change)
+}
+
+func (st ScheduledTransaction) MarshalJSON() ([]byte, error) {
+	details := make(map[string]interface{})
+	details["id"] = st.ID
+	details["nextDate"] = time.Unix(st.NextDate, 0).UTC()
+	details["intervalDays"] = st.IntervalDays
+	details["amount"] = st.Change.String(st.Session)
+
+	if st.Source != nil {
+		details["fromAccount"] = st.Source.Name
+	}
+	if st.Destination != nil {
+		details["toAccount"] = st.Destination.Name
+	}
+
+	details["memo"] = st.Memo
+
+	return json.Marshal(details)
+}
+
+// Macro is a command defined by the user that expands to another command before it is parsed. The expansion refers to
+// the positional parameters as $1 to $n. An alias is a macro without parameters.
+type Macro struct {
+	ID        uint   `gorm:"primaryKey"`
+	CreatedAt int64  `gorm:"autoCreateTime"`
+	Name      string `gorm:"unique"`
+	Params    int    // number of positional parameters
+	Expansion string
+	Session   *session.Session `gorm:"-"` // Ignored by ORM
+}
+
+func (this Macro) GetSession() *session.Session {
+	return this.Session
+}
+
+func (macro Macro) IsAlias() bool {
+	return macro.Params == 0
+}
+
+// Definition is the command that defines the macro, e.g. "macro coffee $1 = new transaction $1 --from=cash"
+func (macro Macro) Definition() string {
+	if macro.IsAlias() {
+		return fmt.Sprintf("alias %s = %s", macro.Name, macro.Expansion)
+	}
+	params := make([]string, macro.Params)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return fmt.Sprintf("macro %s %s = %s", macro.Name, strings.Join(params, " "), macro.Expansion)
+}
+
+func (macro Macro) MarshalJSON() ([]byte, error) {
+	details := make(map[string]interface{})
+	details["id"] = macro.ID
+	details["name"] = macro.Name
+	details["params"] = macro.Params
+	details["expansion"] = macro.Expansion
+	details["definition"] = macro.Definition()
+
+	return json.Marshal(details)
+}
+
 func MigrateSchema(db *gorm.DB) {
 	db.AutoMigrate(&Category{})
 	db.AutoMigrate(&AccountState{})
 	db.AutoMigrate(&Account{})
 	db.AutoMigrate(&Transaction{})
+	db.AutoMigrate(&ScheduledTransaction{})
+	db.AutoMigrate(&Macro{})
+
+	// Rows from before effective dates existed took effect when they were created
+	db.Model(&AccountState{}).Where("effective_at = 0 OR effective_at IS NULL").Update("effective_at", gorm.Expr("created_at"))
+	db.Model(&Transaction{}).Where("effective_at = 0 OR effective_at IS NULL").Update("effective_at", gorm.Expr("created_at"))
 }
@@ -21,6 +21,7 @@ func TestCategoryMarshal(t *testing.T) {
 	state1 := AccountState{
 		123,
 		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		MakeMoney(432.12),
 		nil, nil,
 		false,
@@ -29,6 +30,7 @@ func TestCategoryMarshal(t *testing.T) {
 	state2 := AccountState{
 		124,
 		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		MakeMoney(123.45),
 		nil, nil,
 		false,
@@ -110,6 +112,7 @@ func TestAccountStateMarshal(t *testing.T) {
 	as := AccountState{
 		123,
 		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		MakeMoney(432.12),
 		nil, nil,
 		false,
@@ -125,7 +128,8 @@ func TestAccountStateMarshal(t *testing.T) {
 	expected := `{
 		"id":123,
 		"balance":"432.12 USD",
-		"timestamp":"2020-01-01T00:00:00Z"
+		"timestamp":"2020-01-01T00:00:00Z",
+		"effectiveDate":"2020-01-01T00:00:00Z"
 	}`
 
 	assert.JSONEq(t, expected, jsonStr)
@@ -138,6 +142,7 @@ func TestAccountMarshal(t *testing.T) {
 	state := AccountState{
 		123,
 		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		MakeMoney(123.45),
 		nil, nil,
 		false,
@@ -186,6 +191,7 @@ func TestTransactionMarshal(t *testing.T) {
 	fromAccountState := AccountState{
 		1,
 		time.Now().Unix(),
+		time.Now().Unix(),
 		MakeMoney(12.34),
 		nil, nil,
 		false,
@@ -203,6 +209,7 @@ func TestTransactionMarshal(t *testing.T) {
 	toAccountState := AccountState{
 		2,
 		time.Now().Unix(),
+		time.Now().Unix(),
 		MakeMoney(12.34),
 		nil, nil,
 		false,
@@ -220,6 +227,7 @@ func TestTransactionMarshal(t *testing.T) {
 	transaction := Transaction{
 		123,
 		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		MakeMoney(123.45),
 		&fromAccount.ID,
 		&fromAccount,
@@ -241,9 +249,67 @@ func TestTransactionMarshal(t *testing.T) {
 		"amount":"123.45 USD",
 		"fromAccount":"Source",
 		"toAccount":"Sink",
-		"timestamp":"2020-01-01T00:00:00Z"
+		"timestamp":"2020-01-01T00:00:00Z",
+		"date":"2020-01-01"
 	}`
 
 	assert.JSONEq(t, expected, jsonStr)
 
 }
+
+func TestAccountBalanceAt(t *testing.T) {
+	s := session.InMemorySession(MigrateSchema)
+
+	day := func(month, day int) time.Time {
+		return time.Date(2026, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+
+	// opened with 100 on Sep 1, 50 in on Oct 10, then 20 out entered last but dated Sep 20
+	opened := AccountState{EffectiveAt: day(9, 1).Unix(), Balance: MakeMoney(100)}
+	s.Db.Create(&opened)
+	deposit := AccountState{EffectiveAt: day(10, 10).Unix(), Balance: MakeMoney(150), PrevStateID: &opened.ID}
+	s.Db.Create(&deposit)
+	backdated := AccountState{EffectiveAt: day(9, 20).Unix(), Balance: MakeMoney(130), PrevStateID: &deposit.ID}
+	s.Db.Create(&backdated)
+
+	account := Account{Name: "checking", IsActive: true, CurrentStateID: &backdated.ID, CurrentState: backdated, Session: &s}
+
+	testCase := func(at time.Time, expected Money) func(t *testing.T) {
+		return func(t *testing.T) {
+			balance, err := account.BalanceAt(at)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, balance)
+		}
+	}
+
+	t.Run("before the account", testCase(day(8, 1), 0))
+	t.Run("opening day", testCase(day(9, 2), MakeMoney(100)))
+	t.Run("after backdated", testCase(day(10, 1), MakeMoney(80)))
+	t.Run("after deposit", testCase(day(10, 11), MakeMoney(130)))
+}
+
+func TestScheduledTransactionNextOccurrence(t *testing.T) {
+	day := func(month, day int) time.Time {
+		return time.Date(2026, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+
+	rent := ScheduledTransaction{NextDate: day(9, 1).Unix(), IntervalDays: 30}
+	bonus := ScheduledTransaction{NextDate: day(9, 15).Unix()}
+
+	testCase := func(st ScheduledTransaction, from time.Time, expected time.Time, expectedOk bool) func(t *testing.T) {
+		return func(t *testing.T) {
+			next, ok := st.NextOccurrence(from)
+			assert.Equal(t, expectedOk, ok)
+			if ok {
+				assert.Equal(t, expected, next)
+			}
+		}
+	}
+
+	t.Run("before the first", testCase(rent, day(8, 20), day(9, 1), true))
+	t.Run("on the first", testCase(rent, day(9, 1), day(9, 1), true))
+	t.Run("after the first", testCase(rent, day(9, 2), day(10, 1), true))
+	t.Run("several periods later", testCase(rent, day(11, 15), day(11, 30), true))
+	t.Run("once before", testCase(bonus, day(9, 1), day(9, 15), true))
+	t.Run("once passed", testCase(bonus, day(9, 16), time.Time{}, false))
+}
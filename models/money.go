@@ -11,10 +11,9 @@ import (
 
 type Money int64
 
+// MakeMoney converts an amount of dollars to Money, rounding to the nearest cent
 func MakeMoney(value float64) Money {
-	dollars, cents := math.Modf(value)
-
-	return Money(int(dollars*100) + int(cents*100))
+	return Money(math.Round(value * 100))
 }
 
 func (m Money) Cents() int64 {
@@ -35,8 +34,8 @@ func (m Money) Value() int64 {
 
 func (m Money) String(s *session.Session) string {
 	if m.IsNegative() {
-		return fmt.Sprintf("(%s%s.%d)%s", s.CurrencyPrefix, humanize.Comma(util.AbsI64(m.Dollars())), util.AbsI64(m.Cents()), s.CurrencySuffixWithSpace())
+		return fmt.Sprintf("(%s%s.%02d)%s", s.CurrencyPrefix, humanize.Comma(util.AbsI64(m.Dollars())), util.AbsI64(m.Cents()), s.CurrencySuffixWithSpace())
 	} else {
-		return fmt.Sprintf("%s%s.%d%s", s.CurrencyPrefix, humanize.Comma(m.Dollars()), m.Cents(), s.CurrencySuffixWithSpace())
+		return fmt.Sprintf("%s%s.%02d%s", s.CurrencyPrefix, humanize.Comma(m.Dollars()), m.Cents(), s.CurrencySuffixWithSpace())
 	}
 }
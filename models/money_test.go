@@ -55,4 +55,15 @@ func TestString(t *testing.T) {
 	negativeStr := negative.String(&session)
 
 	assert.Equal(t, "($123.45) USD", negativeStr)
+
+	assert.Equal(t, "$1,234.05 USD", MakeMoney(1234.05).String(&session))
+	assert.Equal(t, "$0.00 USD", MakeMoney(0).String(&session))
+	assert.Equal(t, "($0.07) USD", MakeMoney(-0.07).String(&session))
+}
+
+func TestMakeMoney(t *testing.T) {
+	assert.Equal(t, Money(12345), MakeMoney(123.45))
+	assert.Equal(t, Money(-12345), MakeMoney(-123.45))
+	assert.Equal(t, Money(29), MakeMoney(0.29))
+	assert.Equal(t, Money(100), MakeMoney(0.999))
 }
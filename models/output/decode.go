@@ -0,0 +1,174 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownKind = errors.New("unknown output kind")
+
+type envelope struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// DecodeHelpers parses a JSON array of helpers, as produced by marshaling a []Helper, back into typed values.
+func DecodeHelpers(data []byte) ([]Helper, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	helpers, err := decodeAll(raw)
+	if err != nil {
+		return nil, err
+	}
+	if helpers == nil {
+		helpers = []Helper{}
+	}
+	return helpers, nil
+}
+
+// DecodeHelper parses a single {"kind": ..., "data": ...} object into the helper type named by its kind.
+func DecodeHelper(data []byte) (Helper, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+
+	switch e.Kind {
+	case "text":
+		var t Text
+		err := t.UnmarshalJSON(data)
+		return t, err
+	case "unorderedList":
+		var ul UnorderedList
+		err := ul.UnmarshalJSON(data)
+		return ul, err
+	case "orderedList":
+		var ol OrderedList
+		err := ol.UnmarshalJSON(data)
+		return ol, err
+	case "horizontalRule":
+		var hr HorizontalRule
+		err := hr.UnmarshalJSON(data)
+		return hr, err
+	case "table":
+		var t Table
+		err := t.UnmarshalJSON(data)
+		return t, err
+	case "barChart":
+		var bc BarChart
+		err := bc.UnmarshalJSON(data)
+		return bc, err
+	case "sparkline":
+		var sl Sparkline
+		err := sl.UnmarshalJSON(data)
+		return sl, err
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownKind, e.Kind)
+	}
+}
+
+// decodeAll decodes each helper in order. Returns nil when there is nothing to decode.
+func decodeAll(raw []json.RawMessage) ([]Helper, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	helpers := make([]Helper, len(raw))
+	for i, r := range raw {
+		helper, err := DecodeHelper(r)
+		if err != nil {
+			return nil, err
+		}
+		helpers[i] = helper
+	}
+	return helpers, nil
+}
+
+// unwrap checks that data is an envelope of the expected kind and returns its data
+func unwrap(data []byte, kind string) (json.RawMessage, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.Kind != kind {
+		return nil, fmt.Errorf("expected output kind %q but found %q", kind, e.Kind)
+	}
+	return e.Data, nil
+}
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "text")
+	if err != nil {
+		return err
+	}
+
+	var fields struct {
+		Text     string            `json:"text"`
+		Indent   int               `json:"indent"`
+		Style    TextStyle         `json:"style"`
+		Children []json.RawMessage `json:"children"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+
+	children, err := decodeAll(fields.Children)
+	if err != nil {
+		return err
+	}
+
+	*t = Text{Text: fields.Text, Indent: fields.Indent, Style: fields.Style, Children: children}
+	return nil
+}
+
+func (ul *UnorderedList) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "unorderedList")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeUnorderedList)(ul))
+}
+
+func (ol *OrderedList) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "orderedList")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeOrderedList)(ol))
+}
+
+func (hr *HorizontalRule) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "horizontalRule")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeHorizontalRule)(hr))
+}
+
+func (t *Table) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "table")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeTable)(t))
+}
+
+func (bc *BarChart) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "barChart")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeBarChart)(bc))
+}
+
+func (sl *Sparkline) UnmarshalJSON(data []byte) error {
+	raw, err := unwrap(data, "sparkline")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, (*FakeSparkline)(sl))
+}
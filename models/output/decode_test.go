@@ -0,0 +1,80 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHelpersRoundTrip(t *testing.T) {
+	style := TextStyle{Color: Warning, IsItalic: true}
+
+	group := EmptyOutputGroup()
+	nested := group.SubGroup().OrderedList([]string{"child 1", "child 2"}, UpperRoman).ToSlice()
+
+	helpers := group.
+		Header("Header").
+		HorizontalRule("═").
+		PushStyle(style).
+		Paragraph("Styled paragraph").
+		PopStyle().
+		EmptyLines(2).
+		Indent().
+		UnorderedList([]string{"one", "two"}, NormalBulletChar).
+		OrderedList([]string{"first", "second"}, LowerAlpha).
+		Unindent().
+		UnorderedListItems([]Text{group.Item("parent", nested...), group.Item("sibling")}, "-").
+		Table([]TableColumn{MakeColumn("Name", TextColumn), MakeColumn("When", DateColumn), MakeColumn("Amount", MoneyColumn)},
+			[][]TableCell{{Cell("cash"), Cell("2021-01-01"), ColoredCell("(1.00)", Error)}},
+			[]TableCell{Cell("Total"), Cell(""), Cell("(1.00)")}).
+		Table([]TableColumn{MakeColumn("Empty", TextColumn)}, [][]TableCell{}).
+		BarChart("Balances", []Bar{{Label: "cash", Value: 12.5, Display: "12.50"}, {Label: "credit", Value: -3, Display: "(3.00)", Color: Error}}).
+		Sparkline("Trend", []float64{1, -2.5, 3}).
+		ToSlice()
+
+	b, err := json.Marshal(helpers)
+	assert.Nil(t, err)
+
+	decoded, err := DecodeHelpers(b)
+
+	assert.Nil(t, err)
+	assert.Equal(t, helpers, decoded)
+}
+
+func TestDecodeHelpersEmpty(t *testing.T) {
+	decoded, err := DecodeHelpers([]byte(`[]`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []Helper{}, decoded)
+}
+
+func TestDecodeHelpersUnknownKind(t *testing.T) {
+	decoded, err := DecodeHelpers([]byte(`[{"kind": "text", "data": {"text": "ok"}}, {"kind": "hologram", "data": {}}]`))
+
+	assert.Nil(t, decoded)
+	assert.True(t, errors.Is(err, ErrUnknownKind))
+	assert.Contains(t, err.Error(), `"hologram"`)
+}
+
+func TestDecodeHelpersUnknownNestedKind(t *testing.T) {
+	input := `[{"kind": "unorderedList", "data": {"items": [{"kind": "text", "data": {"text": "a", "children": [{"kind": "hologram"}]}}]}}]`
+
+	_, err := DecodeHelpers([]byte(input))
+
+	assert.True(t, errors.Is(err, ErrUnknownKind))
+}
+
+func TestDecodeHelpersInvalidJSON(t *testing.T) {
+	_, err := DecodeHelpers([]byte(`{"kind": "text"}`))
+
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshalWrongKind(t *testing.T) {
+	var text Text
+	err := json.Unmarshal([]byte(`{"kind": "horizontalRule", "data": {"ruleChar": "-"}}`), &text)
+
+	assert.NotNil(t, err)
+}
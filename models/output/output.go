@@ -2,6 +2,7 @@ package output
 
 import (
 	"encoding/json"
+	"math"
 	"strings"
 )
 
@@ -40,9 +41,10 @@ var DefaultStyle *TextStyle = &TextStyle{Color: Body, IsItalic: false, IsUnderli
 var HeaderStyle *TextStyle = &TextStyle{Color: Body, IsItalic: false, IsUnderline: false, IsBold: true}
 
 type Text struct {
-	Text   string    `json:"text"`
-	Indent int       `json:"indent"`
-	Style  TextStyle `json:"style"`
+	Text     string    `json:"text"`
+	Indent   int       `json:"indent"`
+	Style    TextStyle `json:"style"`
+	Children []Helper  `json:"children,omitempty"` // Nested lists shown below the text when it is a list item
 }
 
 func (t Text) String() string {
@@ -152,6 +154,171 @@ func (hr HorizontalRule) MarshalJSON() ([]byte, error) {
 	})
 }
 
+type ColumnKind string
+
+const (
+	TextColumn  ColumnKind = "text"
+	MoneyColumn ColumnKind = "money"
+	DateColumn  ColumnKind = "date"
+)
+
+type Alignment string
+
+const (
+	AlignLeft   Alignment = "left"
+	AlignCenter Alignment = "center"
+	AlignRight  Alignment = "right"
+)
+
+type TableColumn struct {
+	Header string     `json:"header"`
+	Kind   ColumnKind `json:"kind"`
+	Align  Alignment  `json:"align"`
+}
+
+// MakeColumn creates a column aligned the usual way for its kind. Money is right aligned, everything else is left aligned.
+func MakeColumn(header string, kind ColumnKind) TableColumn {
+	align := AlignLeft
+	if kind == MoneyColumn {
+		align = AlignRight
+	}
+	return TableColumn{Header: header, Kind: kind, Align: align}
+}
+
+type TableCell struct {
+	Text  string    `json:"text"`
+	Color ColorHint `json:"color,omitempty"` // Overrides the table style color when set
+}
+
+func Cell(text string) TableCell {
+	return TableCell{Text: text}
+}
+
+func ColoredCell(text string, color ColorHint) TableCell {
+	return TableCell{Text: text, Color: color}
+}
+
+type Table struct {
+	Columns     []TableColumn `json:"columns"`
+	HeaderStyle TextStyle     `json:"headerStyle"`
+	Rows        [][]TableCell `json:"rows"`
+	Footers     [][]TableCell `json:"footers,omitempty"` // Rows shown below the body, such as totals
+	Indent      int           `json:"indent"`
+	Style       TextStyle     `json:"style"`
+}
+
+func (t Table) String() string {
+	b, err := json.Marshal(t)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+type FakeTable Table // to avoid recursive JSON marshaling
+func (t Table) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind string    `json:"kind"`
+		Data FakeTable `json:"data"`
+	}{
+		"table",
+		FakeTable(t),
+	})
+}
+
+type Bar struct {
+	Label   string    `json:"label"`
+	Value   float64   `json:"value"`
+	Display string    `json:"display"`         // Formatted value shown next to the bar
+	Color   ColorHint `json:"color,omitempty"` // Overrides the chart style color when set
+}
+
+type BarChart struct {
+	Title  string    `json:"title,omitempty"`
+	Bars   []Bar     `json:"bars"`
+	Indent int       `json:"indent"`
+	Style  TextStyle `json:"style"`
+}
+
+func (bc BarChart) String() string {
+	b, err := json.Marshal(bc)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+type FakeBarChart BarChart // to avoid recursive JSON marshaling
+func (bc BarChart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind string       `json:"kind"`
+		Data FakeBarChart `json:"data"`
+	}{
+		"barChart",
+		FakeBarChart(bc),
+	})
+}
+
+type Sparkline struct {
+	Label  string    `json:"label,omitempty"`
+	Values []float64 `json:"values"`
+	Indent int       `json:"indent"`
+	Style  TextStyle `json:"style"`
+}
+
+func (sl Sparkline) String() string {
+	b, err := json.Marshal(sl)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+type FakeSparkline Sparkline // to avoid recursive JSON marshaling
+func (sl Sparkline) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind string        `json:"kind"`
+		Data FakeSparkline `json:"data"`
+	}{
+		"sparkline",
+		FakeSparkline(sl),
+	})
+}
+
+// Sparkline levels, from lowest to highest
+var SparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// SparkLevels scales each value to an index in SparkBlocks relative to the smallest and largest values
+func SparkLevels(values []float64) []int {
+	if len(values) == 0 {
+		return []int{}
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+
+	levels := make([]int, len(values))
+	for i, v := range values {
+		levels[i] = len(SparkBlocks) - 1
+		if high > low {
+			levels[i] = int(math.Round((v - low) / (high - low) * float64(len(SparkBlocks)-1)))
+		}
+	}
+	return levels
+}
+
+// SparklineText draws one unstyled block character per value.
+func SparklineText(values []float64) string {
+	sb := strings.Builder{}
+	for _, level := range SparkLevels(values) {
+		sb.WriteRune(SparkBlocks[level])
+	}
+	return sb.String()
+}
+
 // Functions for making output
 
 type OutputGroup struct {
@@ -190,6 +357,16 @@ func (g *OutputGroup) CurrentStyle() TextStyle {
 	return g.styleStack[len(g.styleStack)-1]
 }
 
+// SubGroup starts a new group one level deeper than this one with the same style. Use it to build nested lists.
+func (g *OutputGroup) SubGroup() *OutputGroup {
+	return &OutputGroup{currentIndent: g.currentIndent + 1, styleStack: []TextStyle{g.CurrentStyle()}}
+}
+
+// Item makes a list item with the current indent and style. Children are shown nested below the item.
+func (g *OutputGroup) Item(text string, children ...Helper) Text {
+	return Text{Text: text, Indent: g.currentIndent, Style: g.CurrentStyle(), Children: children}
+}
+
 func (g *OutputGroup) Header(text string) *OutputGroup {
 	g.items = append(g.items, Text{Text: text, Indent: g.currentIndent, Style: *HeaderStyle})
 	return g
@@ -214,6 +391,11 @@ func (g *OutputGroup) OrderedList(items []string, bulletStyle BulletStyle) *Outp
 	return g
 }
 
+func (g *OutputGroup) OrderedListItems(items []Text, bulletStyle BulletStyle) *OutputGroup {
+	g.items = append(g.items, OrderedList{BulletStyle: bulletStyle, Items: items, Indent: g.currentIndent, Style: g.CurrentStyle()})
+	return g
+}
+
 func (g *OutputGroup) UnorderedList(items []string, bulletChar string) *OutputGroup {
 	var listItems []Text
 	for _, item := range items {
@@ -224,6 +406,26 @@ func (g *OutputGroup) UnorderedList(items []string, bulletChar string) *OutputGr
 	return g
 }
 
+func (g *OutputGroup) Table(columns []TableColumn, rows [][]TableCell, footers ...[]TableCell) *OutputGroup {
+	g.items = append(g.items, Table{Columns: columns, HeaderStyle: *HeaderStyle, Rows: rows, Footers: footers, Indent: g.currentIndent, Style: g.CurrentStyle()})
+	return g
+}
+
+func (g *OutputGroup) BarChart(title string, bars []Bar) *OutputGroup {
+	g.items = append(g.items, BarChart{Title: title, Bars: bars, Indent: g.currentIndent, Style: g.CurrentStyle()})
+	return g
+}
+
+func (g *OutputGroup) Sparkline(label string, values []float64) *OutputGroup {
+	g.items = append(g.items, Sparkline{Label: label, Values: values, Indent: g.currentIndent, Style: g.CurrentStyle()})
+	return g
+}
+
+func (g *OutputGroup) UnorderedListItems(items []Text, bulletChar string) *OutputGroup {
+	g.items = append(g.items, UnorderedList{BulletChar: bulletChar, Items: items, Indent: g.currentIndent, Style: g.CurrentStyle()})
+	return g
+}
+
 func (g *OutputGroup) EmptyLines(numLines int) *OutputGroup {
 	g.items = append(g.items, Text{Text: strings.Repeat("\n", numLines), Indent: g.currentIndent, Style: g.CurrentStyle()})
 	return g
@@ -23,7 +23,7 @@ func TestTextStyleString(t *testing.T) {
 func TestMarshalOutputText(t *testing.T) {
 	var color ColorHint = "mycolor"
 	style := TextStyle{color, true, false, true}
-	text := Text{"This is the text", 2, style}
+	text := Text{Text: "This is the text", Indent: 2, Style: style}
 
 	b, err := json.Marshal(text)
 
@@ -117,6 +117,162 @@ func TestMarshalOutputUnorderedList(t *testing.T) {
 	assert.JSONEq(t, expected, string(b))
 }
 
+func TestMarshalOutputTable(t *testing.T) {
+	var color ColorHint = "mycolor"
+	style := TextStyle{color, true, false, true}
+
+	table := Table{
+		Columns:     []TableColumn{MakeColumn("Name", TextColumn), MakeColumn("Balance", MoneyColumn)},
+		HeaderStyle: *HeaderStyle,
+		Rows:        [][]TableCell{{Cell("cash"), ColoredCell("(1.00)", Error)}},
+		Footers:     [][]TableCell{{Cell("Total"), Cell("(1.00)")}},
+		Indent:      1,
+		Style:       style,
+	}
+
+	b, err := json.Marshal(table)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := fmt.Sprintf(
+		`{
+			"kind": "table",
+			"data": {
+				"columns": [
+					{"header": "Name", "kind": "text", "align": "left"},
+					{"header": "Balance", "kind": "money", "align": "right"}
+				],
+				"headerStyle": %s,
+				"rows": [[{"text": "cash"}, {"text": "(1.00)", "color": "error"}]],
+				"footers": [[{"text": "Total"}, {"text": "(1.00)"}]],
+				"indent": 1,
+				"style": %s
+			}
+		}`, HeaderStyle.String(), style.String())
+
+	assert.JSONEq(t, expected, string(b))
+}
+
+func TestOutputGroupTable(t *testing.T) {
+	columns := []TableColumn{MakeColumn("Date", DateColumn), MakeColumn("Amount", MoneyColumn)}
+	rows := [][]TableCell{{Cell("2021-01-01"), Cell("1.00")}}
+
+	items := EmptyOutputGroup().
+		Indent().
+		Table(columns, rows).
+		ToSlice()
+
+	table := items[0].(Table)
+	assert.Equal(t, columns, table.Columns)
+	assert.Equal(t, rows, table.Rows)
+	assert.Empty(t, table.Footers)
+	assert.Equal(t, *HeaderStyle, table.HeaderStyle)
+	assert.Equal(t, *DefaultStyle, table.Style)
+	assert.Equal(t, 1, table.Indent)
+	assert.Equal(t, AlignLeft, table.Columns[0].Align)
+	assert.Equal(t, AlignRight, table.Columns[1].Align)
+}
+
+func TestMarshalOutputBarChart(t *testing.T) {
+	var color ColorHint = "mycolor"
+	style := TextStyle{color, true, false, true}
+
+	chart := BarChart{
+		Title:  "Balances",
+		Bars:   []Bar{{Label: "cash", Value: 12.5, Display: "12.50"}, {Label: "credit", Value: -3, Display: "(3.00)", Color: Error}},
+		Indent: 1,
+		Style:  style,
+	}
+
+	b, err := json.Marshal(chart)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := fmt.Sprintf(
+		`{
+			"kind": "barChart",
+			"data": {
+				"title": "Balances",
+				"bars": [
+					{"label": "cash", "value": 12.5, "display": "12.50"},
+					{"label": "credit", "value": -3, "display": "(3.00)", "color": "error"}
+				],
+				"indent": 1,
+				"style": %s
+			}
+		}`, style.String())
+
+	assert.JSONEq(t, expected, string(b))
+}
+
+func TestMarshalOutputSparkline(t *testing.T) {
+	var color ColorHint = "mycolor"
+	style := TextStyle{color, true, false, true}
+
+	sparkline := Sparkline{Label: "trend", Values: []float64{1, -2, 3.5}, Indent: 0, Style: style}
+
+	b, err := json.Marshal(sparkline)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := fmt.Sprintf(`{"kind": "sparkline", "data": {"label": "trend", "values": [1, -2, 3.5], "indent": 0, "style": %s}}`, style.String())
+
+	assert.JSONEq(t, expected, string(b))
+}
+
+func TestSparklineText(t *testing.T) {
+	assert.Equal(t, "▁▅█▁", SparklineText([]float64{-2, 1, 3, -2}))
+	assert.Equal(t, "███", SparklineText([]float64{4, 4, 4}))
+	assert.Equal(t, "", SparklineText(nil))
+}
+
+func TestMarshalOutputNestedList(t *testing.T) {
+	group := EmptyOutputGroup()
+	nested := group.SubGroup().UnorderedList([]string{"child"}, "-").ToSlice()
+	items := group.UnorderedListItems([]Text{group.Item("parent", nested...)}, "*").ToSlice()
+
+	b, err := json.Marshal(items[0])
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := fmt.Sprintf(
+		`{
+			"kind": "unorderedList",
+			"data": {
+				"bullet": "*",
+				"indent": 0,
+				"style": %[1]s,
+				"items": [{
+					"kind": "text",
+					"data": {
+						"text": "parent",
+						"indent": 0,
+						"style": %[1]s,
+						"children": [{
+							"kind": "unorderedList",
+							"data": {
+								"bullet": "-",
+								"indent": 1,
+								"style": %[1]s,
+								"items": [{"kind": "text", "data": {"text": "child", "indent": 1, "style": %[1]s}}]
+							}
+						}]
+					}
+				}]
+			}
+		}`, DefaultStyle.String())
+
+	assert.JSONEq(t, expected, string(b))
+}
+
 func TestEmptyOutputGroup(t *testing.T) {
 	group := EmptyOutputGroup()
 
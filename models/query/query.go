@@ -0,0 +1,240 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"samvasta.com/bujit/util"
+)
+
+// Kind is the type of value a field holds, which decides the operators it accepts and how its values are read
+type Kind int
+
+const (
+	Text  Kind = iota
+	Money      // stored in cents
+	Date       // stored as a unix timestamp, compared against days or periods
+)
+
+// Field is a name that can be used in a filter or ordering. Column is the SQL it stands for, which is never built from
+// user input.
+type Field struct {
+	Name   string
+	Column string
+	Kind   Kind
+}
+
+// Fields are the fields of one list command, in the order they are suggested
+type Fields []Field
+
+func (fields Fields) Find(name string) (Field, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+func (fields Fields) Names() []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+type Op string
+
+const (
+	Equal          Op = "="
+	NotEqual       Op = "!="
+	Greater        Op = ">"
+	GreaterOrEqual Op = ">="
+	Less           Op = "<"
+	LessOrEqual    Op = "<="
+	Contains       Op = "~"
+	NotContains    Op = "!~"
+)
+
+// Ops are every operator, longest first so they can be matched greedily
+var Ops = []Op{GreaterOrEqual, LessOrEqual, NotEqual, NotContains, Equal, Greater, Less, Contains}
+
+// OpsFor are the operators a field of the kind accepts
+func OpsFor(kind Kind) []Op {
+	if kind == Text {
+		return []Op{Equal, NotEqual, Contains, NotContains}
+	}
+	return []Op{Equal, NotEqual, Greater, GreaterOrEqual, Less, LessOrEqual}
+}
+
+func (op Op) IsValidFor(kind Kind) bool {
+	for _, valid := range OpsFor(kind) {
+		if op == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Expr is a filter condition
+type Expr interface {
+	// SQL is the condition as a parameterised where clause and its arguments
+	SQL() (string, []interface{})
+}
+
+// Comparison compares a field to a value. Value is a string for text fields, cents as int64 for money fields and a
+// util.DateRange for date fields.
+type Comparison struct {
+	Field Field
+	Op    Op
+	Value interface{}
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+func (c Comparison) SQL() (string, []interface{}) {
+	column := c.Field.Column
+	switch value := c.Value.(type) {
+	case util.DateRange:
+		start, end := value.Start.Unix(), value.End.Unix()
+		// A date matches when it is inside the day or period, and is before or after it when it is before its start or
+		// after its end
+		switch c.Op {
+		case Equal:
+			return fmt.Sprintf("(%s >= ? AND %s < ?)", column, column), []interface{}{start, end}
+		case NotEqual:
+			return fmt.Sprintf("(%s < ? OR %s >= ?)", column, column), []interface{}{start, end}
+		case Greater:
+			return column + " >= ?", []interface{}{end}
+		case GreaterOrEqual:
+			return column + " >= ?", []interface{}{start}
+		case Less:
+			return column + " < ?", []interface{}{start}
+		default:
+			return column + " < ?", []interface{}{end}
+		}
+	case string:
+		switch c.Op {
+		case Contains:
+			return column + ` LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(value) + "%"}
+		case NotContains:
+			return column + ` NOT LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(value) + "%"}
+		case NotEqual:
+			return fmt.Sprintf("LOWER(%s) <> LOWER(?)", column), []interface{}{value}
+		default:
+			return fmt.Sprintf("LOWER(%s) = LOWER(?)", column), []interface{}{value}
+		}
+	default:
+		op := string(c.Op)
+		if c.Op == NotEqual {
+			op = "<>"
+		}
+		return fmt.Sprintf("%s %s ?", column, op), []interface{}{value}
+	}
+}
+
+func (and And) SQL() (string, []interface{}) {
+	return join("AND", and.Left, and.Right)
+}
+
+func (or Or) SQL() (string, []interface{}) {
+	return join("OR", or.Left, or.Right)
+}
+
+func (not Not) SQL() (string, []interface{}) {
+	sql, args := not.Expr.SQL()
+	return "NOT " + sql, args
+}
+
+func join(op string, left, right Expr) (string, []interface{}) {
+	leftSQL, leftArgs := left.SQL()
+	rightSQL, rightArgs := right.SQL()
+	return fmt.Sprintf("(%s %s %s)", leftSQL, op, rightSQL), append(leftArgs, rightArgs...)
+}
+
+// escapeLike makes the wildcards of LIKE match themselves
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+type OrderBy struct {
+	Field Field
+	Desc  bool
+}
+
+// Query is the filter, ordering and limit given after a list command, e.g.
+// "filter balance > 100 and name ~ visa order by balance desc limit 10"
+type Query struct {
+	Filter Expr // nil when there is no filter
+	Order  []OrderBy
+	Limit  int // 0 means no limit
+}
+
+func (q Query) IsOrdered() bool {
+	return len(q.Order) > 0
+}
+
+// WithColumn is the query with the named field read from a different column, such as an expression that computes it
+func (q Query) WithColumn(name, column string) Query {
+	order := make([]OrderBy, len(q.Order))
+	for i, o := range q.Order {
+		order[i] = OrderBy{Field: withColumn(o.Field, name, column), Desc: o.Desc}
+	}
+	return Query{Filter: exprWithColumn(q.Filter, name, column), Order: order, Limit: q.Limit}
+}
+
+func withColumn(field Field, name, column string) Field {
+	if strings.EqualFold(field.Name, name) {
+		field.Column = column
+	}
+	return field
+}
+
+func exprWithColumn(expr Expr, name, column string) Expr {
+	switch e := expr.(type) {
+	case Comparison:
+		return Comparison{Field: withColumn(e.Field, name, column), Op: e.Op, Value: e.Value}
+	case And:
+		return And{Left: exprWithColumn(e.Left, name, column), Right: exprWithColumn(e.Right, name, column)}
+	case Or:
+		return Or{Left: exprWithColumn(e.Left, name, column), Right: exprWithColumn(e.Right, name, column)}
+	case Not:
+		return Not{Expr: exprWithColumn(e.Expr, name, column)}
+	}
+	return expr
+}
+
+// Apply adds the query to the database query. Its ordering comes after any ordering the database query already has, so
+// callers only add their default ordering when the query is not ordered.
+func (q Query) Apply(db *gorm.DB) *gorm.DB {
+	if q.Filter != nil {
+		sql, args := q.Filter.SQL()
+		db = db.Where(sql, args...)
+	}
+	if q.IsOrdered() {
+		clauses := make([]string, len(q.Order))
+		for i, order := range q.Order {
+			clauses[i] = order.Field.Column
+			if order.Desc {
+				clauses[i] += " DESC"
+			}
+		}
+		db = db.Order(strings.Join(clauses, ", "))
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	return db
+}
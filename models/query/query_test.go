@@ -0,0 +1,96 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/util"
+)
+
+var balance = Field{Name: "balance", Column: "balance", Kind: Money}
+var name = Field{Name: "name", Column: "name", Kind: Text}
+var created = Field{Name: "created", Column: "created_at", Kind: Date}
+
+func TestComparisonSQL(t *testing.T) {
+	october := util.DateRange{Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}
+	start, end := october.Start.Unix(), october.End.Unix()
+
+	testCase := func(c Comparison, expectedSQL string, expectedArgs ...interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			sql, args := c.SQL()
+			assert.Equal(t, expectedSQL, sql)
+			assert.Equal(t, expectedArgs, args)
+		}
+	}
+
+	t.Run("money", testCase(Comparison{balance, Greater, int64(10000)}, "balance > ?", int64(10000)))
+	t.Run("money not equal", testCase(Comparison{balance, NotEqual, int64(0)}, "balance <> ?", int64(0)))
+	t.Run("text equal ignores case", testCase(Comparison{name, Equal, "Cash"}, "LOWER(name) = LOWER(?)", "Cash"))
+	t.Run("text contains", testCase(Comparison{name, Contains, "50%_off"}, `name LIKE ? ESCAPE '\'`, `%50\%\_off%`))
+	t.Run("text does not contain", testCase(Comparison{name, NotContains, "visa"}, `name NOT LIKE ? ESCAPE '\'`, "%visa%"))
+	t.Run("in period", testCase(Comparison{created, Equal, october}, "(created_at >= ? AND created_at < ?)", start, end))
+	t.Run("outside period", testCase(Comparison{created, NotEqual, october}, "(created_at < ? OR created_at >= ?)", start, end))
+	t.Run("after period", testCase(Comparison{created, Greater, october}, "created_at >= ?", end))
+	t.Run("from start of period", testCase(Comparison{created, GreaterOrEqual, october}, "created_at >= ?", start))
+	t.Run("before period", testCase(Comparison{created, Less, october}, "created_at < ?", start))
+	t.Run("up to end of period", testCase(Comparison{created, LessOrEqual, october}, "created_at < ?", end))
+}
+
+func TestExprSQL(t *testing.T) {
+	expr := And{
+		Left: Comparison{balance, Greater, int64(100)},
+		Right: Or{
+			Left:  Comparison{name, Contains, "food"},
+			Right: Not{Comparison{name, Equal, "cash"}},
+		},
+	}
+
+	sql, args := expr.SQL()
+	assert.Equal(t, `(balance > ? AND (name LIKE ? ESCAPE '\' OR NOT LOWER(name) = LOWER(?)))`, sql)
+	assert.Equal(t, []interface{}{int64(100), "%food%", "cash"}, args)
+}
+
+func TestQueryWithColumn(t *testing.T) {
+	q := Query{
+		Filter: And{
+			Left:  Comparison{balance, Greater, int64(100)},
+			Right: Not{Comparison{name, Equal, "cash"}},
+		},
+		Order: []OrderBy{{balance, true}, {name, false}},
+		Limit: 5,
+	}
+
+	replaced := q.WithColumn("balance", "old_balance")
+
+	sql, _ := replaced.Filter.SQL()
+	assert.Equal(t, `(old_balance > ? AND NOT LOWER(name) = LOWER(?))`, sql)
+	assert.Equal(t, "old_balance", replaced.Order[0].Field.Column)
+	assert.Equal(t, "name", replaced.Order[1].Field.Column)
+	assert.Equal(t, 5, replaced.Limit)
+
+	// the query it came from is unchanged
+	sql, _ = q.Filter.SQL()
+	assert.Equal(t, `(balance > ? AND NOT LOWER(name) = LOWER(?))`, sql)
+	assert.Equal(t, "balance", q.Order[0].Field.Column)
+}
+
+func TestOpsFor(t *testing.T) {
+	assert.True(t, Contains.IsValidFor(Text))
+	assert.False(t, Greater.IsValidFor(Text))
+	assert.True(t, Greater.IsValidFor(Money))
+	assert.False(t, Contains.IsValidFor(Date))
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields{balance, name}
+
+	field, ok := fields.Find("Balance")
+	assert.True(t, ok)
+	assert.Equal(t, balance, field)
+
+	_, ok = fields.Find("bogus")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"balance", "name"}, fields.Names())
+}
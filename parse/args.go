@@ -9,7 +9,13 @@ import (
 var IntegerPattern *regexp.Regexp = regexp.MustCompile(`[^\w|\d|\.|\,|'|"|_|-]?(-?\d+)(\W?[A-Z]{3})?`)
 var DecimalPattern *regexp.Regexp = regexp.MustCompile(`[^\w|\d|\.|\,|'|"|_|-]?(-?\d+(\.\d{1,2})?)(\W?[A-Z]{3})?`)
 
-var ItemNamePattern *regexp.Regexp = regexp.MustCompile(`[a-zA-Z][a-zA-Z_-]+`)
+// ItemNamePattern matches names of accounts and categories. Names start with a letter and may contain letters and
+// digits in any script, combining marks, spaces, and _ ' . & -
+var ItemNamePattern *regexp.Regexp = regexp.MustCompile(`^\pL[\pL\pN\pM _'.&-]*$`)
+var CategoryPathPattern *regexp.Regexp = regexp.MustCompile(`^\pL[\pL\pN\pM _'.&-]*(/\pL[\pL\pN\pM _'.&-]*)*$`)
+
+// DatePattern matches anything util.ParseDate might read, such as 2026-10-16, last-friday, -3d or q3
+var DatePattern *regexp.Regexp = regexp.MustCompile(`^[+-]?[\pL\pN][\pL\pN _-]*$`)
 
 var ArgumentTokenPattern *regexp.Regexp = regexp.MustCompile("[a-zA-Z0-9]+")
 
@@ -50,11 +56,17 @@ func parseOptionalArg(ctx *ParseContext, tok *TokenPattern, argPattern *regexp.R
 
 	if !hasNext {
 		// Missing flag token
-		return "", makeAutoSuggestion(false, "", []*TokenPattern{tok})
+		return "", makeMissingTokenSuggestion(tok.DisplayName, ctx.currentTokenIndex, []*TokenPattern{tok})
 	} else {
 
+		if option, value, ok := splitOption(nextToken); ok && value != "" && tok.Matches(option) {
+			// The option and its value are one token, e.g. -d=value
+			ctx.moveToNextToken()
+			return value, AutoSuggestion{true, "", []string{}, nil, ""}
+		}
+
 		if !tok.Matches(nextToken) {
-			return "", makeAutoSuggestion(false, nextToken, []*TokenPattern{tok})
+			return "", makeUnknownTokenSuggestion("argument", ctx.currentTokenIndex, nextToken, []*TokenPattern{tok}, []*TokenPattern{tok})
 		}
 
 		// Parse next token
@@ -64,10 +76,11 @@ func parseOptionalArg(ctx *ParseContext, tok *TokenPattern, argPattern *regexp.R
 
 		if !hasNext {
 			// Missing arg value
-			return "", AutoSuggestion{false, "", []string{fmt.Sprintf("<%s>", argPatternName)}}
+			placeholder := fmt.Sprintf("<%s>", argPatternName)
+			return "", AutoSuggestion{false, "", []string{placeholder}, &Diagnostic{TokenIndex: ctx.currentTokenIndex, Expected: []string{placeholder}, Message: fmt.Sprintf("missing value for %s", tok.DisplayName)}, ""}
 		}
 
 		ctx.moveToNextToken()
-		return nextToken, AutoSuggestion{true, "", []string{}}
+		return nextToken, AutoSuggestion{true, "", []string{}, nil, ""}
 	}
 }
@@ -1,9 +1,22 @@
 package parse
 
+import "fmt"
+
 type AutoSuggestion struct {
 	IsValidAsIs  bool
 	CurrentToken string
 	NextArgs     []string
+	Diagnostic   *Diagnostic // explains why the input is not valid, nil when there is nothing to explain
+	Preview      string      // what the value being typed evaluates to, e.g. "120/3 = 40.00". Empty when there is none
+}
+
+// Diagnostic points at the part of the input that stopped it from parsing.
+type Diagnostic struct {
+	TokenIndex int      // index of the offending token, or the number of tokens when something is missing at the end
+	Start      int      // byte offset in the input where the offending token starts
+	End        int      // byte offset in the input just past the offending token. Equal to Start when something is missing
+	Expected   []string // what could have been given instead
+	Message    string
 }
 
 func makeAutoSuggestion(isValidAsIs bool, currentToken string, nextTokens []*TokenPattern) AutoSuggestion {
@@ -16,7 +29,36 @@ func makeAutoSuggestion(isValidAsIs bool, currentToken string, nextTokens []*Tok
 		}
 	}
 
-	return AutoSuggestion{isValidAsIs, currentToken, nextArgs}
+	return AutoSuggestion{isValidAsIs, currentToken, nextArgs, nil, ""}
+}
+
+// makeUnknownTokenSuggestion is the suggestion for the token at index, which does not match any of the expected tokens.
+// kind names what was expected, such as "command" or "argument". The message offers the closest match, if there is one.
+func makeUnknownTokenSuggestion(kind string, index int, currentToken string, expected, possible []*TokenPattern) AutoSuggestion {
+	suggestion := makeAutoSuggestion(false, currentToken, possible)
+
+	message := fmt.Sprintf("unknown %s \"%s\"", kind, currentToken)
+	if len(possible) > 0 {
+		message += fmt.Sprintf(" — did you mean \"%s\"?", possible[0].ClosestLiteral(currentToken))
+	}
+	suggestion.Diagnostic = &Diagnostic{TokenIndex: index, Expected: DisplayNames(expected), Message: message}
+
+	return suggestion
+}
+
+// makeMissingTokenSuggestion is the suggestion for input that ends at index before one of the expected tokens was given
+func makeMissingTokenSuggestion(what string, index int, expected []*TokenPattern) AutoSuggestion {
+	suggestion := makeAutoSuggestion(false, "", expected)
+	suggestion.Diagnostic = &Diagnostic{TokenIndex: index, Expected: DisplayNames(expected), Message: "missing " + what}
+	return suggestion
+}
+
+// Problem describes why the command could not be parsed, including the diagnostic message when there is one
+func (suggestion AutoSuggestion) Problem(command string) string {
+	if suggestion.Diagnostic == nil {
+		return "invalid command: " + command
+	}
+	return fmt.Sprintf("invalid command: %s: %s", command, suggestion.Diagnostic.Message)
 }
 
-var EmptySuggestions AutoSuggestion = AutoSuggestion{true, "", []string{}}
+var EmptySuggestions AutoSuggestion = AutoSuggestion{true, "", []string{}, nil, ""}
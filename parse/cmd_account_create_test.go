@@ -79,6 +79,17 @@ func TestAccountCreateCommand_new_account(t *testing.T) {
 				assert.Nil(t, action)
 			}))
 
+	t.Run("new account name empty description",
+		testCase(`new account name -d="" -b=5`,
+			"",
+			true,
+			[]string{"--category"},
+			func(test *testing.T, action actions.Actioner) {
+				createAction := action.(actions_accounts.CreateAccountAction)
+				assert.Equal(t, "", createAction.Description)
+				assert.Equal(t, models.MakeMoney(5), createAction.StartingBalance)
+			}))
+
 	t.Run("new account name category description",
 		testCase("new account name --category \"Test Category\" -d='description'",
 			"",
@@ -110,4 +121,28 @@ func TestAccountCreateCommand_new_account(t *testing.T) {
 				assert.Equal(t, "description", createAccountAction.Description)
 				assert.Equal(t, models.MakeMoney(1.23), createAccountAction.StartingBalance)
 			}))
+
+	t.Run("new account unicode name quoted description",
+		testCase(`new account Müller-Konto --description="Bob's \"weekly\" groceries" -c='Café & Bar'`,
+			"",
+			true,
+			[]string{"--balance"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+
+				createAccountAction := action.(actions_accounts.CreateAccountAction)
+
+				assert.Equal(t, "Müller-Konto", createAccountAction.Name)
+				assert.Equal(t, "Café & Bar", createAccountAction.CategoryName)
+				assert.Equal(t, `Bob's "weekly" groceries`, createAccountAction.Description)
+			}))
+
+	t.Run("new account unterminated quote",
+		testCase(`new account cash -d="weekly`,
+			"",
+			false,
+			[]string{"--category", "--balance"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
 }
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"samvasta.com/bujit/actions"
@@ -47,15 +48,15 @@ func TestAccountListCommand(t *testing.T) {
 	t.Run("list account",
 		testCase("list account",
 			true,
-			[]string{"--name", "--description", "--category", "--max-balance", "--min-balance", "--help"},
+			[]string{"--name", "--description", "--category", "--max-balance", "--min-balance", "--on", "filter", "order", "limit", "--help"},
 			func(test *testing.T, action actions.Actioner) {
 				assert.NotNil(t, action)
 			}))
 
 	t.Run("fully specified",
-		testCase("list account -n name -d description --category='category' -m $123.45 -x 432.11",
+		testCase("list account -n name -d description --category='category' -m $123.45 -x 432.11 --on=2026-09",
 			true,
-			[]string{},
+			[]string{"filter", "order", "limit"},
 			func(test *testing.T, action actions.Actioner) {
 				assert.NotNil(t, action)
 
@@ -66,5 +67,6 @@ func TestAccountListCommand(t *testing.T) {
 				assert.Equal(t, "category", listAccountAction.CategoryName)
 				assert.Equal(t, models.MakeMoney(123.45), *listAccountAction.MinBalance)
 				assert.Equal(t, models.MakeMoney(432.11), *listAccountAction.MaxBalance)
+				assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), listAccountAction.On)
 			}))
 }
@@ -0,0 +1,22 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_categories "samvasta.com/bujit/actions/categories"
+	"samvasta.com/bujit/session"
+)
+
+var listCategoryCommand = &CommandSpec{
+	Verb:        LIST,
+	Noun:        CATEGORY,
+	Title:       "List Category Command",
+	Summary:     "Lists categories.",
+	Description: "Lists categories by their full name.",
+	Args: []ArgSpec{
+		Option(ARG_NAME, "n", "name", CategoryArg, "only show categories whose full name contains this value."),
+	},
+	Fields: actions_categories.ListCategoryFields,
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_categories.ListCategoryAction{Name: values.Text(ARG_NAME), Query: values.Query(actions_categories.ListCategoryFields), Session: session}
+	},
+}
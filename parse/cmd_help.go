@@ -20,7 +20,7 @@ func parseHelpRoot(context *HelpContext) (action actions.Actioner, suggestion Au
 		exact, possible := PossibleMatches(nextToken, parseRootNextTokens)
 
 		if exact == nil {
-			return nil, makeAutoSuggestion(false, nextToken, possible)
+			return nil, makeUnknownTokenSuggestion("argument", context.currentTokenIndex, nextToken, parseRootNextTokens, possible)
 		}
 
 		switch exact.Id {
@@ -29,21 +29,42 @@ func parseHelpRoot(context *HelpContext) (action actions.Actioner, suggestion Au
 			context.moveToNextToken()
 			return parseHelpRoot(context)
 		default:
-			return nil, makeAutoSuggestion(false, nextToken, possible)
+			return nil, makeUnknownTokenSuggestion("argument", context.currentTokenIndex, nextToken, parseRootNextTokens, possible)
 		}
-	} else if VerboseToken.Matches(nextToken) {
-		// return verbose help
-		return actions.HelpAction{HelpItems: []output.Helper{}}, EmptySuggestions
-	} else {
-		// return terse help
-		helpItems := output.EmptyOutputGroup().
-			Header("Bujit General Help").
-			HorizontalRule("═").
-			Paragraph("Available Commands").
-			UnorderedList(DisplayNames(ActionTokens), output.NormalBulletChar).
-			ToSlice()
-
-		return actions.HelpAction{HelpItems: helpItems}, EmptySuggestions
 	}
 
+	helpItems := append(GeneralHelpItems(Commands, context.verbose), MacroHelpItems(context.session)...)
+	return actions.HelpAction{HelpItems: helpItems}, EmptySuggestions
+}
+
+// GeneralHelpItems lists every command in the registry. Verbose help also includes the full help of each command.
+func GeneralHelpItems(registry *Registry, verbose bool) []output.Helper {
+	rows := [][]output.TableCell{}
+	for _, spec := range registry.Commands() {
+		rows = append(rows, []output.TableCell{output.Cell(spec.Name()), output.Cell(spec.Summary)})
+	}
+	rows = append(rows,
+		[]output.TableCell{output.Cell("alias <name> = <command>"), output.Cell("Defines a shorter name for a command. Anything typed after the name is added to the end.")},
+		[]output.TableCell{output.Cell("macro <name> $1 $2 = <command>"), output.Cell("Defines a command whose arguments $1, $2, ... are given after its name.")},
+		[]output.TableCell{output.Cell("help"), output.Cell("Shows this list. Add -v for the details of every command.")})
+
+	group := output.EmptyOutputGroup().
+		Header("Bujit General Help").
+		HorizontalRule("═").
+		Paragraph("Available Commands").
+		Table([]output.TableColumn{
+			output.MakeColumn("Command", output.TextColumn),
+			output.MakeColumn("Description", output.TextColumn),
+		}, rows).
+		Paragraph("Add --help to any command for its syntax and arguments.").
+		Paragraph("Separate commands with ; to run them one after the other. Follow a command with | sum, | count, | sort <field> [desc] or | export csv|json to work on what it listed, e.g. list account -c=food | sum")
+
+	helpers := group.ToSlice()
+	if verbose {
+		for _, spec := range registry.Commands() {
+			helpers = append(helpers, output.EmptyOutputGroup().EmptyLines(1).ToSlice()...)
+			helpers = append(helpers, spec.HelpItems()...)
+		}
+	}
+	return helpers
 }
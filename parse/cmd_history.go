@@ -0,0 +1,97 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+// rerunning keeps, for each session, the number of the history command that is being run again
+var rerunning = struct {
+	sync.Mutex
+	numbers map[*session.Session]int
+}{numbers: map[*session.Session]int{}}
+
+// HistoryAction lists the commands typed at the prompt, or runs one of them again by its number.
+type HistoryAction struct {
+	Number  int // the command to run again, counting from 1 for the oldest. 0 lists the history
+	Session *session.Session
+}
+
+// Command is the command that is run again, if there is one
+func (action HistoryAction) Command() (string, bool) {
+	if action.Number == 0 {
+		return "", false
+	}
+	return action.Session.History.Entry(action.Number)
+}
+
+func (action HistoryAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	if action.Number == 0 {
+		return actions.ActionResult{Output: historyHelpers(action.Session.History), IsSuccessful: true}, []*actions.Consequence{}
+	}
+
+	// A command from the history that runs another one, directly or through an alias, a chain or a pipe, could run
+	// itself forever
+	rerunning.Lock()
+	running := rerunning.numbers[action.Session]
+	rerunning.Unlock()
+	if running != 0 {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "Command %d runs another command from the history"}`, running), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	command, ok := action.Command()
+	if !ok {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "There is no command %d in the history"}`, action.Number), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	rerun, suggestion := ParseExpression(command, action.Session)
+	if rerun == nil {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "Command %d cannot be run: %s"}`, action.Number, suggestion.Problem(command)), IsSuccessful: false}, []*actions.Consequence{}
+	}
+
+	rerunning.Lock()
+	rerunning.numbers[action.Session] = action.Number
+	rerunning.Unlock()
+	defer func() {
+		rerunning.Lock()
+		delete(rerunning.numbers, action.Session)
+		rerunning.Unlock()
+	}()
+	return rerun.Execute()
+}
+
+func historyHelpers(history *session.History) []output.Helper {
+	if history == nil || len(history.Entries) == 0 {
+		return output.EmptyOutputGroup().Paragraph("The history is empty").ToSlice()
+	}
+
+	rows := make([][]output.TableCell, len(history.Entries))
+	for i, entry := range history.Entries {
+		rows[i] = []output.TableCell{output.Cell(strconv.Itoa(i + 1)), output.Cell(entry)}
+	}
+
+	columns := []output.TableColumn{
+		{Header: "#", Kind: output.TextColumn, Align: output.AlignRight},
+		output.MakeColumn("Command", output.TextColumn),
+	}
+	return output.EmptyOutputGroup().Table(columns, rows).ToSlice()
+}
+
+var historyCommand = &CommandSpec{
+	Verb:        HISTORY,
+	Noun:        NoNoun,
+	Title:       "History Command",
+	Summary:     "Lists the commands typed at the prompt, or runs one again.",
+	Description: "Lists the commands typed at the prompt, oldest first, with their numbers. Given a number, runs that command again. The history is saved next to the ledger, keeps each command once and holds the last 1000 commands. Press Ctrl-R at the prompt to search it.",
+	Args: []ArgSpec{
+		OptionalPositional(ARG_NUMBER, "n", IntegerArg, "number of the command to run again."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return HistoryAction{Number: values.Int(ARG_NUMBER), Session: session}
+	},
+}
@@ -0,0 +1,64 @@
+package parse
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+func TestHistoryCommand(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.History = &session.History{Entries: []string{"new account cash", "lsit account", "history 1"}}
+
+	action, suggestion := ParseExpression("history", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, HistoryAction{Session: &s}, action)
+	assert.Equal(t, []string{"<n>", "--help"}, suggestion.NextArgs)
+
+	result, _ := action.Execute()
+	assert.True(t, result.IsSuccessful)
+	table := result.Output.([]output.Helper)[0].(output.Table)
+	assert.Len(t, table.Rows, 3)
+	assert.Equal(t, "2", table.Rows[1][0].Text)
+
+	action, suggestion = ParseExpression("history 1", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	command, _ := action.(HistoryAction).Command()
+	assert.Equal(t, "new account cash", command)
+
+	result, consequences := action.Execute()
+	assert.True(t, result.IsSuccessful)
+	assert.Equal(t, actions.CREATE, consequences[0].ConsequenceType)
+
+	testCase := func(input string, message string) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, _ := ParseExpression(input, &s)
+			result, _ := action.Execute()
+			assert.False(t, result.IsSuccessful)
+			assert.Equal(t, message, result.Output)
+		}
+	}
+
+	t.Run("missing", testCase("history 4", `{"detail": "There is no command 4 in the history"}`))
+	t.Run("does not parse", testCase("history 2", "{\"detail\": \"Command 2 cannot be run: invalid command: lsit account: unknown command \"lsit\" — did you mean \"list\"?\"}"))
+	t.Run("history", testCase("history 3", `{"detail": "Command 3 runs another command from the history"}`))
+
+	define, _ := ParseExpression("alias again = history 1", &s)
+	define.Execute()
+	s.History.Entries = append(s.History.Entries, "list account; history 1", "again", "history 1 | count")
+
+	for _, input := range []string{"history 4", "history 5", "history 6"} {
+		action, _ := ParseExpression(input, &s)
+		if assert.NotNil(t, action, input) {
+			result, _ := action.Execute()
+			assert.False(t, result.IsSuccessful, input)
+			assert.Contains(t, fmt.Sprint(result.Output), "runs another command from the history", input)
+		}
+		assert.NotContains(t, rerunning.numbers, &s, input)
+	}
+}
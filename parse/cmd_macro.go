@@ -0,0 +1,133 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	actions_macros "samvasta.com/bujit/actions/macros"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+var listMacroCommand = &CommandSpec{
+	Verb:        LIST,
+	Noun:        MACROS,
+	Title:       "List Macro Command",
+	Summary:     "Lists aliases and macros.",
+	Description: "Lists every alias and macro with its definition. Define them with 'alias <name> = <command>' or 'macro <name> $1 $2 = <command>'.",
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_macros.ListMacroAction{Session: session}
+	},
+}
+
+var deleteMacroCommand = &CommandSpec{
+	Verb:        DELETE,
+	Noun:        MACROS,
+	Title:       "Delete Macro Command",
+	Summary:     "Deletes an alias or macro.",
+	Description: "Deletes an alias or macro by name.",
+	Args: []ArgSpec{
+		Positional(ARG_NAME, "name", MacroArg, "name of the alias or macro to delete."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_macros.DeleteMacroAction{Name: values.Text(ARG_NAME), Session: session}
+	},
+}
+
+var macroNameToken = MakeArgToken(ARG_NAME, "name", MacroNamePattern)
+var equalsToken = &TokenPattern{MACRO_CALL, "=", []*regexp.Regexp{regexp.MustCompile("=")}}
+
+func paramToken(n int) *TokenPattern {
+	return &TokenPattern{MACRO_CALL, fmt.Sprintf("$%d", n), []*regexp.Regexp{regexp.MustCompile(regexp.QuoteMeta(fmt.Sprintf("$%d", n)))}}
+}
+
+// parseMacroDefinition parses "alias <name> = <command>" or "macro <name> $1 ... $n = <command>". The command must
+// start with a built in command and may only use the parameters that were declared.
+func parseMacroDefinition(ctx *ParseContext, isAlias bool) (action actions.Actioner, suggestion AutoSuggestion) {
+	name, hasName := ctx.nextToken()
+	if !hasName {
+		return nil, makeMissingTokenSuggestion("name", ctx.currentTokenIndex, []*TokenPattern{macroNameToken})
+	}
+	if !MacroNamePattern.MatchString(name) {
+		return nil, AutoSuggestion{false, name, []string{}, &Diagnostic{TokenIndex: ctx.currentTokenIndex, Expected: []string{macroNameToken.DisplayName}, Message: fmt.Sprintf("invalid name \"%s\": names start with a letter and may contain letters, digits, _ and -", name)}, ""}
+	}
+	if exact, _ := PossibleMatches(name, append(append([]*TokenPattern{}, ActionTokens...), DefinitionTokens...)); exact != nil {
+		return nil, AutoSuggestion{false, name, []string{}, &Diagnostic{TokenIndex: ctx.currentTokenIndex, Expected: []string{macroNameToken.DisplayName}, Message: fmt.Sprintf("\"%s\" is already a command", name)}, ""}
+	}
+	ctx.moveToNextToken()
+
+	params := 0
+	for {
+		expected := []*TokenPattern{equalsToken}
+		if !isAlias {
+			expected = []*TokenPattern{paramToken(params + 1), equalsToken}
+		}
+
+		next, hasNext := ctx.nextToken()
+		if !hasNext {
+			return nil, makeMissingTokenSuggestion(strings.Join(DisplayNames(expected), " or "), ctx.currentTokenIndex, expected)
+		}
+
+		exact, possible := PossibleMatches(next, expected)
+		if exact == nil {
+			return nil, makeUnknownTokenSuggestion("argument", ctx.currentTokenIndex, next, expected, possible)
+		}
+		ctx.moveToNextToken()
+		if exact == equalsToken {
+			break
+		}
+		params++
+	}
+
+	start := ctx.currentTokenIndex
+	body := ctx.tokens[start:]
+	if len(body) == 0 {
+		return nil, makeMissingTokenSuggestion("command", start, ActionTokens)
+	}
+
+	exact, possible := PossibleMatches(body[0], ActionTokens)
+	if exact == nil {
+		return nil, makeUnknownTokenSuggestion("command", start, body[0], ActionTokens, possible)
+	}
+
+	for i, token := range body {
+		for _, ref := range paramPattern.FindAllStringSubmatch(token, -1) {
+			if n, _ := strconv.Atoi(ref[1]); n < 1 || n > params {
+				return nil, AutoSuggestion{false, token, []string{}, &Diagnostic{TokenIndex: start + i, Message: fmt.Sprintf("%s is not a parameter of %s", ref[0], name)}, ""}
+			}
+		}
+	}
+
+	// Parse the command for its suggestions. Anything it is missing may be given when the macro is used, and values
+	// that use parameters cannot be checked until then.
+	_, bodySuggestion := parseInputTokens(body, ctx.session)
+	if diagnostic := bodySuggestion.Diagnostic; diagnostic != nil && diagnostic.TokenIndex > 0 && diagnostic.TokenIndex < len(body) && !strings.Contains(body[diagnostic.TokenIndex], "$") {
+		diagnostic.TokenIndex += start
+		return nil, bodySuggestion
+	}
+
+	action = actions_macros.DefineMacroAction{Name: name, Params: params, Expansion: JoinTokens(body), Session: ctx.session}
+	return action, AutoSuggestion{true, bodySuggestion.CurrentToken, bodySuggestion.NextArgs, nil, bodySuggestion.Preview}
+}
+
+// MacroHelpItems lists the aliases and macros of the ledger, or nothing when there are none
+func MacroHelpItems(s *session.Session) []output.Helper {
+	rows := [][]output.TableCell{}
+	for _, macro := range loadMacros(s) {
+		rows = append(rows, []output.TableCell{output.Cell(macro.Name), output.Cell(macro.Definition())})
+	}
+	if len(rows) == 0 {
+		return []output.Helper{}
+	}
+
+	return output.EmptyOutputGroup().
+		Paragraph("Aliases and Macros").
+		Table([]output.TableColumn{
+			output.MakeColumn("Name", output.TextColumn),
+			output.MakeColumn("Definition", output.TextColumn),
+		}, rows).
+		ToSlice()
+}
@@ -0,0 +1,157 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_macros "samvasta.com/bujit/actions/macros"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+func TestMacroDefinition(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expected actions.Actioner, message string) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &s)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+			if isValid {
+				assert.Equal(t, expected, action)
+				assert.Nil(t, suggestion.Diagnostic)
+			} else {
+				assert.Nil(t, action)
+				assert.Equal(t, message, suggestion.Diagnostic.Message)
+			}
+		}
+	}
+
+	t.Run("alias", testCase("alias owing = list account -c=credit -x=0", true,
+		actions_macros.DefineMacroAction{Name: "owing", Expansion: "list account -c=credit -x=0", Session: &s}, ""))
+	t.Run("macro", testCase(`macro coffee $1 = new transaction $1 --from=cash --to=dining "--memo=morning coffee"`, true,
+		actions_macros.DefineMacroAction{Name: "coffee", Params: 1, Expansion: `new transaction $1 --from=cash --to=dining "--memo=morning coffee"`, Session: &s}, ""))
+	t.Run("missing name", testCase("alias", false, nil, "missing name"))
+	t.Run("missing equals", testCase("alias owing", false, nil, "missing ="))
+	t.Run("missing param or equals", testCase("macro coffee $1", false, nil, "missing $2 or ="))
+	t.Run("params out of order", testCase("macro coffee $2 = new transaction $2", false, nil, `unknown argument "$2" — did you mean "$1"?`))
+	t.Run("missing command", testCase("alias owing =", false, nil, "missing command"))
+	t.Run("unknown command", testCase("alias owing = lst account", false, nil, `unknown command "lst" — did you mean "list"?`))
+	t.Run("undeclared param", testCase("macro coffee $1 = new transaction $1 --memo=$2", false, nil, "$2 is not a parameter of coffee"))
+	t.Run("name is a command", testCase("alias list = list account", false, nil, `"list" is already a command`))
+	t.Run("invalid name", testCase("alias 2much = list account", false, nil, `invalid name "2much": names start with a letter and may contain letters, digits, _ and -`))
+	t.Run("unknown argument in command", testCase("alias owing = list account --bogus", false, nil, `unknown argument "--bogus" — did you mean "--name"?`))
+
+	_, suggestion := ParseExpression("alias owing = list acc", &s)
+	assert.Equal(t, "account", suggestion.NextArgs[0])
+	assert.Equal(t, 19, suggestion.Diagnostic.Start)
+}
+
+func TestMacroExpansion(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.Db.Create(&models.Macro{Name: "owing", Expansion: "list account -c=credit -x=0"})
+	s.Db.Create(&models.Macro{Name: "coffee", Params: 1, Expansion: `new transaction $1 --from=cash --to=dining "--memo=morning coffee"`})
+
+	action, suggestion := ParseExpression("owing", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	listAction := action.(actions_accounts.ListAccountAction)
+	assert.Equal(t, "credit", listAction.CategoryName)
+	assert.Equal(t, models.MakeMoney(0), *listAction.MaxBalance)
+
+	// anything after an alias is added to the end
+	action, _ = ParseExpression("OWING -n=visa", &s)
+	assert.Equal(t, "visa", action.(actions_accounts.ListAccountAction).Name)
+
+	action, suggestion = ParseExpression("coffee 4.50", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, actions_transactions.CreateTransactionAction{
+		Amount:          models.MakeMoney(4.5),
+		SourceName:      "cash",
+		DestinationName: "dining",
+		Memo:            "morning coffee",
+		Session:         &s,
+	}, action)
+
+	action, suggestion = ParseExpression("coffee", &s)
+	assert.Nil(t, action)
+	assert.Equal(t, []string{"<$1>"}, suggestion.NextArgs)
+	assert.Equal(t, `missing value for $1 of coffee, which is macro coffee $1 = new transaction $1 --from=cash --to=dining "--memo=morning coffee"`, suggestion.Diagnostic.Message)
+	assert.Equal(t, 6, suggestion.Diagnostic.Start)
+
+	// problems with an argument point at the argument, problems in the expansion point at the name
+	_, suggestion = ParseExpression("coffee 1/0", &s)
+	assert.Equal(t, 7, suggestion.Diagnostic.Start)
+	assert.Equal(t, 10, suggestion.Diagnostic.End)
+
+	_, suggestion = ParseExpression("owing --bogus", &s)
+	assert.Equal(t, 6, suggestion.Diagnostic.Start)
+
+	s.Db.Create(&models.Macro{Name: "broken", Expansion: "list account --bogus"})
+	_, suggestion = ParseExpression("broken", &s)
+	assert.Equal(t, 0, suggestion.Diagnostic.Start)
+	assert.Equal(t, 6, suggestion.Diagnostic.End)
+}
+
+func TestMacroSuggestions(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.Db.Create(&models.Macro{Name: "owing", Expansion: "list account -c=credit -x=0"})
+	s.Db.Create(&models.Macro{Name: "coffee", Params: 1, Expansion: "new transaction $1 --from=cash"})
+
+	_, suggestion := ParseExpression("ow", &s)
+	assert.Equal(t, []string{"owing"}, suggestion.NextArgs)
+
+	assert.Equal(t, []string{"coffee"}, Complete("co", 2, &s).Completions)
+
+	_, suggestion = ParseExpression("delete macro ", &s)
+	assert.Contains(t, suggestion.NextArgs, "coffee")
+
+	action, _ := ParseExpression("delete macro owing", &s)
+	assert.Equal(t, actions_macros.DeleteMacroAction{Name: "owing", Session: &s}, action)
+
+	action, _ = ParseExpression("list alias", &s)
+	assert.Equal(t, actions_macros.ListMacroAction{Session: &s}, action)
+}
+
+func TestMacroHelp(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	assert.Empty(t, MacroHelpItems(&s))
+
+	// defining a macro makes the parser read them again
+	actions_macros.DefineMacroAction{Name: "owing", Expansion: "list account -c=credit -x=0", Session: &s}.Execute()
+
+	action, _ := ParseExpression("help", &s)
+	result, _ := action.Execute()
+	helpers := result.Output.([]output.Helper)
+	table := helpers[len(helpers)-1].(output.Table)
+	assert.Equal(t, []output.TableCell{output.Cell("owing"), output.Cell("alias owing = list account -c=credit -x=0")}, table.Rows[0])
+}
+
+func TestMacroCache(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	actions_macros.DefineMacroAction{Name: "owing", Expansion: "list account -c=credit -x=0", Session: &s}.Execute()
+
+	assert.Len(t, loadMacros(&s), 1)
+
+	// the macros are not read again for each command. Raw SQL does not tell the parser.
+	s.Db.Exec("INSERT INTO macros (name, params, expansion) VALUES ('coffee', 1, 'new transaction $1 --from=cash')")
+	assert.Len(t, loadMacros(&s), 1)
+
+	actions_macros.DeleteMacroAction{Name: "owing", Session: &s}.Execute()
+	macros := loadMacros(&s)
+	if assert.Len(t, macros, 1) {
+		assert.Equal(t, "coffee", macros[0].Name)
+	}
+
+	// any other write to the macros table is noticed too
+	s.Db.Create(&models.Macro{Name: "rent", Expansion: "new transaction 1200 --from=checking"})
+	assert.Len(t, loadMacros(&s), 2)
+
+	// sessions are cached apart
+	other := session.InMemorySession(models.MigrateSchema)
+	assert.Empty(t, loadMacros(&other))
+	assert.Len(t, loadMacros(&s), 2)
+}
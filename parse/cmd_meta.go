@@ -0,0 +1,28 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/session"
+)
+
+var versionCommand = &CommandSpec{
+	Verb:        VERSION,
+	Noun:        NoNoun,
+	Title:       "Version Command",
+	Summary:     "Shows the version of bujit.",
+	Description: "Shows the version of bujit.",
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions.VersionAction{}
+	},
+}
+
+var exitCommand = &CommandSpec{
+	Verb:        EXIT,
+	Noun:        NoNoun,
+	Title:       "Exit Command",
+	Summary:     "Leaves the interactive prompt.",
+	Description: "Leaves the interactive prompt.",
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions.ExitAction{}
+	},
+}
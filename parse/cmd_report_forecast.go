@@ -0,0 +1,38 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_reports "samvasta.com/bujit/actions/reports"
+	"samvasta.com/bujit/session"
+)
+
+const defaultForecastDays = 30
+
+var forecastCommand = &CommandSpec{
+	Verb:        REPORT,
+	Noun:        FORECAST,
+	Title:       "Forecast Report Command",
+	Summary:     "Projects the balance of an account into the future.",
+	Description: "Projects the daily balance of an account forward from its current balance using scheduled transactions and, optionally, the average activity of the last few months. Shows the first day the balance would drop below the threshold.",
+	Args: []ArgSpec{
+		RequiredOption(ARG_ACCOUNT, "a", "account", AccountArg, "name of the account to forecast."),
+		Option(ARG_DAYS, "n", "days", IntegerArg, "number of days to project forward. Defaults to 30."),
+		Option(ARG_MONTHS, "m", "months", IntegerArg, "number of months of past transactions used to estimate average daily activity. Defaults to 0, which only uses scheduled transactions."),
+		Option(ARG_THRESHOLD, "t", "threshold", MoneyArg, "flag the first day the balance drops below this value. Defaults to 0."),
+		Option(ARG_START, "s", "start", DateArg, "the day the forecast starts from, such as tomorrow or 2026-11. Defaults to today."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		action := actions_reports.ForecastAction{
+			AccountName:   values.Text(ARG_ACCOUNT),
+			Days:          defaultForecastDays,
+			AverageMonths: values.Int(ARG_MONTHS),
+			Threshold:     values.Money(ARG_THRESHOLD),
+			Today:         values.Date(ARG_START).Start,
+			Session:       session,
+		}
+		if values.Has(ARG_DAYS) {
+			action.Days = values.Int(ARG_DAYS)
+		}
+		return action
+	},
+}
@@ -0,0 +1,74 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_reports "samvasta.com/bujit/actions/reports"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestReportForecastCommand(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expectedSuggestions []string, additionalCheck func(t *testing.T, action actions.Actioner)) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+
+			for _, expectedSuggestion := range expectedSuggestions {
+				assert.Contains(t, suggestion.NextArgs, expectedSuggestion)
+			}
+			assert.Len(t, suggestion.NextArgs, len(expectedSuggestions))
+
+			additionalCheck(t, action)
+		}
+	}
+
+	t.Run("report",
+		testCase("report",
+			false,
+			[]string{"forecast", "trend"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("report forecast",
+		testCase("report forecast",
+			false,
+			[]string{"--account", "--days", "--months", "--threshold", "--start", "--help"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("account only",
+		testCase("report forecast --account=checking",
+			true,
+			[]string{"--days", "--months", "--threshold", "--start"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+				forecastAction := action.(actions_reports.ForecastAction)
+				assert.Equal(t, "checking", forecastAction.AccountName)
+				assert.Equal(t, 30, forecastAction.Days)
+				assert.Equal(t, 0, forecastAction.AverageMonths)
+				assert.Equal(t, models.MakeMoney(0), forecastAction.Threshold)
+			}))
+
+	t.Run("fully specified",
+		testCase("report forecast --days=90 --account=checking -m 3 -t $250.50 -s=2026-11",
+			true,
+			[]string{},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+				forecastAction := action.(actions_reports.ForecastAction)
+				assert.Equal(t, "checking", forecastAction.AccountName)
+				assert.Equal(t, 90, forecastAction.Days)
+				assert.Equal(t, 3, forecastAction.AverageMonths)
+				assert.Equal(t, models.MakeMoney(250.50), forecastAction.Threshold)
+				assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), forecastAction.Today)
+			}))
+}
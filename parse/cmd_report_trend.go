@@ -0,0 +1,38 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_reports "samvasta.com/bujit/actions/reports"
+	"samvasta.com/bujit/session"
+)
+
+const defaultTrendDays = 30
+
+var trendCommand = &CommandSpec{
+	Verb:        REPORT,
+	Noun:        TREND,
+	Title:       "Trend Report Command",
+	Summary:     "Charts how the balance of an account or category changed.",
+	Description: "Charts the balance of an account, or the total balance of the accounts in a category and its sub categories, at the end of each of the last few days. Balances go by the dates transactions took effect. An account also shows its change each week, and a category shows the balance of each of its accounts.",
+	Args: []ArgSpec{
+		Option(ARG_ACCOUNT, "a", "account", AccountArg, "name of the account to chart. Give either an account or a category."),
+		Option(ARG_CATEGORY, "c", "category", CategoryArg, "name or path of the category to chart."),
+		Option(ARG_DAYS, "n", "days", IntegerArg, "number of days to look back over, including today. Defaults to 30."),
+		Option(ARG_UNTIL, "u", "until", DateArg, "the last day of the chart, such as yesterday or 2026-09. Defaults to today."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		action := actions_reports.TrendAction{
+			AccountName:  values.Text(ARG_ACCOUNT),
+			CategoryName: values.Text(ARG_CATEGORY),
+			Days:         defaultTrendDays,
+			Session:      session,
+		}
+		if values.Has(ARG_DAYS) {
+			action.Days = values.Int(ARG_DAYS)
+		}
+		if values.Has(ARG_UNTIL) {
+			action.Today = values.Date(ARG_UNTIL).End.AddDate(0, 0, -1)
+		}
+		return action
+	},
+}
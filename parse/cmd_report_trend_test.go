@@ -0,0 +1,65 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_reports "samvasta.com/bujit/actions/reports"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestReportTrendCommand(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expectedSuggestions []string, additionalCheck func(t *testing.T, action actions.Actioner)) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+
+			for _, expectedSuggestion := range expectedSuggestions {
+				assert.Contains(t, suggestion.NextArgs, expectedSuggestion)
+			}
+			assert.Len(t, suggestion.NextArgs, len(expectedSuggestions))
+
+			additionalCheck(t, action)
+		}
+	}
+
+	t.Run("report trend",
+		testCase("report trend",
+			false,
+			[]string{"--account", "--category", "--days", "--until", "--help"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("account only",
+		testCase("report trend --account=checking",
+			true,
+			[]string{"--category", "--days", "--until"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+				trendAction := action.(actions_reports.TrendAction)
+				assert.Equal(t, "checking", trendAction.AccountName)
+				assert.Equal(t, "", trendAction.CategoryName)
+				assert.Equal(t, 30, trendAction.Days)
+				assert.True(t, trendAction.Today.IsZero())
+				assert.True(t, trendAction.IsValid())
+			}))
+
+	t.Run("fully specified",
+		testCase("report trend -c food -n=14 --until=2026-09",
+			true,
+			[]string{"--account"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+				trendAction := action.(actions_reports.TrendAction)
+				assert.Equal(t, "food", trendAction.CategoryName)
+				assert.Equal(t, 14, trendAction.Days)
+				assert.Equal(t, time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC), trendAction.Today)
+			}))
+}
@@ -0,0 +1,34 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/session"
+)
+
+var newScheduledCommand = &CommandSpec{
+	Verb:        NEW,
+	Noun:        SCHEDULED,
+	Title:       "Create New Scheduled Transaction Command",
+	Summary:     "Schedules a transaction that is expected to happen.",
+	Description: "Schedules money to move out of one account and into another on a future day, once or repeating. Scheduled transactions do not change any balance, but the forecast report counts them.",
+	Args: []ArgSpec{
+		Positional(ARG_AMOUNT, "amount", MoneyArg, "how much money moves. Must be more than 0."),
+		Option(ARG_FROM, "f", "from", AccountArg, "the account the money leaves."),
+		Option(ARG_TO, "t", "to", AccountArg, "the account the money enters."),
+		Option(ARG_MEMO, "m", "memo", TextArg, "a note about the transaction."),
+		Option(ARG_DATE, "d", "date", DateArg, "the day it next happens, such as 2026-11-01, tomorrow or +7d. Defaults to today."),
+		Option(ARG_EVERY, "e", "every", IntegerArg, "number of days between occurrences. Leave out for a transaction that only happens once."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_transactions.CreateScheduledTransactionAction{
+			Amount:          values.Money(ARG_AMOUNT),
+			SourceName:      values.Text(ARG_FROM),
+			DestinationName: values.Text(ARG_TO),
+			Memo:            values.Text(ARG_MEMO),
+			NextDate:        values.Date(ARG_DATE).Start,
+			IntervalDays:    values.Int(ARG_EVERY),
+			Session:         session,
+		}
+	},
+}
@@ -0,0 +1,28 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/session"
+)
+
+var listScheduledCommand = &CommandSpec{
+	Verb:        LIST,
+	Noun:        SCHEDULED,
+	Title:       "List Scheduled Transaction Command",
+	Summary:     "Lists scheduled transactions, soonest first.",
+	Description: "Lists scheduled transactions by the day they next happen, soonest first. One that only happens once is left out after its day has passed.",
+	Args: []ArgSpec{
+		Option(ARG_ACCOUNT, "a", "account", AccountArg, "only show scheduled transactions into or out of this account."),
+		Option(ARG_LIMIT, "l", "limit", IntegerArg, "show at most this many scheduled transactions."),
+	},
+	Fields: actions_transactions.ListScheduledTransactionFields,
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_transactions.ListScheduledTransactionAction{
+			AccountName: values.Text(ARG_ACCOUNT),
+			Limit:       values.Int(ARG_LIMIT),
+			Query:       values.Query(actions_transactions.ListScheduledTransactionFields),
+			Session:     session,
+		}
+	},
+}
@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/session"
+)
+
+var PathPattern *regexp.Regexp = regexp.MustCompile(`^[^\s-][^\s]*$`)
+
+// sourcing keeps the absolute paths of the scripts that source is running for each session, the innermost last
+var sourcing = struct {
+	sync.Mutex
+	paths map[*session.Session][]string
+}{paths: map[*session.Session][]string{}}
+
+// SourceAction runs every command in a script file.
+type SourceAction struct {
+	Path            string
+	ContinueOnError bool
+	Session         *session.Session
+}
+
+func (action SourceAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	file, err := os.Open(action.Path)
+	if err != nil {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "Cannot open script '%s'"}`, action.Path), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	defer file.Close()
+
+	// A script that sources itself, directly or through other scripts, would never finish
+	if !startSourcing(action.Session, scriptPath(action.Path)) {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "Cannot source '%s' while it is running: recursive source"}`, action.Path), IsSuccessful: false}, []*actions.Consequence{}
+	}
+	defer finishSourcing(action.Session)
+
+	result, err := ScriptExecutor{Session: action.Session, ContinueOnError: action.ContinueOnError}.Execute(file)
+	if err != nil {
+		return actions.ActionResult{Output: fmt.Sprintf(`{"detail": "Cannot read script '%s'"}`, action.Path), IsSuccessful: false}, result.Consequences()
+	}
+
+	return actions.ActionResult{Output: result.Summary(), IsSuccessful: result.IsSuccessful()}, result.Consequences()
+}
+
+// startSourcing records that the session runs the script. It is false when the script is already running.
+func startSourcing(s *session.Session, path string) bool {
+	sourcing.Lock()
+	defer sourcing.Unlock()
+	for _, running := range sourcing.paths[s] {
+		if running == path {
+			return false
+		}
+	}
+	sourcing.paths[s] = append(sourcing.paths[s], path)
+	return true
+}
+
+// finishSourcing records that the innermost script the session runs has finished
+func finishSourcing(s *session.Session) {
+	sourcing.Lock()
+	defer sourcing.Unlock()
+	if paths := sourcing.paths[s]; len(paths) > 1 {
+		sourcing.paths[s] = paths[:len(paths)-1]
+	} else {
+		delete(sourcing.paths, s)
+	}
+}
+
+// scriptPath is the absolute path of a script with any symbolic links followed, so each script has one path
+func scriptPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
+
+var sourceCommand = &CommandSpec{
+	Verb:        SOURCE,
+	Noun:        NoNoun,
+	Title:       "Source Command",
+	Summary:     "Runs every command in a script file.",
+	Description: "Runs every command in a script file, one command per line. Blank lines and anything after a # are ignored. Stops at the first command that fails unless --continue-on-error is given.",
+	Args: []ArgSpec{
+		Positional(ARG_PATH, "file", PathArg, "path to the script."),
+		Flag(FLAG_CONTINUE_ON_ERROR, "k", "continue-on-error", "keep running the remaining commands after one fails."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return SourceAction{Path: values[ARG_PATH], ContinueOnError: values.Has(FLAG_CONTINUE_ON_ERROR), Session: session}
+	},
+}
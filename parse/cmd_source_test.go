@@ -0,0 +1,124 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestSourceCommand(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expectedSuggestions []string, additionalCheck func(t *testing.T, action actions.Actioner)) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+
+			for _, expectedSuggestion := range expectedSuggestions {
+				assert.Contains(t, suggestion.NextArgs, expectedSuggestion)
+			}
+			assert.Len(t, suggestion.NextArgs, len(expectedSuggestions))
+
+			additionalCheck(t, action)
+		}
+	}
+
+	t.Run("source",
+		testCase("source",
+			false,
+			[]string{"<file>", "--help"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("file only",
+		testCase("source setup.bujit",
+			true,
+			[]string{"--continue-on-error"},
+			func(test *testing.T, action actions.Actioner) {
+				sourceAction := action.(SourceAction)
+				assert.Equal(t, "setup.bujit", sourceAction.Path)
+				assert.False(t, sourceAction.ContinueOnError)
+			}))
+
+	t.Run("continue on error",
+		testCase("source -k scripts/monthly.bujit",
+			true,
+			[]string{},
+			func(test *testing.T, action actions.Actioner) {
+				sourceAction := action.(SourceAction)
+				assert.Equal(t, "scripts/monthly.bujit", sourceAction.Path)
+				assert.True(t, sourceAction.ContinueOnError)
+			}))
+
+	t.Run("flag after file",
+		testCase("source scripts/monthly.bujit -k",
+			true,
+			[]string{},
+			func(test *testing.T, action actions.Actioner) {
+				sourceAction := action.(SourceAction)
+				assert.Equal(t, "scripts/monthly.bujit", sourceAction.Path)
+				assert.True(t, sourceAction.ContinueOnError)
+			}))
+
+	t.Run("flag without file",
+		testCase("source --continue-on-error",
+			false,
+			[]string{"<file>"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+}
+
+func TestSourceAction(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	path := filepath.Join(t.TempDir(), "setup.bujit")
+	os.WriteFile(path, []byte("new account cash\nnew account savings\n"), 0600)
+
+	result, consequences := SourceAction{Path: path, Session: &s}.Execute()
+
+	assert.True(t, result.IsSuccessful)
+	assert.Len(t, consequences, 2)
+
+	result, consequences = SourceAction{Path: filepath.Join(t.TempDir(), "missing.bujit"), Session: &s}.Execute()
+
+	assert.False(t, result.IsSuccessful)
+	assert.Contains(t, result.Output, "Cannot open script")
+	assert.Len(t, consequences, 0)
+}
+
+func TestSourceActionRecursive(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.bujit"), filepath.Join(dir, "second.bujit")
+	os.WriteFile(first, []byte("new account cash\nsource "+second+"\n"), 0600)
+	os.WriteFile(second, []byte("source "+first+"\n"), 0600)
+
+	result, consequences := SourceAction{Path: first, Session: &s}.Execute()
+
+	assert.False(t, result.IsSuccessful)
+	assert.Len(t, consequences, 1)
+	assert.Empty(t, sourcing.paths[&s])
+
+	result, _ = SourceAction{Path: second, Session: &s}.Execute()
+	assert.False(t, result.IsSuccessful)
+
+	startSourcing(&s, scriptPath(first))
+	result, _ = SourceAction{Path: first, Session: &s}.Execute()
+	assert.Contains(t, result.Output, "recursive source")
+	finishSourcing(&s)
+
+	// the same script may run again once it has finished
+	os.WriteFile(second, []byte("list account\n"), 0600)
+	os.WriteFile(first, []byte("source "+second+"\nsource "+second+"\n"), 0600)
+	result, _ = SourceAction{Path: first, Session: &s}.Execute()
+	assert.True(t, result.IsSuccessful)
+}
@@ -0,0 +1,32 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/session"
+)
+
+var newTransactionCommand = &CommandSpec{
+	Verb:        NEW,
+	Noun:        TRANSACTION,
+	Title:       "Create New Transaction Command",
+	Summary:     "Moves money between accounts.",
+	Description: "Moves money out of one account and into another. Leave out the source for income, or the destination for spending.",
+	Args: []ArgSpec{
+		Positional(ARG_AMOUNT, "amount", MoneyArg, "how much money moves. Must be more than 0."),
+		Option(ARG_FROM, "f", "from", AccountArg, "the account the money leaves."),
+		Option(ARG_TO, "t", "to", AccountArg, "the account the money enters."),
+		Option(ARG_MEMO, "m", "memo", TextArg, "a note about the transaction."),
+		Option(ARG_DATE, "d", "date", DateArg, "the day the transaction happened, such as 2026-10-16, yesterday, last-friday or -3d. Defaults to today."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_transactions.CreateTransactionAction{
+			Amount:          values.Money(ARG_AMOUNT),
+			SourceName:      values.Text(ARG_FROM),
+			DestinationName: values.Text(ARG_TO),
+			Memo:            values.Text(ARG_MEMO),
+			EffectiveAt:     values.Date(ARG_DATE).Start,
+			Session:         session,
+		}
+	},
+}
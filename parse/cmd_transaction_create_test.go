@@ -0,0 +1,121 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+func TestNewTransactionCommand(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expectedSuggestions []string, additionalCheck func(t *testing.T, action actions.Actioner)) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+
+			for _, expectedSuggestion := range expectedSuggestions {
+				assert.Contains(t, suggestion.NextArgs, expectedSuggestion)
+			}
+			assert.Len(t, suggestion.NextArgs, len(expectedSuggestions))
+
+			additionalCheck(t, action)
+		}
+	}
+
+	t.Run("new transaction",
+		testCase("new transaction",
+			false,
+			[]string{"<amount>", "--help"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("amount without accounts",
+		testCase("new transaction 12.50",
+			false,
+			[]string{"--from", "--to", "--memo", "--date"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("fully specified",
+		testCase(`new tran 12.50 --from=checking -t=savings -m="rainy day" -d=2026-10-16`,
+			true,
+			[]string{},
+			func(test *testing.T, action actions.Actioner) {
+				transactionAction := action.(actions_transactions.CreateTransactionAction)
+				assert.Equal(t, models.MakeMoney(12.5), transactionAction.Amount)
+				assert.Equal(t, "checking", transactionAction.SourceName)
+				assert.Equal(t, "savings", transactionAction.DestinationName)
+				assert.Equal(t, "rainy day", transactionAction.Memo)
+				assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), transactionAction.EffectiveAt)
+			}))
+
+	t.Run("list transaction",
+		testCase("list transaction -a=checking -l=5 -s=2026-q3 --until=2026-10",
+			true,
+			[]string{"filter", "order", "limit"},
+			func(test *testing.T, action actions.Actioner) {
+				listAction := action.(actions_transactions.ListTransactionAction)
+				assert.Equal(t, "checking", listAction.AccountName)
+				assert.Equal(t, 5, listAction.Limit)
+				assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), listAction.Since)
+				assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), listAction.Until)
+			}))
+
+	t.Run("relative date",
+		testCase("new transaction 3 -f=checking -d=-3d",
+			true,
+			[]string{"--to", "--memo"},
+			func(test *testing.T, action actions.Actioner) {
+				transactionAction := action.(actions_transactions.CreateTransactionAction)
+				assert.Equal(t, util.Today().AddDate(0, 0, -3), transactionAction.EffectiveAt)
+			}))
+
+	t.Run("unknown date",
+		testCase("new transaction 3 -f=checking -d=someday",
+			false,
+			[]string{},
+			func(test *testing.T, action actions.Actioner) {
+				assert.Nil(t, action)
+			}))
+
+	t.Run("new scheduled",
+		testCase(`new sched 1200 --from=checking -m=rent -d=2026-11-01 --every=30`,
+			true,
+			[]string{"--to"},
+			func(test *testing.T, action actions.Actioner) {
+				scheduledAction := action.(actions_transactions.CreateScheduledTransactionAction)
+				assert.Equal(t, models.MakeMoney(1200), scheduledAction.Amount)
+				assert.Equal(t, "checking", scheduledAction.SourceName)
+				assert.Equal(t, "rent", scheduledAction.Memo)
+				assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), scheduledAction.NextDate)
+				assert.Equal(t, 30, scheduledAction.IntervalDays)
+			}))
+
+	t.Run("list scheduled",
+		testCase("list scheduled -a=checking -l=5",
+			true,
+			[]string{"filter", "order", "limit"},
+			func(test *testing.T, action actions.Actioner) {
+				listAction := action.(actions_transactions.ListScheduledTransactionAction)
+				assert.Equal(t, "checking", listAction.AccountName)
+				assert.Equal(t, 5, listAction.Limit)
+			}))
+
+	t.Run("list category",
+		testCase("list category",
+			true,
+			[]string{"--name", "filter", "order", "limit", "--help"},
+			func(test *testing.T, action actions.Actioner) {
+				assert.NotNil(t, action)
+			}))
+}
@@ -0,0 +1,32 @@
+package parse
+
+import (
+	"samvasta.com/bujit/actions"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/session"
+)
+
+var listTransactionCommand = &CommandSpec{
+	Verb:        LIST,
+	Noun:        TRANSACTION,
+	Title:       "List Transaction Command",
+	Summary:     "Lists transactions, newest first.",
+	Description: "Lists transactions, newest first.",
+	Args: []ArgSpec{
+		Option(ARG_ACCOUNT, "a", "account", AccountArg, "only show transactions into or out of this account."),
+		Option(ARG_LIMIT, "l", "limit", IntegerArg, "show at most this many transactions."),
+		Option(ARG_SINCE, "s", "since", DateArg, "only show transactions on or after this day, or from the start of this period, such as 2026-10, q3 or -7d."),
+		Option(ARG_UNTIL, "u", "until", DateArg, "only show transactions on or before this day, or up to the end of this period, such as yesterday or 2026-10."),
+	},
+	Fields: actions_transactions.ListTransactionFields,
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return actions_transactions.ListTransactionAction{
+			AccountName: values.Text(ARG_ACCOUNT),
+			Limit:       values.Int(ARG_LIMIT),
+			Since:       values.Date(ARG_SINCE).Start,
+			Until:       values.Date(ARG_UNTIL).End,
+			Query:       values.Query(actions_transactions.ListTransactionFields),
+			Session:     session,
+		}
+	},
+}
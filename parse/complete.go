@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"strings"
+	"unicode"
+
+	"samvasta.com/bujit/session"
+)
+
+// Completion describes what could be typed at the cursor position of a partial command.
+type Completion struct {
+	Line         string            `json:"line"`
+	Cursor       int               `json:"cursor"` // in characters from the start of the line
+	Start        int               `json:"start"`  // in characters, where the word being completed starts
+	Word         string            `json:"word"`   // text between Start and Cursor
+	IsValidAsIs  bool              `json:"isValidAsIs"`
+	CurrentToken string            `json:"currentToken"`
+	NextArgs     []string          `json:"nextArgs"`
+	Completions  []string          `json:"completions"`       // literal tokens that can replace Word
+	Placeholders []string          `json:"placeholders"`      // descriptions of values the user has to supply, such as <name>
+	Descriptions map[string]string `json:"descriptions"`      // one line of help for the completions that are arguments, by completion
+	Preview      string            `json:"preview,omitempty"` // what the value before the cursor evaluates to, such as an amount
+}
+
+// IsPlaceholder is true for suggestions that describe a value rather than being literal text, such as <name>.
+func IsPlaceholder(suggestion string) bool {
+	return strings.HasPrefix(suggestion, "<")
+}
+
+// Complete finds the suggestions for the word under the cursor, ignoring anything after the cursor.
+func Complete(line string, cursor int, session *session.Session) Completion {
+	runes := []rune(line)
+	if cursor < 0 || cursor > len(runes) {
+		cursor = len(runes)
+	}
+
+	start := cursor
+	for start > 0 && !unicode.IsSpace(runes[start-1]) && !strings.ContainsRune("=;|", runes[start-1]) {
+		start--
+	}
+
+	beforeCursor := string(runes[:cursor])
+	beforeWord := string(runes[:start])
+	word := string(runes[start:cursor])
+
+	_, whole := ParseExpression(beforeCursor, session)
+	_, next := ParseExpression(beforeWord, session)
+
+	completion := Completion{
+		Line:         line,
+		Cursor:       cursor,
+		Start:        start,
+		Word:         word,
+		IsValidAsIs:  whole.IsValidAsIs,
+		CurrentToken: whole.CurrentToken,
+		NextArgs:     nonNil(whole.NextArgs),
+		Completions:  []string{},
+		Placeholders: []string{},
+		Descriptions: map[string]string{},
+		Preview:      whole.Preview,
+	}
+
+	for _, arg := range next.NextArgs {
+		if IsPlaceholder(arg) {
+			completion.Placeholders = append(completion.Placeholders, arg)
+		} else if strings.HasPrefix(strings.ToLower(arg), strings.ToLower(word)) {
+			completion.Completions = append(completion.Completions, arg)
+		}
+	}
+
+	if spec := commandBefore(beforeWord); spec != nil {
+		for _, arg := range spec.Args {
+			if arg.Help != "" && !arg.IsPositional && contains(completion.Completions, arg.token.DisplayName) {
+				completion.Descriptions[arg.token.DisplayName] = arg.Help
+			}
+		}
+	}
+
+	return completion
+}
+
+// commandBefore is the command that the last command of a partial line starts, or nil when it is not known yet or the
+// line ends in a pipe stage
+func commandBefore(line string) *CommandSpec {
+	segments := splitCommandLine(line)
+	last := segments[len(segments)-1]
+	if last.isStage {
+		return nil
+	}
+	return Commands.Match(Tokenize(last.text))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
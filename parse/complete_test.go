@@ -0,0 +1,57 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestComplete(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(line string, cursor int, expectedWord string, expectedCompletions, expectedPlaceholders []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			completion := Complete(line, cursor, &session)
+
+			assert.Equal(t, expectedWord, completion.Word)
+			assert.ElementsMatch(t, expectedCompletions, completion.Completions)
+			assert.ElementsMatch(t, expectedPlaceholders, completion.Placeholders)
+		}
+	}
+
+	t.Run("empty", testCase("", 0, "", append(DisplayNames(ActionTokens), "alias", "macro"), []string{}))
+	t.Run("partial verb", testCase("li", 2, "li", []string{"list"}, []string{}))
+	t.Run("partial noun", testCase("new acc", 7, "acc", []string{"account"}, []string{}))
+	t.Run("cursor in the middle", testCase("new acc cash", 7, "acc", []string{"account"}, []string{}))
+	t.Run("cursor past the end", testCase("new ", 100, "", []string{"account", "transaction", "scheduled"}, []string{}))
+	t.Run("positional", testCase("new account ", 12, "", []string{"--help"}, []string{"<name>"}))
+	t.Run("flag", testCase("report forecast --acc", 21, "--acc", []string{"--account"}, []string{}))
+	t.Run("flag value", testCase("report forecast --account=", 26, "", []string{}, []string{"<account>"}))
+	t.Run("after positional", testCase("new account cash ", 17, "", []string{"--description", "--category", "--balance"}, []string{}))
+}
+
+func TestCompleteExposesSuggestion(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	completion := Complete("new account cash", 16, &session)
+
+	assert.True(t, completion.IsValidAsIs)
+	assert.Equal(t, 12, completion.Start)
+	assert.NotNil(t, completion.NextArgs)
+}
+
+func TestCompleteDescribesArguments(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	completion := Complete("source run.bujit --", 19, &session)
+	assert.Equal(t, map[string]string{"--continue-on-error": "keep running the remaining commands after one fails."}, completion.Descriptions)
+
+	completion = Complete("list account; report forecast -", 31, &session)
+	assert.Equal(t, "name of the account to forecast.", completion.Descriptions["--account"])
+	assert.NotContains(t, completion.Descriptions, "--help")
+
+	completion = Complete("new ", 4, &session)
+	assert.Empty(t, completion.Descriptions)
+}
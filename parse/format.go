@@ -0,0 +1,55 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"samvasta.com/bujit/actions"
+)
+
+// FormatArgToken can be added to any command to render its output as markdown or html instead of for the terminal
+var FormatArgToken *TokenPattern = &TokenPattern{ARG_FORMAT, "--format", []*regexp.Regexp{regexp.MustCompile(`--format=?`)}}
+
+var formatValues = map[string]actions.OutputFormat{
+	"md":       actions.FormatMarkdown,
+	"markdown": actions.FormatMarkdown,
+	"html":     actions.FormatHTML,
+}
+
+var formatNames = []string{"md", "html"}
+
+// extractFormat removes the --format arg and its value from the tokens. positions holds the index in tokens of each
+// remaining token.
+func extractFormat(tokens []string) (remaining []string, positions []int, format actions.OutputFormat, suggestion AutoSuggestion) {
+	for i := 0; i < len(tokens); i++ {
+		if option, value, ok := splitOption(tokens[i]); ok && value != "" && FormatArgToken.Matches(option) {
+			// --format=value
+			format, ok = formatValues[strings.ToLower(value)]
+			if !ok {
+				return nil, nil, actions.FormatTerminal, AutoSuggestion{false, value, formatNames, &Diagnostic{TokenIndex: i, Expected: formatNames, Message: fmt.Sprintf("unknown format \"%s\" — expected md or html", value)}, ""}
+			}
+			continue
+		}
+
+		if !FormatArgToken.Matches(tokens[i]) {
+			remaining = append(remaining, tokens[i])
+			positions = append(positions, i)
+			continue
+		}
+
+		if i+1 >= len(tokens) {
+			// Missing arg value
+			return nil, nil, actions.FormatTerminal, AutoSuggestion{false, "", formatNames, &Diagnostic{TokenIndex: i + 1, Expected: formatNames, Message: "missing value for --format"}, ""}
+		}
+
+		value, ok := formatValues[strings.ToLower(tokens[i+1])]
+		if !ok {
+			return nil, nil, actions.FormatTerminal, AutoSuggestion{false, tokens[i+1], formatNames, &Diagnostic{TokenIndex: i + 1, Expected: formatNames, Message: fmt.Sprintf("unknown format \"%s\" — expected md or html", tokens[i+1])}, ""}
+		}
+
+		format = value
+		i++
+	}
+	return remaining, positions, format, EmptySuggestions
+}
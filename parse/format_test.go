@@ -0,0 +1,52 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestFormatArg(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expectedFormat actions.OutputFormat) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+
+			if !isValid {
+				assert.Nil(t, action)
+				assert.ElementsMatch(t, []string{"md", "html"}, suggestion.NextArgs)
+				return
+			}
+
+			formatAction, ok := action.(actions.FormatAction)
+			assert.True(t, ok)
+			assert.Equal(t, expectedFormat, formatAction.Format)
+
+			result, _ := action.Execute()
+			assert.True(t, result.IsSuccessful)
+			assert.Equal(t, expectedFormat, result.Output.(actions.FormattedOutput).Format)
+		}
+	}
+
+	t.Run("markdown", testCase("help --format=md", true, actions.FormatMarkdown))
+	t.Run("markdown long name", testCase("help --format markdown", true, actions.FormatMarkdown))
+	t.Run("html before other args", testCase("new account --format=html --help", true, actions.FormatHTML))
+	t.Run("missing value", testCase("help --format", false, actions.FormatTerminal))
+	t.Run("unknown value", testCase("help --format=pdf", false, actions.FormatTerminal))
+	t.Run("empty value", testCase(`help --format="" --help`, false, actions.FormatTerminal))
+}
+
+func TestNoFormatArg(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	action, _ := ParseExpression("help", &session)
+
+	_, ok := action.(actions.HelpAction)
+	assert.True(t, ok)
+}
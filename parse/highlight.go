@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"strings"
+)
+
+// TokenKind is the part a token plays in a command line, so it can be coloured
+type TokenKind int
+
+const (
+	VerbToken      TokenKind = iota // the first word of a command, or an alias or macro
+	NounToken                       // the type after the verb, such as account
+	FlagToken                       // a flag or option, up to and including the = of its value
+	ValueToken                      // a value given to the command
+	KeywordToken                    // a word of a query or of a pipe stage, such as filter or sort
+	SeparatorToken                  // a ; or | between commands and stages
+	InvalidToken                    // the token that the parser stopped at
+)
+
+// StyledSpan is a part of a command line, in bytes from the start of the line, and what it is
+type StyledSpan struct {
+	Span
+	Kind TokenKind
+}
+
+// highlightKeywords are the words that are coloured as keywords inside a query or a pipe stage
+var highlightKeywords = []*TokenPattern{
+	allTokens[FILTER], allTokens[ORDER], allTokens[BY], allTokens[LIMIT], allTokens[AND], allTokens[OR], allTokens[NOT],
+	allTokens[ASC], allTokens[DESC],
+}
+
+// Highlight splits a command line into the spans of its tokens and separators, in order, and says what each one is.
+// suggestion is what ParseExpression returned for the line, and the token its diagnostic points at is invalid.
+// Whitespace is not part of any span.
+func Highlight(input string, suggestion AutoSuggestion) []StyledSpan {
+	styled := []StyledSpan{}
+
+	for i, seg := range splitCommandLine(input) {
+		if i > 0 {
+			styled = append(styled, StyledSpan{Span{seg.start - 1, seg.start}, SeparatorToken})
+		}
+
+		tokens, spans, _ := TokenizeWithSpans(seg.text)
+		for j := range spans {
+			spans[j].Start += seg.start
+			spans[j].End += seg.start
+		}
+
+		if seg.isStage {
+			styled = append(styled, highlightStage(tokens, spans)...)
+		} else {
+			styled = append(styled, highlightCommand(input, tokens, spans)...)
+		}
+	}
+
+	if diagnostic := suggestion.Diagnostic; diagnostic != nil && diagnostic.Start < diagnostic.End {
+		for i, s := range styled {
+			if s.Start < diagnostic.End && diagnostic.Start < s.End {
+				styled[i].Kind = InvalidToken
+				break
+			}
+		}
+	}
+	return styled
+}
+
+func highlightCommand(input string, tokens []string, spans []Span) []StyledSpan {
+	styled := []StyledSpan{}
+	if len(tokens) == 0 {
+		return styled
+	}
+	styled = append(styled, StyledSpan{spans[0], VerbToken})
+
+	args := 1
+	if verb, _ := PossibleMatches(tokens[0], Commands.Verbs()); verb != nil && len(Commands.Nouns(verb.Id)) > 0 && len(tokens) > 1 {
+		styled = append(styled, StyledSpan{spans[1], NounToken})
+		args = 2
+	}
+
+	inQuery := false
+	for i := args; i < len(tokens); i++ {
+		token, span := tokens[i], spans[i]
+		keyword, _ := PossibleMatches(token, highlightKeywords)
+
+		switch {
+		case !inQuery && strings.HasPrefix(token, "-"):
+			// an option joined to its value is coloured in two parts
+			if equals := strings.Index(input[span.Start:span.End], "="); equals >= 0 && span.Start+equals+1 < span.End {
+				styled = append(styled,
+					StyledSpan{Span{span.Start, span.Start + equals + 1}, FlagToken},
+					StyledSpan{Span{span.Start + equals + 1, span.End}, ValueToken})
+			} else {
+				styled = append(styled, StyledSpan{span, FlagToken})
+			}
+		case keyword != nil && (inQuery || keyword.Id == FILTER || keyword.Id == ORDER || keyword.Id == LIMIT):
+			inQuery = true
+			styled = append(styled, StyledSpan{span, KeywordToken})
+		default:
+			styled = append(styled, StyledSpan{span, ValueToken})
+		}
+	}
+	return styled
+}
+
+func highlightStage(tokens []string, spans []Span) []StyledSpan {
+	styled := []StyledSpan{}
+	for i, token := range tokens {
+		kind := ValueToken
+		if keyword, _ := PossibleMatches(token, highlightKeywords); i == 0 || keyword != nil {
+			kind = KeywordToken
+		}
+		styled = append(styled, StyledSpan{spans[i], kind})
+	}
+	return styled
+}
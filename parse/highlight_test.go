@@ -0,0 +1,56 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestHighlight(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, expected []StyledSpan) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, suggestion := ParseExpression(input, &s)
+			assert.Equal(t, expected, Highlight(input, suggestion))
+		}
+	}
+
+	t.Run("empty", testCase("", []StyledSpan{}))
+	t.Run("command", testCase(`new account "my cash" -d=wallet --hard`, []StyledSpan{
+		{Span{0, 3}, VerbToken},
+		{Span{4, 11}, NounToken},
+		{Span{12, 21}, ValueToken},
+		{Span{22, 25}, FlagToken},
+		{Span{25, 31}, ValueToken},
+		{Span{32, 38}, InvalidToken},
+	}))
+	t.Run("no noun", testCase("source run.bujit", []StyledSpan{
+		{Span{0, 6}, VerbToken},
+		{Span{7, 16}, ValueToken},
+	}))
+	t.Run("query and stages", testCase("ls account filter balance>0 order by name desc | count; version", []StyledSpan{
+		{Span{0, 2}, VerbToken},
+		{Span{3, 10}, NounToken},
+		{Span{11, 17}, KeywordToken},
+		{Span{18, 27}, ValueToken},
+		{Span{28, 33}, KeywordToken},
+		{Span{34, 36}, KeywordToken},
+		{Span{37, 41}, ValueToken},
+		{Span{42, 46}, KeywordToken},
+		{Span{47, 48}, SeparatorToken},
+		{Span{49, 54}, KeywordToken},
+		{Span{54, 55}, SeparatorToken},
+		{Span{56, 63}, VerbToken},
+	}))
+	t.Run("invalid", testCase("lsit account", []StyledSpan{
+		{Span{0, 4}, InvalidToken},
+		{Span{5, 12}, ValueToken},
+	}))
+	t.Run("missing", testCase("new account", []StyledSpan{
+		{Span{0, 3}, VerbToken},
+		{Span{4, 11}, NounToken},
+	}))
+}
@@ -2,7 +2,6 @@ package parse
 
 import (
 	"samvasta.com/bujit/actions"
-	actions_accounts "samvasta.com/bujit/actions/accounts"
 	"samvasta.com/bujit/session"
 )
 
@@ -43,12 +42,29 @@ const (
 	OPEN
 	SET
 	PRINT
+	REPORT
+	SOURCE
+	ALIAS
+	MACRO
+	HISTORY
 
 	// Models
 	CATEGORY
 	ACCOUNT
 	ACCOUNT_STATE
 	TRANSACTION
+	SCHEDULED
+	MACROS
+
+	// Reports
+	FORECAST
+	TREND
+
+	// Pipe stages
+	SUM
+	COUNT
+	SORT
+	EXPORT
 
 	// Args
 	ARG_FROM
@@ -59,10 +75,27 @@ const (
 	ARG_STARTING_BALANCE
 	ARG_MIN_BALANCE
 	ARG_MAX_BALANCE
+	ARG_ACCOUNT
+	ARG_DAYS
+	ARG_MONTHS
+	ARG_THRESHOLD
+	ARG_FORMAT
+	ARG_PATH
+	ARG_AMOUNT
+	ARG_MEMO
+	ARG_LIMIT
+	ARG_DATE
+	ARG_SINCE
+	ARG_UNTIL
+	ARG_START
+	ARG_NUMBER
+	ARG_EVERY
+	ARG_QUERY // the filter, ordering and limit after the arguments of a list command
 
 	// Flags
 	FLAG_HELP
 	FLAG_HARD
+	FLAG_CONTINUE_ON_ERROR
 
 	// Misc
 	FILTER
@@ -70,6 +103,15 @@ const (
 	BY
 	FROM
 	TO
+	LIMIT
+	AND
+	OR
+	NOT
+	ASC
+	DESC
+	QUERY_FIELD  // a field that a list can be filtered or ordered by
+	QUERY_SYMBOL // an operator, parenthesis or comma in a query
+	MACRO_CALL   // the name of a user defined alias or macro
 )
 
 var allTokens map[int]*TokenPattern = map[int]*TokenPattern{
@@ -91,139 +133,166 @@ var allTokens map[int]*TokenPattern = map[int]*TokenPattern{
 	SET:   MakeLiteralToken(SET, "set"),
 	PRINT: MakeLiteralToken(PRINT, "print"),
 
+	REPORT: MakeLiteralToken(REPORT, "report"),
+	SOURCE: MakeLiteralToken(SOURCE, "source"),
+	ALIAS:  MakeLiteralToken(ALIAS, "alias"),
+	MACRO:  MakeLiteralToken(MACRO, "macro"),
+
+	HISTORY: MakeLiteralToken(HISTORY, "history"),
+
 	FILTER: MakeLiteralToken(FILTER, "filter"),
 	ORDER:  MakeLiteralToken(ORDER, "order"),
 	BY:     MakeLiteralToken(BY, "by"),
+	LIMIT:  MakeLiteralToken(LIMIT, "limit"),
+	AND:    MakeLiteralToken(AND, "and"),
+	OR:     MakeLiteralToken(OR, "or"),
+	NOT:    MakeLiteralToken(NOT, "not"),
+	ASC:    MakeLiteralToken(ASC, "asc"),
+	DESC:   MakeLiteralToken(DESC, "desc"),
 
 	// Nouns
 	CATEGORY:      MakeLiteralToken(CATEGORY, "category", "group"),
 	ACCOUNT:       MakeLiteralToken(ACCOUNT, "account", "acct"),
 	ACCOUNT_STATE: MakeLiteralToken(ACCOUNT_STATE, "account_state", "acct_state"),
 	TRANSACTION:   MakeLiteralToken(TRANSACTION, "transaction", "tran"),
+	SCHEDULED:     MakeLiteralToken(SCHEDULED, "scheduled", "sched"),
+	MACROS:        MakeLiteralToken(MACROS, "macro", "alias"),
+
+	// Reports
+	FORECAST: MakeLiteralToken(FORECAST, "forecast"),
+	TREND:    MakeLiteralToken(TREND, "trend"),
+
+	// Pipe stages
+	SUM:    MakeLiteralToken(SUM, "sum"),
+	COUNT:  MakeLiteralToken(COUNT, "count"),
+	SORT:   MakeLiteralToken(SORT, "sort"),
+	EXPORT: MakeLiteralToken(EXPORT, "export"),
 }
 
-var ActionTokens = []*TokenPattern{
-	allTokens[NEW],
-	allTokens[LIST],
-	allTokens[DELETE],
-	allTokens[MODIFY],
-	allTokens[DETAIL],
-	allTokens[CLOSE],
-	allTokens[OPEN],
-	allTokens[CONFIGURE],
-	allTokens[HELP],
-	allTokens[EXIT],
-	allTokens[VERSION],
-}
+// Commands is every command the parser understands, in the order they are suggested and listed in help.
+var Commands = NewRegistry(
+	newAccountCommand,
+	newTransactionCommand,
+	newScheduledCommand,
+	listAccountCommand,
+	listCategoryCommand,
+	listTransactionCommand,
+	listScheduledCommand,
+	deleteAccountCommand,
+	forecastCommand,
+	trendCommand,
+	listMacroCommand,
+	deleteMacroCommand,
+	sourceCommand,
+	historyCommand,
+	versionCommand,
+	exitCommand,
+)
+
+// ActionTokens are the words that can start a command
+var ActionTokens = append(Commands.Verbs(), allTokens[HELP])
 
-var ModelTokens = []*TokenPattern{
-	allTokens[CATEGORY],
-	allTokens[ACCOUNT],
-	allTokens[ACCOUNT_STATE],
-	allTokens[TRANSACTION],
+// DefinitionTokens are the words that start the definition of an alias or macro
+var DefinitionTokens = []*TokenPattern{allTokens[ALIAS], allTokens[MACRO]}
+
+// commandTokens are the words that can start a command: the built in commands, then the definitions, then the aliases
+// and macros of the ledger
+func commandTokens(session *session.Session) []*TokenPattern {
+	tokens := append([]*TokenPattern{}, ActionTokens...)
+	tokens = append(tokens, DefinitionTokens...)
+	return append(tokens, macroTokens(session)...)
 }
 
+// ParseExpression parses a command line. Several commands may be separated by ; and each may be followed by pipe
+// stages after a |.
 func ParseExpression(input string, session *session.Session) (action actions.Actioner, suggestion AutoSuggestion) {
-	tokens := Tokenize(input)
+	if segments := splitCommandLine(input); len(segments) > 1 {
+		return parsePipeline(segments, session)
+	}
 
-	actionTok := tokens[0]
+	inputTokens, spans, lexErr := TokenizeWithSpans(input)
 
-	exact, possible := PossibleMatches(actionTok, ActionTokens)
+	tokens, tokenSpans, problem := expandMacro(inputTokens, spans, session)
+	if problem != nil {
+		suggestion = *problem
+	} else {
+		action, suggestion = parseInputTokens(tokens, session)
+	}
 
-	if exact == nil {
-		return nil, makeAutoSuggestion(false, actionTok, possible)
+	if lexErr != nil {
+		// Keep the suggestions for the rest of the input, but the command cannot run until the quote is closed
+		action = nil
+		suggestion.IsValidAsIs = false
+		suggestion.Diagnostic = &Diagnostic{TokenIndex: lexErr.TokenIndex, Expected: []string{lexErr.Expected}, Message: lexErr.Message}
+		tokenSpans = spans
 	}
 
-	parseContext := EmptyParseContext(tokens, session)
+	locate(suggestion.Diagnostic, tokenSpans, len(input))
 
-	switch exact.Id {
-	case NEW:
-		return ParseNew(&parseContext)
-	case LIST:
-		return ParseList(&parseContext)
-	case DELETE:
-		return nil, EmptySuggestions
-	case MODIFY:
-		return nil, EmptySuggestions
-	case DETAIL:
-		return nil, EmptySuggestions
-	case CLOSE:
-		return nil, EmptySuggestions
-	case OPEN:
-		return nil, EmptySuggestions
-	case CONFIGURE:
-		return nil, EmptySuggestions
-	case HELP:
-		return parseHelpRoot(&HelpContext{ParseContext: parseContext, verbose: false})
-	case EXIT:
-		return nil, EmptySuggestions
-	case VERSION:
-		return nil, EmptySuggestions
-	default:
-		return nil, EmptySuggestions
-	}
+	return action, suggestion
 }
 
-func ParseNew(context *ParseContext) (action actions.Actioner, suggestion AutoSuggestion) {
-	nextToken, hasNext := context.nextToken()
+// parseInputTokens parses every token of the input, including the --format arg
+func parseInputTokens(inputTokens []string, session *session.Session) (action actions.Actioner, suggestion AutoSuggestion) {
+	tokens, positions, format, suggestion := extractFormat(inputTokens)
 
-	if hasNext {
-		exact, possible := PossibleMatches(nextToken, ModelTokens)
+	if !suggestion.IsValidAsIs {
+		return nil, suggestion
+	}
 
-		if exact == nil {
-			return nil, makeAutoSuggestion(false, nextToken, possible)
-		}
+	action, suggestion = parseTokens(tokens, session)
 
-		switch exact.Id {
-		case CATEGORY:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
-		case ACCOUNT:
-			context.moveToNextToken()
-			return parseNewAccount(
-				&NewAccountContext{
-					ParseContext: *context,
-					action:       actions_accounts.CreateAccountAction{Session: context.session}})
-		case ACCOUNT_STATE:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
-		case TRANSACTION:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
+	if suggestion.Diagnostic != nil {
+		// The parser counts tokens without the format arg, so map back to the position in the input
+		if suggestion.Diagnostic.TokenIndex < len(positions) {
+			suggestion.Diagnostic.TokenIndex = positions[suggestion.Diagnostic.TokenIndex]
+		} else {
+			suggestion.Diagnostic.TokenIndex = len(inputTokens)
 		}
 	}
 
-	return nil, makeAutoSuggestion(false, nextToken, ModelTokens)
+	if action != nil && format != actions.FormatTerminal {
+		action = actions.FormatAction{Action: action, Format: format}
+	}
+
+	return action, suggestion
 }
 
-func ParseList(context *ParseContext) (action actions.Actioner, suggestion AutoSuggestion) {
-	nextToken, hasNext := context.nextToken()
+// locate sets the byte span of the diagnostic from its token index. Missing tokens are placed at the end of the input.
+func locate(diagnostic *Diagnostic, spans []Span, inputLen int) {
+	if diagnostic == nil {
+		return
+	}
+	if diagnostic.TokenIndex < len(spans) {
+		diagnostic.Start, diagnostic.End = spans[diagnostic.TokenIndex].Start, spans[diagnostic.TokenIndex].End
+	} else {
+		diagnostic.Start, diagnostic.End = inputLen, inputLen
+	}
+}
 
-	if hasNext {
-		exact, possible := PossibleMatches(nextToken, ModelTokens)
+func parseTokens(tokens []string, session *session.Session) (action actions.Actioner, suggestion AutoSuggestion) {
+	if len(tokens) == 0 {
+		return nil, makeMissingTokenSuggestion("command", 0, commandTokens(session))
+	}
 
-		if exact == nil {
-			return nil, makeAutoSuggestion(false, nextToken, possible)
-		}
+	actionTok := tokens[0]
 
-		switch exact.Id {
-		case CATEGORY:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
-		case ACCOUNT:
-			context.moveToNextToken()
-			return parseListAccount(
-				&ListAccountContext{
-					ParseContext: *context,
-					action:       actions_accounts.ListAccountAction{Session: context.session}})
-		case ACCOUNT_STATE:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
-		case TRANSACTION:
-			context.moveToNextToken()
-			return nil, EmptySuggestions
-		}
+	expected := commandTokens(session)
+	exact, possible := PossibleMatches(actionTok, expected)
+
+	if exact == nil || exact.Id == MACRO_CALL {
+		// Macros are expanded before parsing, so a macro name only gets here when it does not start the command
+		return nil, makeUnknownTokenSuggestion("command", 0, actionTok, expected, possible)
+	}
+
+	parseContext := EmptyParseContext(tokens, session)
+
+	switch exact.Id {
+	case HELP:
+		return parseHelpRoot(&HelpContext{ParseContext: parseContext, verbose: false})
+	case ALIAS, MACRO:
+		return parseMacroDefinition(&parseContext, exact.Id == ALIAS)
 	}
 
-	return nil, makeAutoSuggestion(false, nextToken, ModelTokens)
+	return Commands.parseVerb(&parseContext, exact.Id)
 }
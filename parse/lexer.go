@@ -0,0 +1,142 @@
+package parse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a range of bytes in the input, from Start up to but not including End
+type Span struct {
+	Start int
+	End   int
+}
+
+// LexError is a problem with the input found while splitting it into tokens, such as a quote that is never closed
+type LexError struct {
+	TokenIndex int
+	Expected   string
+	Message    string
+}
+
+// Tokenize splits the input into tokens the way a shell splits arguments. See TokenizeWithSpans.
+func Tokenize(input string) (tokens []string) {
+	tokens, _, _ = TokenizeWithSpans(input)
+	return tokens
+}
+
+// TokenizeWithSpans splits the input into tokens at unquoted whitespace and returns where each token is in the input.
+//
+// Text in double quotes is kept together, and a backslash inside it escapes a double quote or another backslash. Text in
+// single quotes is kept together exactly as written. Outside of quotes a backslash escapes the character after it.
+// Quotes may appear in the middle of a token, so -d="weekly groceries" is the single token -d=weekly groceries. An
+// option given an empty value in quotes, as in -d="", is the option followed by an empty token, the same as -d= "", so
+// the token after it is never read as its value. The span of a token includes its quotes.
+//
+// A quote that is not closed by the end of the input ends the last token and is reported as an error.
+func TokenizeWithSpans(input string) (tokens []string, spans []Span, err *LexError) {
+	var sb strings.Builder
+	inToken := false
+	start := 0
+	var quote rune // the quote that is open, or 0
+	quoteStart, quoteLen := 0, 0
+	emptyValueAt, emptyValueLen := -1, 0 // where an empty quoted value of an option starts, and the length of the option
+
+	endToken := func(end int) {
+		if inToken {
+			if emptyValueAt >= 0 && sb.Len() == emptyValueLen {
+				tokens = append(tokens, sb.String(), "")
+				spans = append(spans, Span{start, emptyValueAt}, Span{emptyValueAt, end})
+			} else {
+				tokens = append(tokens, sb.String())
+				spans = append(spans, Span{start, end})
+			}
+			sb.Reset()
+			inToken = false
+			emptyValueAt = -1
+		}
+	}
+
+	closeQuote := func() {
+		quote = 0
+		if text := sb.String(); sb.Len() == quoteLen && strings.HasPrefix(text, "-") && strings.HasSuffix(text, "=") && emptyValueAt < 0 {
+			emptyValueAt, emptyValueLen = quoteStart, quoteLen
+		}
+	}
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		if !inToken && !unicode.IsSpace(r) {
+			inToken = true
+			start = i
+		}
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				closeQuote()
+			} else {
+				sb.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				closeQuote()
+			} else if r == '\\' && i+1 < len(input) && (input[i+1] == '"' || input[i+1] == '\\') {
+				sb.WriteByte(input[i+1])
+				size++
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			quoteStart, quoteLen = i, sb.Len()
+		case r == '\\' && i+size < len(input):
+			escaped, escapedSize := utf8.DecodeRuneInString(input[i+size:])
+			sb.WriteRune(escaped)
+			size += escapedSize
+		case unicode.IsSpace(r):
+			endToken(i)
+		default:
+			sb.WriteRune(r)
+		}
+
+		i += size
+	}
+
+	if quote != 0 {
+		err = &LexError{TokenIndex: len(tokens), Expected: string(quote), Message: "missing closing quote " + string(quote)}
+	}
+	endToken(len(input))
+
+	return tokens, spans, err
+}
+
+// JoinTokens is the inverse of Tokenize. It quotes any token that is empty or contains whitespace, quotes or
+// backslashes so it stays one token with the same text.
+func JoinTokens(tokens []string) string {
+	quoted := make([]string, len(tokens))
+	for i, token := range tokens {
+		if token != "" && !strings.ContainsAny(token, " \t\"'\\") {
+			quoted[i] = token
+		} else if strings.Contains(token, `"`) && !strings.Contains(token, "'") {
+			quoted[i] = "'" + token + "'"
+		} else {
+			quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(token) + `"`
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// splitOption splits a token such as -d=value or --description=value into the option, including the "=", and its
+// value. ok is false when the token is not an option joined to a value with "=".
+func splitOption(token string) (option, value string, ok bool) {
+	if !strings.HasPrefix(token, "-") {
+		return token, "", false
+	}
+	idx := strings.Index(token, "=")
+	if idx < 0 {
+		return token, "", false
+	}
+	return token[:idx+1], token[idx+1:], true
+}
@@ -0,0 +1,143 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// MacroNamePattern matches names of aliases and macros. Names start with a letter and may contain letters, digits, _
+// and -
+var MacroNamePattern *regexp.Regexp = regexp.MustCompile(`^\pL[\pL\pN_-]*$`)
+
+// paramPattern finds the references to positional parameters in an expansion, e.g. $1
+var paramPattern *regexp.Regexp = regexp.MustCompile(`\$(\d+)`)
+
+// macroCache keeps the aliases and macros the parser has read for each session, so they are not read again for every
+// command or keystroke. The entries of a ledger are forgotten whenever anything is written to its macros table.
+var macroCache = struct {
+	sync.Mutex
+	macros  map[*session.Session][]models.Macro
+	watched map[*gorm.Config]bool // ledgers whose writes forget the entries
+}{macros: map[*session.Session][]models.Macro{}, watched: map[*gorm.Config]bool{}}
+
+// loadMacros reads every alias and macro in the ledger, by name. A ledger without macros has none.
+func loadMacros(s *session.Session) []models.Macro {
+	macros := []models.Macro{}
+	if s == nil || s.Db == nil {
+		return macros
+	}
+
+	macroCache.Lock()
+	defer macroCache.Unlock()
+	if cached, ok := macroCache.macros[s]; ok {
+		return cached
+	}
+	watchMacros(s.Db)
+
+	// Ledgers that were never migrated have no macros table, which is the same as having no macros
+	s.Db.Session(&gorm.Session{Logger: s.Db.Logger.LogMode(logger.Silent)}).Order("name").Find(&macros)
+	macroCache.macros[s] = macros
+	return macros
+}
+
+// watchMacros forgets the macros of every session of the ledger once anything is created, saved or deleted in its
+// macros table. The cache must be locked.
+func watchMacros(db *gorm.DB) {
+	if macroCache.watched[db.Config] {
+		return
+	}
+	macroCache.watched[db.Config] = true
+
+	forget := func(tx *gorm.DB) {
+		if tx.Statement.Table != "macros" {
+			return
+		}
+		macroCache.Lock()
+		defer macroCache.Unlock()
+		for s := range macroCache.macros {
+			if s.Db != nil && s.Db.Config == tx.Config {
+				delete(macroCache.macros, s)
+			}
+		}
+	}
+	db.Callback().Create().After("gorm:create").Register("parse:forget_macros", forget)
+	db.Callback().Update().After("gorm:update").Register("parse:forget_macros", forget)
+	db.Callback().Delete().After("gorm:delete").Register("parse:forget_macros", forget)
+}
+
+func findMacro(s *session.Session, name string) (models.Macro, bool) {
+	for _, macro := range loadMacros(s) {
+		if strings.EqualFold(macro.Name, name) {
+			return macro, true
+		}
+	}
+	return models.Macro{}, false
+}
+
+// macroTokens are the names of the aliases and macros in the ledger, so they are suggested as commands
+func macroTokens(s *session.Session) []*TokenPattern {
+	tokens := []*TokenPattern{}
+	for _, macro := range loadMacros(s) {
+		tokens = append(tokens, MakeLiteralToken(MACRO_CALL, macro.Name))
+	}
+	return tokens
+}
+
+// expandMacro replaces an alias or macro at the start of the tokens with its expansion. $1 to $n in the expansion are
+// replaced by the tokens after the name, and any tokens left over are added to the end. Each token of the expansion
+// takes the span of the name, or of the argument it uses, so diagnostics point at what was typed. problem is set when
+// the macro is missing arguments.
+func expandMacro(tokens []string, spans []Span, s *session.Session) (expanded []string, expandedSpans []Span, problem *AutoSuggestion) {
+	if len(tokens) == 0 {
+		return tokens, spans, nil
+	}
+	macro, ok := findMacro(s, tokens[0])
+	if !ok {
+		return tokens, spans, nil
+	}
+
+	args, argSpans := tokens[1:], spans[1:]
+	if len(args) < macro.Params {
+		placeholder := fmt.Sprintf("<$%d>", len(args)+1)
+		return tokens, spans, &AutoSuggestion{false, "", []string{placeholder}, &Diagnostic{
+			TokenIndex: len(tokens),
+			Expected:   []string{placeholder},
+			Message:    fmt.Sprintf("missing value for $%d of %s, which is %s", len(args)+1, macro.Name, macro.Definition()),
+		}, ""}
+	}
+
+	for _, token := range Tokenize(macro.Expansion) {
+		span := spans[0]
+		token = paramPattern.ReplaceAllStringFunc(token, func(ref string) string {
+			n, _ := strconv.Atoi(ref[1:])
+			if n < 1 || n > macro.Params {
+				return ref
+			}
+			span = argSpans[n-1]
+			return args[n-1]
+		})
+		expanded = append(expanded, token)
+		expandedSpans = append(expandedSpans, span)
+	}
+
+	expanded = append(expanded, args[macro.Params:]...)
+	expandedSpans = append(expandedSpans, argSpans[macro.Params:]...)
+	return expanded, expandedSpans, nil
+}
+
+// macroNames lists the names of every alias and macro, alphabetically
+func macroNames(s *session.Session) []string {
+	names := []string{}
+	for _, macro := range loadMacros(s) {
+		names = append(names, macro.Name)
+	}
+	return names
+}
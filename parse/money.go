@@ -0,0 +1,230 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"samvasta.com/bujit/models"
+)
+
+// evaluateMoney reads an amount of money written as a number, such as $12.34 or 1,234.50 USD, or as an arithmetic
+// expression of numbers with + - * / and parentheses, such as 120.00/3 + 4.50.
+//
+// The arithmetic is exact. Only the result is rounded to the nearest cent, with halves rounded away from zero, so
+// 10/3 is 3.33, 20/3 is 6.67 and 0.125 is 0.13.
+func evaluateMoney(expression string) (models.Money, error) {
+	p := moneyParser{input: expression}
+
+	value, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return 0, p.unexpected()
+	}
+
+	return roundToCents(value), nil
+}
+
+// isMoneyExpression is true when the value is more than a single number, so its evaluated value is worth showing
+func isMoneyExpression(value string) bool {
+	value = strings.TrimLeftFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) || r == '-' || r == '+'
+	})
+	return strings.ContainsAny(value, "+-*/()")
+}
+
+// roundToCents rounds a number of dollars to the nearest cent, rounding halves away from zero
+func roundToCents(value *big.Rat) models.Money {
+	cents := new(big.Rat).Mul(value, big.NewRat(100, 1))
+
+	num := new(big.Int).Abs(cents.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, cents.Denom(), new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(cents.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if cents.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+
+	return models.Money(quotient.Int64())
+}
+
+// moneyParser is a recursive descent parser for
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = ("+" | "-") factor | "(" expression ")" | amount
+//	amount     = [currency symbol] number [currency code]
+type moneyParser struct {
+	input string
+	pos   int
+}
+
+func (p *moneyParser) expression() (*big.Rat, error) {
+	value, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			value.Add(value, right)
+		case '-':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			value.Sub(value, right)
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *moneyParser) term() (*big.Rat, error) {
+	value, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			right, err := p.factor()
+			if err != nil {
+				return nil, err
+			}
+			value.Mul(value, right)
+		case '/':
+			p.pos++
+			right, err := p.factor()
+			if err != nil {
+				return nil, err
+			}
+			if right.Sign() == 0 {
+				return nil, errors.New("division by zero")
+			}
+			value.Quo(value, right)
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *moneyParser) factor() (*big.Rat, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		value, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return value.Neg(value), nil
+	case '+':
+		p.pos++
+		return p.factor()
+	case '(':
+		p.pos++
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return value, nil
+	}
+	return p.amount()
+}
+
+func (p *moneyParser) amount() (*big.Rat, error) {
+	if r, size := utf8.DecodeRuneInString(p.input[p.pos:]); unicode.Is(unicode.Sc, r) {
+		p.pos += size
+		if p.peek() == '-' {
+			// $-12.34
+			p.pos++
+			value, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			return value.Neg(value), nil
+		}
+	}
+
+	return p.number()
+}
+
+func (p *moneyParser) number() (*big.Rat, error) {
+	p.skipSpace()
+
+	start := p.pos
+	for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == ',' || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos >= len(p.input) {
+			return nil, errors.New("expected a number")
+		}
+		return nil, p.unexpected()
+	}
+
+	value, ok := new(big.Rat).SetString(strings.ReplaceAll(p.input[start:p.pos], ",", ""))
+	if !ok {
+		return nil, fmt.Errorf("\"%s\" is not a number", p.input[start:p.pos])
+	}
+
+	// optional currency code, e.g. 12.34 USD
+	p.skipSpace()
+	if code := p.pos + 3; code <= len(p.input) && isCurrencyCode(p.input[p.pos:code]) && (code == len(p.input) || !unicode.IsLetter(rune(p.input[code]))) {
+		p.pos = code
+	}
+
+	return value, nil
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end of the input
+func (p *moneyParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *moneyParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *moneyParser) unexpected() error {
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return fmt.Errorf("unexpected \"%c\"", r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isCurrencyCode(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
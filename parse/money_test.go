@@ -0,0 +1,89 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestEvaluateMoney(t *testing.T) {
+	testCase := func(expression string, expected models.Money) func(t *testing.T) {
+		return func(t *testing.T) {
+			value, err := evaluateMoney(expression)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+	}
+
+	t.Run("Number", testCase("12.34", 1234))
+	t.Run("Currency prefix and suffix", testCase("$1,234.50 USD", 123450))
+	t.Run("Negative with currency prefix", testCase("$-12.34", -1234))
+	t.Run("Split bill", testCase("120.00/3 + 4.50", 4450))
+	t.Run("Precedence", testCase("2 + 3 * 4", 1400))
+	t.Run("Parentheses", testCase("(2 + 3) * 4", 2000))
+	t.Run("Left to right", testCase("10 - 4 - 3", 300))
+	t.Run("Unary minus", testCase("-(5 - 7)", 200))
+	t.Run("Amounts with currency", testCase("$10.25 + $4.75", 1500))
+	t.Run("Exact until the end", testCase("1/3 * 3", 100))
+	t.Run("Rounds down", testCase("10/3", 333))
+	t.Run("Rounds up", testCase("20/3", 667))
+	t.Run("Halves away from zero", testCase("0.125", 13))
+	t.Run("Negative halves away from zero", testCase("-0.125", -13))
+	t.Run("More decimals", testCase("0.1 + 0.2", 30))
+}
+
+func TestEvaluateMoneyErrors(t *testing.T) {
+	testCase := func(expression, expectedError string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := evaluateMoney(expression)
+			if assert.Error(t, err) {
+				assert.Equal(t, expectedError, err.Error())
+			}
+		}
+	}
+
+	t.Run("Division by zero", testCase("12/(3-3)", "division by zero"))
+	t.Run("Missing parenthesis", testCase("(1 + 2", "missing )"))
+	t.Run("Extra parenthesis", testCase("1 + 2)", `unexpected ")"`))
+	t.Run("Trailing operator", testCase("5 +", "expected a number"))
+	t.Run("Words", testCase("notmoney", `unexpected "n"`))
+	t.Run("Empty", testCase("", "expected a number"))
+	t.Run("Two points", testCase("1.2.3", `"1.2.3" is not a number`))
+}
+
+func TestIsMoneyExpression(t *testing.T) {
+	assert.False(t, isMoneyExpression("12.34"))
+	assert.False(t, isMoneyExpression("-12.34"))
+	assert.False(t, isMoneyExpression("$-12.34 USD"))
+	assert.True(t, isMoneyExpression("120/3"))
+	assert.True(t, isMoneyExpression("-5 + 2"))
+	assert.True(t, isMoneyExpression("(2)"))
+}
+
+func TestMoneyExpressionArgs(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	action, suggestion := ParseExpression(`new account cash -b="120.00/3 + 4.50"`, &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, models.Money(4450), action.(actions_accounts.CreateAccountAction).StartingBalance)
+	assert.Equal(t, "120.00/3 + 4.50 = 44.50 USD", suggestion.Preview)
+
+	action, suggestion = ParseExpression(`new transaction (30+12.50)/2 --from=cash`, &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, models.Money(2125), action.(actions_transactions.CreateTransactionAction).Amount)
+	assert.Empty(t, suggestion.Preview, "the amount is not the last thing typed")
+
+	action, suggestion = ParseExpression(`list account --min-balance=12/0`, &s)
+	assert.Nil(t, action)
+	if assert.NotNil(t, suggestion.Diagnostic) {
+		assert.Equal(t, `invalid min-balance "12/0": division by zero`, suggestion.Diagnostic.Message)
+		assert.Equal(t, 2, suggestion.Diagnostic.TokenIndex)
+	}
+
+	_, suggestion = ParseExpression(`new account cash -b=12.50`, &s)
+	assert.Empty(t, suggestion.Preview, "a plain number needs no preview")
+}
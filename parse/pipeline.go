@@ -0,0 +1,187 @@
+package parse
+
+import (
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_categories "samvasta.com/bujit/actions/categories"
+	actions_pipeline "samvasta.com/bujit/actions/pipeline"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+)
+
+// StageTokens are the words that can follow a |
+var StageTokens = []*TokenPattern{allTokens[SUM], allTokens[COUNT], allTokens[SORT], allTokens[EXPORT]}
+
+var exportFormatTokens = []*TokenPattern{MakeLiteralToken(EXPORT, "csv"), MakeLiteralToken(EXPORT, "json")}
+
+// segment is one command, or one pipe stage, of a command line
+type segment struct {
+	text    string
+	start   int  // byte offset of the text in the command line
+	isStage bool // the text follows a |
+}
+
+// splitCommandLine splits the input at every ; and | that is not inside quotes or escaped with a backslash
+func splitCommandLine(input string) []segment {
+	segments := []segment{}
+	start := 0
+	isStage := false
+
+	var quote rune
+	escaped := false
+	for i, c := range input {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quote != '\'':
+			// quoting follows the same rules as TokenizeWithSpans
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ';' || c == '|':
+			segments = append(segments, segment{input[start:i], start, isStage})
+			start, isStage = i+1, c == '|'
+		}
+	}
+	return append(segments, segment{input[start:], start, isStage})
+}
+
+// parsePipeline parses a command line of several commands separated by ;, each of which may be followed by pipe stages,
+// e.g. "new account cash; list account -c=food | sort balance desc | export csv". The suggestion is for the last
+// command or stage, unless an earlier one does not parse.
+func parsePipeline(segments []segment, session *session.Session) (action actions.Actioner, suggestion AutoSuggestion) {
+	commands := []actions.Actioner{}
+	var current actions.Actioner
+	tokensBefore := 0
+
+	for i, seg := range segments {
+		tokens, spans, _ := TokenizeWithSpans(seg.text)
+
+		switch {
+		case seg.isStage:
+			var stage actions_pipeline.Stage
+			stage, suggestion = parseStage(tokens, stageFields(current))
+			locate(suggestion.Diagnostic, spans, len(seg.text))
+			if stage != nil {
+				current = actions_pipeline.PipeAction{Action: current, Stage: stage}
+			} else {
+				current = nil
+			}
+		case len(tokens) == 0 && (i == len(segments)-1 || !segments[i+1].isStage):
+			// An empty command, as after a trailing ;, does nothing. One that is followed by a stage is missing.
+			suggestion = makeAutoSuggestion(true, "", commandTokens(session))
+		default:
+			if current != nil {
+				commands = append(commands, current)
+			}
+			current, suggestion = ParseExpression(seg.text, session)
+		}
+
+		if suggestion.Diagnostic != nil {
+			suggestion.Diagnostic.TokenIndex += tokensBefore
+			suggestion.Diagnostic.Start += seg.start
+			suggestion.Diagnostic.End += seg.start
+		}
+		if !suggestion.IsValidAsIs || (current == nil && len(tokens) > 0) {
+			return nil, suggestion
+		}
+		tokensBefore += len(tokens)
+	}
+
+	if current != nil {
+		commands = append(commands, current)
+	}
+	if len(commands) == 1 {
+		return commands[0], suggestion
+	}
+	return actions_pipeline.SequenceAction{Actions: commands}, suggestion
+}
+
+// stageFields are the fields that what the action lists can be sorted by, or nil when they are not known
+func stageFields(action actions.Actioner) query.Fields {
+	switch a := action.(type) {
+	case actions.FormatAction:
+		return stageFields(a.Action)
+	case actions_pipeline.PipeAction:
+		return stageFields(a.Action)
+	case actions_accounts.ListAccountAction:
+		return actions_accounts.ListAccountFields
+	case actions_categories.ListCategoryAction:
+		return actions_categories.ListCategoryFields
+	case actions_transactions.ListTransactionAction:
+		return actions_transactions.ListTransactionFields
+	case actions_transactions.ListScheduledTransactionAction:
+		return actions_transactions.ListScheduledTransactionFields
+	}
+	return nil
+}
+
+// parseStage parses "sum", "count", "sort <field> [asc|desc]" or "export csv|json". fields are what sort accepts, any
+// name is accepted when they are nil.
+func parseStage(tokens []string, fields query.Fields) (stage actions_pipeline.Stage, suggestion AutoSuggestion) {
+	if len(tokens) == 0 {
+		return nil, makeMissingTokenSuggestion("stage after |", 0, StageTokens)
+	}
+
+	exact, possible := PossibleMatches(tokens[0], StageTokens)
+	if exact == nil {
+		return nil, makeUnknownTokenSuggestion("stage", 0, tokens[0], StageTokens, possible)
+	}
+
+	ctx := EmptyParseContext(tokens, nil)
+	next := []*TokenPattern{}
+
+	switch exact.Id {
+	case SUM:
+		stage = actions_pipeline.SumStage{}
+	case COUNT:
+		stage = actions_pipeline.CountStage{}
+	case SORT:
+		expected := fieldTokens(fields)
+		if fields == nil {
+			expected = []*TokenPattern{MakeArgToken(QUERY_FIELD, "field", ItemNamePattern)}
+		}
+		name, hasName := ctx.nextToken()
+		if !hasName {
+			return nil, makeMissingTokenSuggestion("field", ctx.currentTokenIndex, expected)
+		}
+		if exact, possible := PossibleMatches(name, expected); exact == nil {
+			return nil, makeUnknownTokenSuggestion("field", ctx.currentTokenIndex, name, expected, possible)
+		}
+		ctx.moveToNextToken()
+
+		sort := actions_pipeline.SortStage{Field: strings.ToLower(name)}
+		next = []*TokenPattern{allTokens[ASC], allTokens[DESC]}
+		if direction, hasDirection := ctx.nextToken(); hasDirection {
+			if exact, _ := PossibleMatches(direction, next); exact != nil {
+				sort.Desc = exact.Id == DESC
+				ctx.moveToNextToken()
+				next = []*TokenPattern{}
+			}
+		}
+		stage = sort
+	case EXPORT:
+		format, hasFormat := ctx.nextToken()
+		if !hasFormat {
+			return nil, makeMissingTokenSuggestion("format", ctx.currentTokenIndex, exportFormatTokens)
+		}
+		exact, possible := PossibleMatches(format, exportFormatTokens)
+		if exact == nil {
+			return nil, makeUnknownTokenSuggestion("format", ctx.currentTokenIndex, format, exportFormatTokens, possible)
+		}
+		ctx.moveToNextToken()
+		stage = actions_pipeline.ExportStage{Format: exact.DisplayName}
+	}
+
+	if extra, hasExtra := ctx.nextToken(); hasExtra {
+		return nil, makeUnknownTokenSuggestion("argument", ctx.currentTokenIndex, extra, next, []*TokenPattern{})
+	}
+	return stage, makeAutoSuggestion(true, "", next)
+}
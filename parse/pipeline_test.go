@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_pipeline "samvasta.com/bujit/actions/pipeline"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	assert.Equal(t, []segment{{"list account", 0, false}}, splitCommandLine("list account"))
+	assert.Equal(t, []segment{
+		{"new account a ", 0, false},
+		{` new account "b;c" `, 15, false},
+		{` sort name\|x`, 35, true},
+	}, splitCommandLine(`new account a ; new account "b;c" | sort name\|x`))
+}
+
+func TestPipeline(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	action, suggestion := ParseExpression("list account -c=food | sort balance desc | export csv", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, actions_pipeline.PipeAction{
+		Action: actions_pipeline.PipeAction{
+			Action: actions_accounts.ListAccountAction{CategoryName: "food", Session: &s},
+			Stage:  actions_pipeline.SortStage{Field: "balance", Desc: true},
+		},
+		Stage: actions_pipeline.ExportStage{Format: "csv"},
+	}, action)
+
+	action, suggestion = ParseExpression("new account cash; list account|count;", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, actions_pipeline.SequenceAction{Actions: []actions.Actioner{
+		actions_accounts.CreateAccountAction{Name: "cash", Session: &s},
+		actions_pipeline.PipeAction{Action: actions_accounts.ListAccountAction{Session: &s}, Stage: actions_pipeline.CountStage{}},
+	}}, action)
+
+	result, _ := action.Execute()
+	assert.True(t, result.IsSuccessful)
+	steps := result.Output.(actions_pipeline.SequenceOutput).Steps
+	assert.Equal(t, actions_pipeline.CountOutput{Count: 1, Kind: "account"}, steps[1].Result.Output)
+
+	// stages after a command that is not a list can sort by any name
+	action, _ = ParseExpression("version | sort anything", &s)
+	assert.Equal(t, actions_pipeline.SortStage{Field: "anything"}, action.(actions_pipeline.PipeAction).Stage)
+}
+
+func TestPipelineProblems(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, message string, start, end int) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &s)
+
+			assert.Nil(t, action)
+			assert.False(t, suggestion.IsValidAsIs)
+			assert.Equal(t, message, suggestion.Diagnostic.Message)
+			assert.Equal(t, start, suggestion.Diagnostic.Start)
+			assert.Equal(t, end, suggestion.Diagnostic.End)
+		}
+	}
+
+	t.Run("second command", testCase("list account; lst account", `unknown command "lst" — did you mean "list"?`, 14, 17))
+	t.Run("first command", testCase("list acount; list account", `unknown type "acount" — did you mean "account"?`, 5, 11))
+	t.Run("unknown stage", testCase("list account | total", `unknown stage "total"`, 15, 20))
+	t.Run("missing stage", testCase("list account |", "missing stage after |", 14, 14))
+	t.Run("unknown sort field", testCase("list account | sort balnce", `unknown field "balnce" — did you mean "balance"?`, 20, 26))
+	t.Run("missing export format", testCase("list account | export", "missing format", 21, 21))
+	t.Run("missing command", testCase("list account; | count", "missing command", 14, 14))
+	t.Run("extra argument", testCase("list account | count 3", `unknown argument "3"`, 21, 22))
+}
+
+func TestPipelineSuggestions(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	_, suggestion := ParseExpression("list account | ", &s)
+	assert.Equal(t, []string{"sum", "count", "sort", "export"}, suggestion.NextArgs)
+
+	_, suggestion = ParseExpression("list transaction | sort ", &s)
+	assert.Equal(t, []string{"date", "amount", "from", "to", "memo"}, suggestion.NextArgs)
+
+	_, suggestion = ParseExpression("list scheduled | sort ", &s)
+	assert.Equal(t, []string{"next", "amount", "from", "to", "memo"}, suggestion.NextArgs)
+
+	_, suggestion = ParseExpression("list account | sort name", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, []string{"asc", "desc"}, suggestion.NextArgs)
+
+	_, suggestion = ParseExpression("list account; new acc", &s)
+	assert.Equal(t, []string{"account"}, suggestion.NextArgs)
+
+	assert.Equal(t, []string{"count"}, Complete("list account |co", 16, &s).Completions)
+}
@@ -0,0 +1,363 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// queryKeywords start the parts of a query, in the order they must be given
+var queryKeywords = []*TokenPattern{allTokens[FILTER], allTokens[ORDER], allTokens[LIMIT]}
+
+func makeSymbolToken(symbol string) *TokenPattern {
+	return &TokenPattern{QUERY_SYMBOL, symbol, []*regexp.Regexp{regexp.MustCompile(regexp.QuoteMeta(symbol))}}
+}
+
+var openParenToken = makeSymbolToken("(")
+var closeParenToken = makeSymbolToken(")")
+var commaToken = makeSymbolToken(",")
+
+func fieldTokens(fields query.Fields) []*TokenPattern {
+	tokens := []*TokenPattern{}
+	for _, field := range fields {
+		tokens = append(tokens, MakeLiteralToken(QUERY_FIELD, field.Name))
+	}
+	return tokens
+}
+
+func opTokens(kind query.Kind) []*TokenPattern {
+	tokens := []*TokenPattern{}
+	for _, op := range query.OpsFor(kind) {
+		tokens = append(tokens, makeSymbolToken(string(op)))
+	}
+	return tokens
+}
+
+// queryArgKind is the kind of argument that values of a field are read as
+func queryArgKind(kind query.Kind) ArgKind {
+	switch kind {
+	case query.Money:
+		return MoneyArg
+	case query.Date:
+		return DateArg
+	default:
+		return TextArg
+	}
+}
+
+func queryValueName(kind query.Kind) string {
+	switch kind {
+	case query.Money:
+		return "<amount>"
+	case query.Date:
+		return "<date>"
+	default:
+		return "<text>"
+	}
+}
+
+// queryToken is one word of a query and the index of the input token it came from
+type queryToken struct {
+	text  string
+	index int
+}
+
+// queryOpPattern finds an operator written against a field or value, e.g. balance>100 or >=2026-10
+var queryOpPattern = regexp.MustCompile(`^([^<>=!~]*)(>=|<=|!=|!~|=|>|<|~)(.*)$`)
+
+// splitQueryTokens splits the tokens from start into the words of a query, so parentheses, commas and operators do not
+// need spaces around them. Parentheses that are part of an amount, such as (10+5)*2, are kept.
+func splitQueryTokens(tokens []string, start int, fields query.Fields) []queryToken {
+	words := []queryToken{}
+	for i := start; i < len(tokens); i++ {
+		token := tokens[i]
+
+		before := []string{}
+		for strings.HasPrefix(token, "(") && strings.Count(token, "(") > strings.Count(token, ")") {
+			before = append(before, "(")
+			token = token[1:]
+		}
+		after := []string{}
+		for strings.HasSuffix(token, ",") || (strings.HasSuffix(token, ")") && strings.Count(token, ")") > strings.Count(token, "(")) {
+			after = append([]string{token[len(token)-1:]}, after...)
+			token = token[:len(token)-1]
+		}
+		// A condition in parentheses, but not an amount in parentheses
+		for strings.HasPrefix(token, "(") && strings.HasSuffix(token, ")") && !isAmount(token) {
+			before, after = append(before, "("), append([]string{")"}, after...)
+			token = token[1 : len(token)-1]
+		}
+
+		middle := []string{token}
+		if match := queryOpPattern.FindStringSubmatch(token); match != nil {
+			// Only split after a field, so text values that contain an operator are kept whole
+			if _, isField := fields.Find(match[1]); isField || match[1] == "" {
+				middle = match[1:]
+			}
+		}
+
+		for _, word := range append(append(before, middle...), after...) {
+			if word != "" {
+				words = append(words, queryToken{word, i})
+			}
+		}
+	}
+	return words
+}
+
+func isAmount(value string) bool {
+	_, err := evaluateMoney(value)
+	return err == nil
+}
+
+type queryParser struct {
+	tokens  []queryToken
+	pos     int
+	end     int // index of the input token after the query, where anything missing is reported
+	fields  query.Fields
+	session *session.Session
+	depth   int             // how many parentheses are open
+	clauses []*TokenPattern // the keywords that may still start a part of the query
+	next    []*TokenPattern // what may follow what has been parsed so far, suggested when the query ends
+	preview string
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept moves past the next word when it is exactly one of the tokens
+func (p *queryParser) accept(tokens ...*TokenPattern) (*TokenPattern, bool) {
+	word, ok := p.peek()
+	if !ok {
+		return nil, false
+	}
+	if exact, _ := PossibleMatches(word.text, tokens); exact != nil {
+		p.pos++
+		return exact, true
+	}
+	return nil, false
+}
+
+// expect moves past the next word, which must be one of the expected tokens. what names what was expected.
+func (p *queryParser) expect(what string, expected []*TokenPattern) (*TokenPattern, queryToken, *AutoSuggestion) {
+	word, ok := p.peek()
+	if !ok {
+		suggestion := makeMissingTokenSuggestion(what, p.end, expected)
+		return nil, word, &suggestion
+	}
+
+	exact, possible := PossibleMatches(word.text, expected)
+	if exact == nil {
+		suggestion := makeUnknownTokenSuggestion(what, word.index, word.text, expected, possible)
+		return nil, word, &suggestion
+	}
+	p.pos++
+	return exact, word, nil
+}
+
+// afterCondition is what may follow a complete condition
+func (p *queryParser) afterCondition() []*TokenPattern {
+	next := []*TokenPattern{allTokens[AND], allTokens[OR]}
+	if p.depth > 0 {
+		return append(next, closeParenToken)
+	}
+	return append(next, p.clauses...)
+}
+
+// or parses conditions joined by "or", which binds less tightly than "and"
+func (p *queryParser) or() (query.Expr, *AutoSuggestion) {
+	left, problem := p.and()
+	for problem == nil {
+		if _, ok := p.accept(allTokens[OR]); !ok {
+			break
+		}
+		var right query.Expr
+		right, problem = p.and()
+		left = query.Or{Left: left, Right: right}
+	}
+	return left, problem
+}
+
+func (p *queryParser) and() (query.Expr, *AutoSuggestion) {
+	left, problem := p.condition()
+	for problem == nil {
+		if _, ok := p.accept(allTokens[AND]); !ok {
+			break
+		}
+		var right query.Expr
+		right, problem = p.condition()
+		left = query.And{Left: left, Right: right}
+	}
+	return left, problem
+}
+
+// condition parses "not <condition>", "(<conditions>)" or "<field> <op> <value>"
+func (p *queryParser) condition() (query.Expr, *AutoSuggestion) {
+	expected := append([]*TokenPattern{allTokens[NOT], openParenToken}, fieldTokens(p.fields)...)
+	token, word, problem := p.expect("field", expected)
+	if problem != nil {
+		return nil, problem
+	}
+
+	switch {
+	case token.Id == NOT:
+		expr, problem := p.condition()
+		return query.Not{Expr: expr}, problem
+	case token == openParenToken:
+		p.depth++
+		expr, problem := p.or()
+		if problem != nil {
+			return nil, problem
+		}
+		if _, _, problem := p.expect(")", p.afterCondition()); problem != nil {
+			return nil, problem
+		}
+		p.depth--
+		p.next = p.afterCondition()
+		return expr, nil
+	}
+
+	field, _ := p.fields.Find(word.text)
+	token, _, problem = p.expect("operator", opTokens(field.Kind))
+	if problem != nil {
+		return nil, problem
+	}
+
+	value, problem := p.value(field)
+	if problem != nil {
+		return nil, problem
+	}
+	p.next = p.afterCondition()
+	return query.Comparison{Field: field, Op: query.Op(token.DisplayName), Value: value}, nil
+}
+
+// value reads the next word as a value of the field: text, cents or days
+func (p *queryParser) value(field query.Field) (interface{}, *AutoSuggestion) {
+	name := queryValueName(field.Kind)
+	word, ok := p.peek()
+	if !ok {
+		return nil, &AutoSuggestion{false, "", []string{name}, &Diagnostic{TokenIndex: p.end, Expected: []string{name}, Message: "missing value for " + field.Name}, ""}
+	}
+	p.pos++
+
+	var value interface{} = word.text
+	var err error
+	switch field.Kind {
+	case query.Money:
+		var amount models.Money
+		amount, err = evaluateMoney(word.text)
+		value = int64(amount)
+	case query.Date:
+		value, err = util.ParseDate(word.text, util.Today())
+	}
+	if err != nil {
+		return nil, &AutoSuggestion{false, word.text, []string{}, &Diagnostic{TokenIndex: word.index, Expected: []string{name}, Message: fmt.Sprintf("invalid %s \"%s\": %v", field.Name, word.text, err)}, ""}
+	}
+
+	if p.pos == len(p.tokens) {
+		p.preview = queryArgKind(field.Kind).preview(p.session, word.text)
+	}
+	return value, nil
+}
+
+func (p *queryParser) order() ([]query.OrderBy, *AutoSuggestion) {
+	if _, _, problem := p.expect("keyword", []*TokenPattern{allTokens[BY]}); problem != nil {
+		return nil, problem
+	}
+
+	order := []query.OrderBy{}
+	for {
+		_, word, problem := p.expect("field", fieldTokens(p.fields))
+		if problem != nil {
+			return nil, problem
+		}
+		field, _ := p.fields.Find(word.text)
+		p.next = append([]*TokenPattern{allTokens[ASC], allTokens[DESC], commaToken}, p.clauses...)
+
+		direction, hasDirection := p.accept(allTokens[ASC], allTokens[DESC])
+		if hasDirection {
+			p.next = append([]*TokenPattern{commaToken}, p.clauses...)
+		}
+		order = append(order, query.OrderBy{Field: field, Desc: hasDirection && direction.Id == DESC})
+
+		if _, ok := p.accept(commaToken); !ok {
+			return order, nil
+		}
+	}
+}
+
+func (p *queryParser) limit() (int, *AutoSuggestion) {
+	word, ok := p.peek()
+	if !ok {
+		return 0, &AutoSuggestion{false, "", []string{"<n>"}, &Diagnostic{TokenIndex: p.end, Expected: []string{"<n>"}, Message: "missing value for limit"}, ""}
+	}
+	p.pos++
+
+	limit, err := strconv.Atoi(word.text)
+	if err != nil || limit < 1 {
+		return 0, &AutoSuggestion{false, word.text, []string{}, &Diagnostic{TokenIndex: word.index, Expected: []string{"<n>"}, Message: fmt.Sprintf("invalid limit \"%s\": expected a whole number above 0", word.text)}, ""}
+	}
+	p.next = []*TokenPattern{}
+	return limit, nil
+}
+
+// parseQuery parses the tokens from start as "[filter <conditions>] [order by <field> [asc|desc], ...] [limit <n>]".
+// Conditions compare a field to a value and may be joined with and, or, not and parentheses.
+func parseQuery(tokens []string, start int, fields query.Fields, s *session.Session) (q query.Query, suggestion AutoSuggestion) {
+	p := queryParser{tokens: splitQueryTokens(tokens, start, fields), end: len(tokens), fields: fields, session: s}
+	p.clauses = queryKeywords
+	p.next = queryKeywords
+
+	for {
+		if _, hasNext := p.peek(); !hasNext {
+			break
+		}
+
+		keyword, _, problem := p.expect("keyword", p.next)
+		if problem != nil {
+			return q, *problem
+		}
+
+		for i, clause := range queryKeywords {
+			if clause == keyword {
+				p.clauses = queryKeywords[i+1:]
+			}
+		}
+
+		switch keyword.Id {
+		case FILTER:
+			q.Filter, problem = p.or()
+		case ORDER:
+			q.Order, problem = p.order()
+		case LIMIT:
+			q.Limit, problem = p.limit()
+		}
+		if problem != nil {
+			return q, *problem
+		}
+	}
+
+	suggestion = makeAutoSuggestion(true, "", p.next)
+	suggestion.Preview = p.preview
+	return q, suggestion
+}
+
+// queryHelpLines describe the query that can follow a list command
+func queryHelpLines(fields query.Fields) []string {
+	return []string{
+		"filter <conditions>: only list what matches, e.g. filter balance > 100 and (category ~ \"food\" or name = cash). Text is compared with = != ~ (contains) and !~, amounts and dates with = != > >= < and <=. Conditions can be joined with and, or, not and parentheses.",
+		"order by <field> [asc|desc], ...: list in this order instead.",
+		"limit <n>: list at most this many.",
+		"fields: " + strings.Join(fields.Names(), ", "),
+	}
+}
@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_categories "samvasta.com/bujit/actions/categories"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+func field(fields query.Fields, name string) query.Field {
+	f, _ := fields.Find(name)
+	return f
+}
+
+func TestQuery(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	fields := actions_accounts.ListAccountFields
+
+	action, suggestion := ParseExpression(`list account filter balance > 100 and (category ~ "food" or name = cash) order by balance desc limit 10`, &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, query.Query{
+		Filter: query.And{
+			Left: query.Comparison{Field: field(fields, "balance"), Op: query.Greater, Value: int64(10000)},
+			Right: query.Or{
+				Left:  query.Comparison{Field: field(fields, "category"), Op: query.Contains, Value: "food"},
+				Right: query.Comparison{Field: field(fields, "name"), Op: query.Equal, Value: "cash"},
+			},
+		},
+		Order: []query.OrderBy{{Field: field(fields, "balance"), Desc: true}},
+		Limit: 10,
+	}, action.(actions_accounts.ListAccountAction).Query)
+
+	// operators, parentheses and commas do not need spaces
+	action, suggestion = ParseExpression("list account -c=credit filter not (balance>=0) order by category, name asc", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	listAction := action.(actions_accounts.ListAccountAction)
+	assert.Equal(t, "credit", listAction.CategoryName)
+	assert.Equal(t, query.Query{
+		Filter: query.Not{Expr: query.Comparison{Field: field(fields, "balance"), Op: query.GreaterOrEqual, Value: int64(0)}},
+		Order:  []query.OrderBy{{Field: field(fields, "category")}, {Field: field(fields, "name")}},
+	}, listAction.Query)
+
+	action, _ = ParseExpression("list transaction filter date = 2026-10 and amount <= 10*3 limit 5", &s)
+	transactionFields := actions_transactions.ListTransactionFields
+	october, _ := util.ParseDate("2026-10", util.Today())
+	assert.Equal(t, query.Query{
+		Filter: query.And{
+			Left:  query.Comparison{Field: field(transactionFields, "date"), Op: query.Equal, Value: october},
+			Right: query.Comparison{Field: field(transactionFields, "amount"), Op: query.LessOrEqual, Value: int64(3000)},
+		},
+		Limit: 5,
+	}, action.(actions_transactions.ListTransactionAction).Query)
+
+	action, _ = ParseExpression("list category order by name desc", &s)
+	assert.Equal(t, query.Query{Order: []query.OrderBy{{Field: field(actions_categories.ListCategoryFields, "name"), Desc: true}}}, action.(actions_categories.ListCategoryAction).Query)
+}
+
+func TestQueryProblems(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, message string, start int) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &s)
+
+			assert.Nil(t, action)
+			assert.False(t, suggestion.IsValidAsIs)
+			assert.Equal(t, message, suggestion.Diagnostic.Message)
+			assert.Equal(t, start, suggestion.Diagnostic.Start)
+		}
+	}
+
+	t.Run("unknown field", testCase("list account filter blance > 1", `unknown field "blance" — did you mean "balance"?`, 20))
+	t.Run("operator not for text", testCase("list account filter name > a", `unknown operator ">" — did you mean "="?`, 25))
+	t.Run("invalid amount", testCase("list account filter balance > abc", `invalid balance "abc": unexpected "a"`, 30))
+	t.Run("invalid date", testCase("list transaction filter date = someday", `invalid date "someday": not a date, try 2026-10-16, yesterday, last friday, -3d, 2026-10 or q3`, 31))
+	t.Run("missing value", testCase("list account filter name =", "missing value for name", 26))
+	t.Run("missing parenthesis", testCase("list account filter (name = a", "missing )", 29))
+	t.Run("missing by", testCase("list account order balance", `unknown keyword "balance"`, 19))
+	t.Run("invalid limit", testCase("list account limit 0", `invalid limit "0": expected a whole number above 0`, 19))
+	t.Run("clauses out of order", testCase("list account limit 3 filter name = a", `unknown keyword "filter"`, 21))
+}
+
+func TestQuerySuggestions(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, isValid bool, expected []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, suggestion := ParseExpression(input, &s)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+			assert.Equal(t, expected, suggestion.NextArgs)
+		}
+	}
+
+	t.Run("field", testCase("list account filter ", false, []string{"not", "(", "name", "description", "category", "balance", "created", "updated"}))
+	t.Run("partial field", testCase("list account filter bal", false, []string{"balance"}))
+	t.Run("operator", testCase("list account filter balance ", false, []string{"=", "!=", ">", ">=", "<", "<="}))
+	t.Run("value", testCase("list account filter balance >", false, []string{"<amount>"}))
+	t.Run("after condition", testCase("list account filter balance > 1", true, []string{"and", "or", "order", "limit"}))
+	t.Run("inside parentheses", testCase("list account filter (balance > 1", false, []string{"and", "or", ")"}))
+	t.Run("after order", testCase("list account order by name", true, []string{"asc", "desc", ",", "limit"}))
+	t.Run("after limit", testCase("list account limit 3", true, nil))
+
+	_, suggestion := ParseExpression("list account filter balance > 10*3", &s)
+	assert.Equal(t, "10*3 = 30.00 USD", suggestion.Preview)
+}
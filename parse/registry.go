@@ -0,0 +1,511 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/models/query"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// NoNoun is the noun of commands that are a single word, such as "source"
+const NoNoun = -1
+
+// ArgKind is the type of value an argument takes
+type ArgKind int
+
+const (
+	TextArg ArgKind = iota
+	MoneyArg
+	IntegerArg
+	PathArg
+	AccountArg  // name of an existing account
+	CategoryArg // fully qualified name of a category
+	DateArg     // a day or period, such as 2026-10-16, yesterday, last friday, -3d, 2026-10 or q3
+	MacroArg    // name of an existing alias or macro
+)
+
+func (kind ArgKind) pattern() *regexp.Regexp {
+	switch kind {
+	case MoneyArg:
+		return DecimalPattern
+	case IntegerArg:
+		return IntegerPattern
+	case PathArg:
+		return PathPattern
+	case CategoryArg:
+		return CategoryPathPattern
+	case DateArg:
+		return DatePattern
+	case MacroArg:
+		return MacroNamePattern
+	default:
+		return ItemNamePattern
+	}
+}
+
+// check finds problems with a value that its pattern cannot, such as an amount that divides by zero
+func (kind ArgKind) check(value string) error {
+	switch kind {
+	case MoneyArg:
+		_, err := evaluateMoney(value)
+		return err
+	case DateArg:
+		_, err := util.ParseDate(itemNameValue(value), util.Today())
+		return err
+	}
+	return nil
+}
+
+// preview shows what a value that is still being typed means, such as the result of an amount that is an expression.
+// Returns an empty string when there is nothing more to show than the value itself.
+func (kind ArgKind) preview(s *session.Session, value string) string {
+	switch {
+	case kind == MoneyArg && s != nil && isMoneyExpression(value):
+		return value + " = " + moneyValue(value).String(s)
+	case kind == DateArg:
+		dates, err := util.ParseDate(itemNameValue(value), util.Today())
+		if err != nil || dates.String() == value {
+			return ""
+		}
+		return itemNameValue(value) + " = " + dates.String()
+	}
+	return ""
+}
+
+// ArgSpec declares one argument of a command. Positional arguments are given by value, options as -s=<value> or
+// --long=<value>, and flags as -s or --long on their own.
+type ArgSpec struct {
+	Id           int
+	Short        string // single letter used as -s. Empty for positional arguments
+	Name         string // used as --name for options and flags, and as <name> for values
+	Kind         ArgKind
+	Help         string
+	IsPositional bool
+	IsFlag       bool
+	IsRequired   bool // positional arguments are required unless made with OptionalPositional
+	token        *TokenPattern
+}
+
+func Positional(id int, name string, kind ArgKind, help string) ArgSpec {
+	return ArgSpec{Id: id, Name: name, Kind: kind, Help: help, IsPositional: true, IsRequired: true, token: MakeArgToken(id, name, kind.pattern())}
+}
+
+// OptionalPositional is a positional argument that may be left out. It must come after every required one.
+func OptionalPositional(id int, name string, kind ArgKind, help string) ArgSpec {
+	arg := Positional(id, name, kind, help)
+	arg.IsRequired = false
+	return arg
+}
+
+func Option(id int, short, name string, kind ArgKind, help string) ArgSpec {
+	return ArgSpec{Id: id, Short: short, Name: name, Kind: kind, Help: help, token: MakeOptionalArgToken(id, short, name)}
+}
+
+func RequiredOption(id int, short, name string, kind ArgKind, help string) ArgSpec {
+	arg := Option(id, short, name, kind, help)
+	arg.IsRequired = true
+	return arg
+}
+
+func Flag(id int, short, name string, help string) ArgSpec {
+	return ArgSpec{Id: id, Short: short, Name: name, Help: help, IsFlag: true, token: makeFlagToken(id, short, name)}
+}
+
+func (arg ArgSpec) Token() *TokenPattern {
+	return arg.token
+}
+
+// Usage is how the argument is written in a syntax line, e.g. <name>, -d=<description> or -d
+func (arg ArgSpec) Usage() string {
+	switch {
+	case arg.IsPositional:
+		return arg.token.DisplayName
+	case arg.IsFlag:
+		return fmt.Sprintf("-%s", arg.Short)
+	default:
+		return fmt.Sprintf("-%s=<%s>", arg.Short, arg.Name)
+	}
+}
+
+// HelpLine describes the argument in a command's help
+func (arg ArgSpec) HelpLine() string {
+	if arg.IsPositional {
+		return fmt.Sprintf("%s: %s", arg.Name, arg.Help)
+	}
+	return fmt.Sprintf("%s (-%s or --%s): %s", arg.Name, arg.Short, arg.Name, arg.Help)
+}
+
+// ArgValues holds the raw value of every argument that was given, by argument id. Flags have an empty value.
+type ArgValues map[int]string
+
+func (values ArgValues) Has(id int) bool {
+	_, ok := values[id]
+	return ok
+}
+
+func (values ArgValues) Text(id int) string {
+	return itemNameValue(values[id])
+}
+
+func (values ArgValues) Money(id int) models.Money {
+	return moneyValue(values[id])
+}
+
+func (values ArgValues) Int(id int) int {
+	return intValue(values[id])
+}
+
+// Date is the days the value names, relative to today. It is empty when the value is not a date.
+func (values ArgValues) Date(id int) util.DateRange {
+	dates, _ := util.ParseDate(itemNameValue(values[id]), util.Today())
+	return dates
+}
+
+// Query is the filter, ordering and limit given after the arguments, with the fields of the command
+func (values ArgValues) Query(fields query.Fields) query.Query {
+	q, _ := parseQuery(Tokenize(values[ARG_QUERY]), 0, fields, nil)
+	return q
+}
+
+// CommandSpec declares everything needed to parse, suggest and describe one command.
+type CommandSpec struct {
+	Verb        int // token id of the first word
+	Noun        int // token id of the second word, or NoNoun
+	Title       string
+	Summary     string // one line shown in the general help
+	Description string
+	Args        []ArgSpec
+	Fields      query.Fields // what the command can be filtered and ordered by after its arguments. nil when it cannot
+	Action      func(session *session.Session, values ArgValues) actions.Actioner
+}
+
+var helpFlag = Flag(FLAG_HELP, "h", "help", "")
+
+func (spec *CommandSpec) arg(id int) (ArgSpec, bool) {
+	for _, arg := range spec.Args {
+		if arg.Id == id {
+			return arg, true
+		}
+	}
+	return ArgSpec{}, false
+}
+
+// Name is the words that start the command, e.g. "new account"
+func (spec *CommandSpec) Name() string {
+	name := allTokens[spec.Verb].DisplayName
+	if spec.Noun != NoNoun {
+		name += " " + allTokens[spec.Noun].DisplayName
+	}
+	return name
+}
+
+// Syntax is the command with every argument, optional ones in brackets
+func (spec *CommandSpec) Syntax() string {
+	syntax := spec.Name()
+	for _, arg := range spec.Args {
+		if arg.IsRequired {
+			syntax += " " + arg.Usage()
+		} else {
+			syntax += " [" + arg.Usage() + "]"
+		}
+	}
+	if spec.Fields != nil {
+		syntax += " [filter <conditions>] [order by <field>] [limit <n>]"
+	}
+	return syntax
+}
+
+func (spec *CommandSpec) HelpItems() []output.Helper {
+	group := output.EmptyOutputGroup().
+		Header(spec.Title).
+		HorizontalRule("═").
+		Header("Description").
+		Paragraph(spec.Description).
+		HorizontalRule("-").
+		Header("Syntax: " + spec.Syntax())
+
+	lines := []string{}
+	for _, arg := range spec.Args {
+		lines = append(lines, arg.HelpLine())
+	}
+	if spec.Fields != nil {
+		lines = append(lines, queryHelpLines(spec.Fields)...)
+	}
+	if len(lines) > 0 {
+		group.Indent().UnorderedList(lines, output.NormalBulletChar).Unindent()
+	}
+
+	return group.ToSlice()
+}
+
+// Registry is the set of commands the parser understands.
+type Registry struct {
+	commands []*CommandSpec
+}
+
+func NewRegistry(specs ...*CommandSpec) *Registry {
+	registry := &Registry{}
+	for _, spec := range specs {
+		registry.Register(spec)
+	}
+	return registry
+}
+
+func (registry *Registry) Register(spec *CommandSpec) {
+	registry.commands = append(registry.commands, spec)
+}
+
+func (registry *Registry) Commands() []*CommandSpec {
+	return registry.commands
+}
+
+// Verbs are the tokens that can start a command, in the order they were registered
+func (registry *Registry) Verbs() []*TokenPattern {
+	verbs := []*TokenPattern{}
+	seen := map[int]bool{}
+	for _, spec := range registry.commands {
+		if !seen[spec.Verb] {
+			seen[spec.Verb] = true
+			verbs = append(verbs, allTokens[spec.Verb])
+		}
+	}
+	return verbs
+}
+
+// Nouns are the tokens that can follow the verb
+func (registry *Registry) Nouns(verb int) []*TokenPattern {
+	nouns := []*TokenPattern{}
+	for _, spec := range registry.commands {
+		if spec.Verb == verb && spec.Noun != NoNoun {
+			nouns = append(nouns, allTokens[spec.Noun])
+		}
+	}
+	return nouns
+}
+
+func (registry *Registry) Find(verb, noun int) *CommandSpec {
+	for _, spec := range registry.commands {
+		if spec.Verb == verb && spec.Noun == noun {
+			return spec
+		}
+	}
+	return nil
+}
+
+// Match is the command that the tokens start with, or nil when they do not start one
+func (registry *Registry) Match(tokens []string) *CommandSpec {
+	if len(tokens) == 0 {
+		return nil
+	}
+	verb, _ := PossibleMatches(tokens[0], registry.Verbs())
+	if verb == nil {
+		return nil
+	}
+	if spec := registry.Find(verb.Id, NoNoun); spec != nil {
+		return spec
+	}
+	if len(tokens) < 2 {
+		return nil
+	}
+	noun, _ := PossibleMatches(tokens[1], registry.Nouns(verb.Id))
+	if noun == nil {
+		return nil
+	}
+	return registry.Find(verb.Id, noun.Id)
+}
+
+// parseVerb parses the rest of a command once its verb is known
+func (registry *Registry) parseVerb(context *ParseContext, verb int) (action actions.Actioner, suggestion AutoSuggestion) {
+	if spec := registry.Find(verb, NoNoun); spec != nil {
+		return parseCommand(&CommandContext{ParseContext: *context, spec: spec, values: ArgValues{}})
+	}
+
+	nouns := registry.Nouns(verb)
+	nextToken, hasNext := context.nextToken()
+
+	if hasNext {
+		exact, possible := PossibleMatches(nextToken, nouns)
+
+		if exact == nil {
+			return nil, makeUnknownTokenSuggestion("type", context.currentTokenIndex, nextToken, nouns, possible)
+		}
+
+		context.moveToNextToken()
+		return parseCommand(&CommandContext{ParseContext: *context, spec: registry.Find(verb, exact.Id), values: ArgValues{}})
+	}
+
+	return nil, makeMissingTokenSuggestion("type", context.currentTokenIndex, nouns)
+}
+
+type CommandContext struct {
+	ParseContext
+	spec   *CommandSpec
+	values ArgValues
+}
+
+// possibleNextTokens lists the positional arguments in order until they are all given, then any options that have not
+// been given yet, then the keywords that start a query. The help flag is only accepted before any other argument.
+func (ctx CommandContext) possibleNextTokens() []*TokenPattern {
+	tokens := []*TokenPattern{}
+
+	if arg, ok := ctx.nextPositional(); ok {
+		tokens = append(tokens, arg.token)
+	}
+
+	if len(tokens) == 0 {
+		for _, arg := range ctx.spec.Args {
+			if !arg.IsPositional && !ctx.values.Has(arg.Id) {
+				tokens = append(tokens, arg.token)
+			}
+		}
+		if ctx.spec.Fields != nil {
+			tokens = append(tokens, queryKeywords...)
+		}
+	}
+
+	if len(ctx.values) == 0 {
+		tokens = append(tokens, helpFlag.token)
+	}
+
+	return tokens
+}
+
+// acceptedTokens is what may come next. It is possibleNextTokens with the options that have not been given yet, which
+// may also come before the positional arguments, as in "source -k monthly.bujit".
+func (ctx CommandContext) acceptedTokens() []*TokenPattern {
+	tokens := ctx.possibleNextTokens()
+	if _, ok := ctx.nextPositional(); ok {
+		for _, arg := range ctx.spec.Args {
+			if !arg.IsPositional && !ctx.values.Has(arg.Id) {
+				tokens = append(tokens, arg.token)
+			}
+		}
+	}
+	return tokens
+}
+
+// nextPositional is the first positional argument that has not been given
+func (ctx CommandContext) nextPositional() (ArgSpec, bool) {
+	for _, arg := range ctx.spec.Args {
+		if arg.IsPositional && !ctx.values.Has(arg.Id) {
+			return arg, true
+		}
+	}
+	return ArgSpec{}, false
+}
+
+// missingArg is the first required argument that has not been given
+func (ctx CommandContext) missingArg() (ArgSpec, bool) {
+	for _, arg := range ctx.spec.Args {
+		if arg.IsRequired && !ctx.values.Has(arg.Id) {
+			return arg, true
+		}
+	}
+	return ArgSpec{}, false
+}
+
+func (ctx CommandContext) hasRequiredArgs() bool {
+	_, missing := ctx.missingArg()
+	return !missing
+}
+
+type validator interface {
+	IsValid() bool
+}
+
+func parseCommand(context *CommandContext) (action actions.Actioner, suggestion AutoSuggestion) {
+	nextToken, hasNext := context.nextToken()
+
+	missingTokens := context.possibleNextTokens()
+
+	if hasNext {
+		// An option joined to its value, e.g. -d=value, is matched by the option alone
+		name := nextToken
+		if option, _, ok := splitOption(nextToken); ok {
+			name = option
+		}
+
+		accepted := context.acceptedTokens()
+		exact, possible := PossibleMatches(name, accepted)
+
+		if exact == nil {
+			return nil, makeUnknownTokenSuggestion("argument", context.currentTokenIndex, strings.TrimSuffix(name, "="), accepted, possible)
+		}
+
+		if exact.Id == FLAG_HELP {
+			return actions.HelpAction{HelpItems: context.spec.HelpItems()}, EmptySuggestions
+		}
+
+		if exact.Id == FILTER || exact.Id == ORDER || exact.Id == LIMIT {
+			// The query is the rest of the input
+			_, querySuggestion := parseQuery(context.tokens, context.currentTokenIndex, context.spec.Fields, context.session)
+			if !querySuggestion.IsValidAsIs {
+				return nil, querySuggestion
+			}
+			context.values[ARG_QUERY] = JoinTokens(context.tokens[context.currentTokenIndex:])
+			context.currentTokenIndex = len(context.tokens)
+
+			action, suggestion = parseCommand(context)
+			suggestion.NextArgs, suggestion.Preview = querySuggestion.NextArgs, querySuggestion.Preview
+			return action, suggestion
+		}
+
+		arg, _ := context.spec.arg(exact.Id)
+		switch {
+		case arg.IsPositional:
+			context.values[arg.Id] = nextToken
+			context.moveToNextToken()
+		case arg.IsFlag:
+			context.values[arg.Id] = ""
+			context.moveToNextToken()
+		default:
+			value, suggestion := parseOptionalArg(&context.ParseContext, arg.token, arg.Kind.pattern(), arg.Name)
+			if !suggestion.IsValidAsIs {
+				if _, hasValue := context.nextToken(); !hasValue {
+					suggestion.NextArgs = append(arg.Kind.knownValues(context.session, ""), suggestion.NextArgs...)
+				}
+				return nil, suggestion
+			}
+			context.values[arg.Id] = value
+		}
+
+		value := context.values[arg.Id]
+		if err := arg.Kind.check(value); err != nil {
+			return nil, AutoSuggestion{false, value, []string{}, &Diagnostic{TokenIndex: context.currentTokenIndex - 1, Expected: []string{"<" + arg.Name + ">"}, Message: fmt.Sprintf("invalid %s \"%s\": %v", arg.Name, value, err)}, ""}
+		}
+
+		_, hasMore := context.nextToken()
+		action, suggestion = parseCommand(context)
+		if !hasMore && !arg.IsFlag {
+			// The value is the last thing typed, so it may only be the start of a longer known value
+			suggestion.NextArgs = append(arg.Kind.knownValues(context.session, value), suggestion.NextArgs...)
+			suggestion.Preview = arg.Kind.preview(context.session, value)
+		}
+		return action, suggestion
+	}
+
+	if context.hasRequiredArgs() {
+		action := context.spec.Action(context.session, context.values)
+		if v, ok := action.(validator); !ok || v.IsValid() {
+			return action, makeAutoSuggestion(true, nextToken, missingTokens)
+		}
+	}
+
+	if arg, missing := context.missingArg(); missing {
+		suggestion = makeMissingTokenSuggestion("value for "+arg.Usage(), context.currentTokenIndex, missingTokens)
+	} else {
+		// Every argument was given but the action rejected them
+		suggestion = makeAutoSuggestion(false, "", missingTokens)
+		suggestion.Diagnostic = &Diagnostic{TokenIndex: 0, Expected: []string{context.spec.Syntax()}, Message: "invalid arguments for " + context.spec.Name()}
+	}
+	if arg, ok := context.nextPositional(); ok {
+		suggestion.NextArgs = append(arg.Kind.knownValues(context.session, ""), suggestion.NextArgs...)
+	}
+	return nil, suggestion
+}
@@ -0,0 +1,144 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+type testAction struct {
+	Name  string
+	Count int
+	Loud  bool
+}
+
+func (action testAction) Execute() (actions.ActionResult, []*actions.Consequence) {
+	return actions.ActionResult{Output: action.Name, IsSuccessful: true}, []*actions.Consequence{}
+}
+
+func (action testAction) IsValid() bool {
+	return action.Count >= 0
+}
+
+var testCommand = &CommandSpec{
+	Verb:        PRINT,
+	Noun:        ACCOUNT,
+	Title:       "Print Account Command",
+	Summary:     "Prints an account.",
+	Description: "Prints an account a number of times.",
+	Args: []ArgSpec{
+		Positional(ARG_NAME, "name", TextArg, "account to print."),
+		Option(ARG_LIMIT, "l", "limit", IntegerArg, "how many times."),
+		Flag(FLAG_HARD, "d", "hard", "print loudly."),
+	},
+	Action: func(session *session.Session, values ArgValues) actions.Actioner {
+		return testAction{Name: values.Text(ARG_NAME), Count: values.Int(ARG_LIMIT), Loud: values.Has(FLAG_HARD)}
+	},
+}
+
+func TestCommandSpec(t *testing.T) {
+	registry := NewRegistry(testCommand)
+
+	testCase := func(tokens []string, isValid bool, expectedSuggestions []string, expected actions.Actioner) func(t *testing.T) {
+		return func(t *testing.T) {
+			context := EmptyParseContext(tokens, nil)
+			action, suggestion := registry.parseVerb(&context, PRINT)
+
+			assert.Equal(t, isValid, suggestion.IsValidAsIs)
+			assert.ElementsMatch(t, expectedSuggestions, suggestion.NextArgs)
+			assert.Equal(t, expected, action)
+		}
+	}
+
+	t.Run("noun", testCase([]string{"print"}, false, []string{"account"}, nil))
+	t.Run("positional", testCase([]string{"print", "account"}, false, []string{"<name>", "--help"}, nil))
+	t.Run("options after positional", testCase([]string{"print", "account", "cash"}, true, []string{"--limit", "--hard"}, testAction{Name: "cash"}))
+	t.Run("options before positional", testCase([]string{"print", "account", "-d", "-l=", "3", "cash"}, true, []string{}, testAction{Name: "cash", Count: 3, Loud: true}))
+	t.Run("all args", testCase([]string{"print", "account", "cash", "-d", "-l=", "3"}, true, []string{}, testAction{Name: "cash", Count: 3, Loud: true}))
+	t.Run("missing option value", testCase([]string{"print", "account", "cash", "-l="}, false, []string{"<limit>"}, nil))
+	t.Run("invalid action", testCase([]string{"print", "account", "cash", "-l=", "-1"}, false, []string{"--hard"}, nil))
+}
+
+func TestCommandSpecHelp(t *testing.T) {
+	assert.Equal(t, "print account <name> [-l=<limit>] [-d]", testCommand.Syntax())
+	assert.Equal(t, "limit (-l or --limit): how many times.", testCommand.HelpItems()[6].(output.UnorderedList).Items[1].Text)
+
+	registry := NewRegistry(testCommand)
+	context := EmptyParseContext([]string{"print", "account", "--help"}, nil)
+	action, _ := registry.parseVerb(&context, PRINT)
+
+	assert.Equal(t, actions.HelpAction{HelpItems: testCommand.HelpItems()}, action)
+}
+
+func TestGeneralHelp(t *testing.T) {
+	helpers := GeneralHelpItems(NewRegistry(testCommand), false)
+
+	table := helpers[3].(output.Table)
+	assert.Equal(t, []output.TableCell{output.Cell("print account"), output.Cell("Prints an account.")}, table.Rows[0])
+	assert.Equal(t, "alias <name> = <command>", table.Rows[1][0].Text)
+	assert.Equal(t, "macro <name> $1 $2 = <command>", table.Rows[2][0].Text)
+	assert.Equal(t, "help", table.Rows[3][0].Text)
+
+	verbose := GeneralHelpItems(NewRegistry(testCommand), true)
+	assert.Greater(t, len(verbose), len(helpers))
+}
+
+func TestRegisteredCommands(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	s.Db.Create(&models.Account{Name: "cash", IsActive: true})
+
+	action, suggestion := ParseExpression("delete account cash --hard", &s)
+	assert.True(t, suggestion.IsValidAsIs)
+	assert.Equal(t, actions_accounts.DeleteAccountAction{Name: "cash", IsHardDelete: true, Session: &s}, action)
+
+	action, _ = ParseExpression("exit", &s)
+	assert.Equal(t, actions.ExitAction{}, action)
+
+	action, _ = ParseExpression("version", &s)
+	assert.Equal(t, actions.VersionAction{}, action)
+
+	for _, spec := range Commands.Commands() {
+		assert.NotEmpty(t, spec.Summary, spec.Name())
+		assert.NotNil(t, spec.Action, spec.Name())
+		assert.Equal(t, spec, Commands.Find(spec.Verb, spec.Noun), spec.Name())
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	session := session.InMemorySession(models.MigrateSchema)
+
+	testCase := func(input string, expectedIndex, expectedStart, expectedEnd int, expectedMessage string) func(t *testing.T) {
+		return func(t *testing.T) {
+			action, suggestion := ParseExpression(input, &session)
+
+			assert.Nil(t, action)
+			if assert.NotNil(t, suggestion.Diagnostic) {
+				assert.Equal(t, expectedIndex, suggestion.Diagnostic.TokenIndex)
+				assert.Equal(t, expectedStart, suggestion.Diagnostic.Start)
+				assert.Equal(t, expectedEnd, suggestion.Diagnostic.End)
+				assert.Equal(t, expectedMessage, suggestion.Diagnostic.Message)
+				assert.NotEmpty(t, suggestion.Diagnostic.Expected)
+			}
+		}
+	}
+
+	t.Run("Misspelled command", testCase("lsit account", 0, 0, 4, `unknown command "lsit" — did you mean "list"?`))
+	t.Run("Misspelled type", testCase("list acount", 1, 5, 11, `unknown type "acount" — did you mean "account"?`))
+	t.Run("Nothing close", testCase("qqqqqq account", 0, 0, 6, `unknown command "qqqqqq"`))
+	t.Run("Missing command", testCase("", 0, 0, 0, "missing command"))
+	t.Run("Missing type", testCase("list ", 1, 5, 5, "missing type"))
+	t.Run("Missing positional", testCase("new account", 2, 11, 11, "missing value for <name>"))
+	t.Run("Unknown argument", testCase("new account cash --bogus", 3, 17, 24, `unknown argument "--bogus" — did you mean "--balance"?`))
+	t.Run("Missing option value", testCase("report forecast --account=", 3, 26, 26, "missing value for --account"))
+	t.Run("Unknown format", testCase("list account --format=pdf", 2, 13, 25, `unknown format "pdf" — expected md or html`))
+	t.Run("Empty format", testCase(`list account --format="" --help`, 3, 22, 24, `unknown format "" — expected md or html`))
+	t.Run("After format arg", testCase("list --format=md acount", 2, 17, 23, `unknown type "acount" — did you mean "account"?`))
+	t.Run("Quoted token", testCase(`list "acc ount"`, 1, 5, 15, `unknown type "acc ount" — did you mean "account"?`))
+	t.Run("Unterminated quote", testCase(`new account "cash`, 2, 12, 17, `missing closing quote "`))
+	t.Run("Rejected arguments", testCase("new transaction 0", 0, 0, 3, "invalid arguments for new transaction"))
+}
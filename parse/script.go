@@ -0,0 +1,171 @@
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"samvasta.com/bujit/actions"
+	"samvasta.com/bujit/models/output"
+	"samvasta.com/bujit/session"
+)
+
+// ScriptLine is the outcome of one command in a script.
+type ScriptLine struct {
+	Number       int // line number in the script, starting at 1
+	Command      string
+	Parsed       bool
+	Problem      string // why the command could not be parsed, empty when it was
+	Column       int    // where the problem is in the line, counting characters from 1
+	Result       actions.ActionResult
+	Consequences []*actions.Consequence
+}
+
+// Location is where the line's problem is, as line:column
+func (line ScriptLine) Location() string {
+	return fmt.Sprintf("%d:%d", line.Number, line.Column)
+}
+
+func (line ScriptLine) IsSuccessful() bool {
+	return line.Parsed && line.Result.IsSuccessful
+}
+
+func (line ScriptLine) Status() string {
+	if !line.Parsed {
+		return "invalid"
+	} else if !line.Result.IsSuccessful {
+		return "failed"
+	}
+	return "ok"
+}
+
+type ScriptResult struct {
+	Lines   []ScriptLine
+	Stopped bool // true when a failure stopped the script before the end
+}
+
+func (result ScriptResult) IsSuccessful() bool {
+	for _, line := range result.Lines {
+		if !line.IsSuccessful() {
+			return false
+		}
+	}
+	return true
+}
+
+// Consequences collects the consequences of every line, in order.
+func (result ScriptResult) Consequences() []*actions.Consequence {
+	consequences := []*actions.Consequence{}
+	for _, line := range result.Lines {
+		consequences = append(consequences, line.Consequences...)
+	}
+	return consequences
+}
+
+// Summary is a table with the status of each line that ran.
+func (result ScriptResult) Summary() []output.Helper {
+	rows := make([][]output.TableCell, len(result.Lines))
+	failed := 0
+	for i, line := range result.Lines {
+		status := output.ColoredCell(line.Status(), output.Success)
+		if !line.IsSuccessful() {
+			status.Color = output.Error
+			failed++
+		}
+		rows[i] = []output.TableCell{output.Cell(strconv.Itoa(line.Number)), output.Cell(line.Command), status}
+	}
+
+	columns := []output.TableColumn{
+		{Header: "Line", Kind: output.TextColumn, Align: output.AlignRight},
+		output.MakeColumn("Command", output.TextColumn),
+		output.MakeColumn("Status", output.TextColumn),
+	}
+
+	message := fmt.Sprintf("Ran %d commands, %d failed", len(result.Lines), failed)
+	if result.Stopped {
+		message += fmt.Sprintf(", stopped at line %d", result.Lines[len(result.Lines)-1].Number)
+	}
+
+	return output.EmptyOutputGroup().
+		Table(columns, rows).
+		Paragraph(message).
+		ToSlice()
+}
+
+// ScriptExecutor runs a script with one command per line. Blank lines and everything after a # that is not inside
+// quotes are ignored.
+type ScriptExecutor struct {
+	Session         *session.Session
+	ContinueOnError bool
+	OnLine          func(line ScriptLine) // called after each command runs, may be nil
+}
+
+func (executor ScriptExecutor) Execute(reader io.Reader) (ScriptResult, error) {
+	result := ScriptResult{Lines: []ScriptLine{}}
+
+	scanner := bufio.NewScanner(reader)
+	number := 0
+	for scanner.Scan() {
+		number++
+
+		text := stripComment(scanner.Text())
+		command := strings.TrimSpace(text)
+		if command == "" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+
+		line := ScriptLine{Number: number, Command: command}
+		action, suggestion := ParseExpression(command, executor.Session)
+		if action != nil {
+			line.Parsed = true
+			line.Result, line.Consequences = action.Execute()
+		} else if suggestion.Diagnostic != nil {
+			line.Problem = suggestion.Diagnostic.Message
+			line.Column = utf8.RuneCountInString(text[:indent+suggestion.Diagnostic.Start]) + 1
+		} else {
+			line.Problem = suggestion.Problem(command)
+			line.Column = indent + 1
+		}
+
+		result.Lines = append(result.Lines, line)
+		if executor.OnLine != nil {
+			executor.OnLine(line)
+		}
+
+		if !line.IsSuccessful() && !executor.ContinueOnError {
+			result.Stopped = true
+			break
+		}
+	}
+
+	return result, scanner.Err()
+}
+
+// stripComment removes a trailing # comment from a line, leaving any # inside quotes or escaped with a backslash alone
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quote != '\'':
+			// quoting follows the same rules as TokenizeWithSpans
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
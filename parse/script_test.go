@@ -0,0 +1,78 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+const testScript = `# set up the ledger
+new account cash -b=10
+
+new account "savings" # trailing comment
+lsit account
+new account checking
+`
+
+func TestScriptExecutor(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	seen := []int{}
+	executor := ScriptExecutor{Session: &s, OnLine: func(line ScriptLine) { seen = append(seen, line.Number) }}
+
+	result, err := executor.Execute(strings.NewReader(testScript))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsSuccessful())
+	assert.True(t, result.Stopped)
+	assert.Equal(t, []int{2, 4, 5}, seen)
+
+	assert.Len(t, result.Lines, 3)
+	assert.Equal(t, "new account cash -b=10", result.Lines[0].Command)
+	assert.Equal(t, `new account "savings"`, result.Lines[1].Command)
+	assert.Equal(t, "ok", result.Lines[1].Status())
+	assert.Equal(t, "invalid", result.Lines[2].Status())
+	assert.Len(t, result.Consequences(), 2)
+
+	var count int64
+	s.Db.Model(&models.Account{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestScriptExecutorContinueOnError(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	result, err := ScriptExecutor{Session: &s, ContinueOnError: true}.Execute(strings.NewReader(testScript))
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsSuccessful())
+	assert.False(t, result.Stopped)
+	assert.Len(t, result.Lines, 4)
+	assert.Equal(t, 6, result.Lines[3].Number)
+	assert.True(t, result.Lines[3].IsSuccessful())
+}
+
+func TestScriptExecutorLocation(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	result, err := ScriptExecutor{Session: &s, ContinueOnError: true}.Execute(strings.NewReader("new account cash\n  list acount # typo\n\tnew account\n"))
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Lines, 3)
+	assert.Equal(t, "2:8", result.Lines[1].Location())
+	assert.Equal(t, `unknown type "acount" — did you mean "account"?`, result.Lines[1].Problem)
+	assert.Equal(t, "3:13", result.Lines[2].Location())
+	assert.Equal(t, "missing value for <name>", result.Lines[2].Problem)
+}
+
+func TestStripComment(t *testing.T) {
+	assert.Equal(t, "list account ", stripComment("list account # everything"))
+	assert.Equal(t, `new account "a#b" `, stripComment(`new account "a#b" # quoted`))
+	assert.Equal(t, "", stripComment("# only a comment"))
+	assert.Equal(t, "help", stripComment("help"))
+	assert.Equal(t, `new account "say \"#1\"" `, stripComment(`new account "say \"#1\"" # escaped quotes`))
+	assert.Equal(t, `new account no\#1 `, stripComment(`new account no\#1 # escaped hash`))
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"samvasta.com/bujit/models"
 )
@@ -65,36 +66,18 @@ func makeFlagToken(id int, shortName, longName string) *TokenPattern {
 	return &TokenPattern{id, displayName, patterns}
 }
 
-var TokenizePattern = regexp.MustCompile(`[^\s"'=]+=?|"([^"]*)"|'([^']*)'`)
-
-func Tokenize(input string) (tokens []string) {
-	matches := TokenizePattern.FindAllStringSubmatch(input, -1)
-	for _, match := range matches {
-		if match[2] != "" {
-			tokens = append(tokens, match[2])
-		} else if match[1] != "" {
-			tokens = append(tokens, match[1])
-		} else {
-			tokens = append(tokens, match[0])
-		}
-	}
-	return tokens
-}
-
+// LengthOfMatch is the length in bytes of the longest common prefix of a and b. Characters are compared whole and
+// case matters, so the result is always the end of a character in both strings.
 func LengthOfMatch(a, b string) int {
-	var minLen int
-	if len(a) < len(b) {
-		minLen = len(a)
-	} else {
-		minLen = len(b)
-	}
-
-	for i := 0; i < minLen; i++ {
-		if a[i] != b[i] {
-			return i
+	length := 0
+	rb := []rune(b)
+	for i, r := range []rune(a) {
+		if i >= len(rb) || r != rb[i] {
+			break
 		}
+		length += utf8.RuneLen(r)
 	}
-	return minLen
+	return length
 }
 
 func (tok *TokenPattern) BestMatch(test string) string {
@@ -135,28 +118,155 @@ func (tok *TokenPattern) BestMatch(test string) string {
 	return bestSoFar
 }
 
-func PossibleMatches(test string, possibleTokens []*TokenPattern) (exactMatch *TokenPattern, possibleMatches []*TokenPattern) {
+// Match quality tiers, best first
+const (
+	prefixMatch = iota
+	subsequenceMatch
+	editDistanceMatch
+	noMatch
+)
 
-	// Copy possible tokens
-	var matchLens map[int]int = make(map[int]int, len(possibleTokens))
+// matchScore ranks how well some input matches a token. Lower is better.
+type matchScore struct {
+	tier      int
+	closeness int // within a tier, lower is closer
+}
 
-	//Remove bad tokens & store match lengths for sorting
-	for i, tok := range possibleTokens {
-		isTokenAlreadyAdded := false
-		for _, pattern := range tok.Patterns {
-			prefix, isComplete := pattern.LiteralPrefix()
+func (a matchScore) isBetterThan(b matchScore) bool {
+	return a.tier < b.tier || (a.tier == b.tier && a.closeness < b.closeness)
+}
+
+// scoreLiteral compares input to a literal token, ignoring case. Inputs that the literal starts with, or that share
+// more than their first character with it, score best, then inputs whose characters all appear in order in the
+// literal, then inputs with a small number of typos.
+func scoreLiteral(test, literal string, isComplete bool) matchScore {
+	lowerTest, lowerLiteral := []rune(strings.ToLower(test)), []rune(strings.ToLower(literal))
+	if len(lowerTest) == 0 {
+		return matchScore{noMatch, 0}
+	}
+
+	shared := sharedPrefix(lowerTest, lowerLiteral)
+	if (shared == len(lowerTest) || shared > 1) && !(isComplete && len(lowerLiteral) < len(lowerTest)) {
+		return matchScore{prefixMatch, -shared}
+	}
+
+	if len(lowerTest) > 1 && isSubsequence(lowerTest, lowerLiteral) {
+		return matchScore{subsequenceMatch, len(lowerLiteral) - len(lowerTest)}
+	}
+
+	if distance := EditDistance(string(lowerTest), string(lowerLiteral)); distance <= maxTypos(len(lowerTest)) {
+		return matchScore{editDistanceMatch, distance}
+	}
+
+	return matchScore{noMatch, 0}
+}
+
+// sharedPrefix is how many characters a and b start with in common
+func sharedPrefix(a, b []rune) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// maxTypos is how many edits an input of this many characters may be away from a token and still be suggested
+func maxTypos(length int) int {
+	if length <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// isSubsequence is true when every character of test appears in s in the same order
+func isSubsequence(test, s []rune) bool {
+	i := 0
+	for _, c := range s {
+		if i < len(test) && test[i] == c {
+			i++
+		}
+	}
+	return i == len(test)
+}
 
-			if isComplete && len(prefix) < len(test) {
-				continue
+// EditDistance is the number of single character insertions, deletions, substitutions or swaps of neighbouring
+// characters needed to turn a into b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// rows i-2, i-1 and i of the distance table
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
 			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
+
+// score finds how well the input matches the closest of the token's literal patterns, and that literal
+func (tok *TokenPattern) score(test string) (best matchScore, literal string) {
+	best = matchScore{noMatch, 0}
+	for _, pattern := range tok.Patterns {
+		prefix, isComplete := pattern.LiteralPrefix()
+		if len(prefix) == 0 {
+			continue
+		}
+		score := scoreLiteral(test, prefix, isComplete)
+		if score.isBetterThan(best) || (score == best && EditDistance(test, prefix) < EditDistance(test, literal)) {
+			// equally close literals are told apart by case, so "lsit" suggests "list" rather than "LIST"
+			best, literal = score, prefix
+		}
+	}
+	return best, literal
+}
+
+// ClosestLiteral is the literal form of the token that is most like the input, or the display name when nothing is
+// alike.
+func (tok *TokenPattern) ClosestLiteral(test string) string {
+	if score, literal := tok.score(test); score.tier != noMatch {
+		return literal
+	}
+	return tok.DisplayName
+}
+
+// PossibleMatches finds the token that the input matches exactly, if any, and every token the input could be a partial or
+// misspelled form of. Possible matches are ordered from closest to furthest; tokens that are equally close keep their
+// original order.
+func PossibleMatches(test string, possibleTokens []*TokenPattern) (exactMatch *TokenPattern, possibleMatches []*TokenPattern) {
+	scores := map[*TokenPattern]matchScore{}
+
+	for _, tok := range possibleTokens {
+		for _, pattern := range tok.Patterns {
+			prefix, _ := pattern.LiteralPrefix()
 
 			if len(prefix) > 0 {
 				lenOfMatch := LengthOfMatch(test, prefix)
-				matchLens[i] = lenOfMatch
-				if !isTokenAlreadyAdded && lenOfMatch > 0 {
-					possibleMatches = append(possibleMatches, tok)
-					isTokenAlreadyAdded = true
-				}
 				if lenOfMatch == len(test) && len(test) == len(prefix) {
 					// The token must be 100% literal and be an exact match of the test str
 					exactMatch = tok
@@ -169,32 +279,47 @@ func PossibleMatches(test string, possibleTokens []*TokenPattern) (exactMatch *T
 					}
 				}
 			} else if pattern.MatchString(test) {
-				matchLens[i] = 0
 				exactMatch = tok
 			}
 		}
+
+		if score, _ := tok.score(test); score.tier != noMatch {
+			scores[tok] = score
+			possibleMatches = append(possibleMatches, tok)
+		}
 	}
 
-	// Sort by match length
-	sort.Slice(possibleMatches, func(i, j int) bool {
-		return matchLens[i] < matchLens[j]
+	sort.SliceStable(possibleMatches, func(i, j int) bool {
+		return scores[possibleMatches[i]].isBetterThan(scores[possibleMatches[j]])
 	})
 
 	return exactMatch, possibleMatches
 }
 
+// itemNameValue removes one pair of quotes around the name, if it has them, so quotes inside the name are kept
 func itemNameValue(tokenStr string) string {
-	return strings.Trim(tokenStr, `'"`)
+	if len(tokenStr) >= 2 && strings.ContainsAny(tokenStr[:1], `'"`) && tokenStr[0] == tokenStr[len(tokenStr)-1] {
+		return tokenStr[1 : len(tokenStr)-1]
+	}
+	return tokenStr
 }
 
+// moneyValue evaluates an amount or arithmetic expression of amounts. Values that cannot be evaluated are 0.
 func moneyValue(tokenStr string) models.Money {
-	submatches := DecimalPattern.FindStringSubmatch(tokenStr)
+	value, err := evaluateMoney(tokenStr)
+	if err != nil {
+		return models.MakeMoney(0)
+	}
+	return value
+}
+
+func intValue(tokenStr string) int {
+	submatches := IntegerPattern.FindStringSubmatch(tokenStr)
 
 	if len(submatches) > 1 {
-		//Guaranteed to never get a parsing error because if submatch exists then the value must fit the float pattern
-		value, _ := strconv.ParseFloat(submatches[1], 64)
-		return models.MakeMoney(value)
+		value, _ := strconv.Atoi(submatches[1])
+		return value
 	}
 
-	return models.MakeMoney(0)
+	return 0
 }
@@ -198,6 +198,8 @@ func TestLengthOfMatch(t *testing.T) {
 	t.Run("Full match on str1", testCase("ABC", "ABCdef", 3))
 	t.Run("Empty strings", testCase("", "", 0))
 	t.Run("Full Match", testCase("ABCDEF", "ABCDEF", 6))
+	t.Run("Multibyte characters", testCase("Café", "Cafè", 3))
+	t.Run("Multibyte match", testCase("Café", "Café au lait", 5))
 }
 
 func TestPossibleMatches(t *testing.T) {
@@ -306,6 +308,94 @@ func TestPossibleMatchesNoDuplicates(t *testing.T) {
 	assert.Contains(t, possible, tokens[0])
 }
 
+func TestPossibleMatchesFuzzy(t *testing.T) {
+	tokens := []*TokenPattern{
+		MakeLiteralToken(1, "new"),
+		MakeLiteralToken(2, "list"),
+		MakeLiteralToken(3, "delete"),
+		MakeLiteralToken(4, "forecast"),
+	}
+
+	testCase := func(input string, expected *TokenPattern) func(t *testing.T) {
+		return func(t *testing.T) {
+			exact, possible := PossibleMatches(input, tokens)
+
+			assert.Nil(t, exact)
+			if assert.NotEmpty(t, possible) {
+				assert.Same(t, expected, possible[0])
+			}
+		}
+	}
+
+	t.Run("Swapped letters", testCase("lsit", tokens[1]))
+	t.Run("Missing letter", testCase("delte", tokens[2]))
+	t.Run("Wrong letter", testCase("nex", tokens[0]))
+	t.Run("Subsequence", testCase("frcst", tokens[3]))
+	t.Run("Too many typos", func(t *testing.T) {
+		_, possible := PossibleMatches("xyzw", tokens)
+		assert.Empty(t, possible)
+	})
+}
+
+func TestPossibleMatchesRanking(t *testing.T) {
+	tokens := []*TokenPattern{
+		MakeLiteralToken(1, "last"),
+		MakeLiteralToken(2, "list"),
+		MakeLiteralToken(3, "lint"),
+	}
+
+	_, possible := PossibleMatches("lis", tokens)
+
+	// longest prefix first. Only the first letter of last is shared, which is not enough to suggest it
+	assert.Equal(t, []*TokenPattern{tokens[1], tokens[2]}, possible)
+
+	_, possible = PossibleMatches("LIs", tokens)
+	assert.Equal(t, []*TokenPattern{tokens[1], tokens[2]}, possible)
+
+	_, possible = PossibleMatches("l", tokens)
+	assert.Equal(t, tokens, possible)
+}
+
+func TestScoreLiteral(t *testing.T) {
+	testCase := func(test, literal string, expected matchScore) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, scoreLiteral(test, literal, true))
+		}
+	}
+
+	t.Run("Prefix", testCase("acc", "account", matchScore{prefixMatch, -3}))
+	t.Run("Prefix in another case", testCase("ACC", "account", matchScore{prefixMatch, -3}))
+	t.Run("Only the first letter", testCase("axyz", "account", matchScore{noMatch, 0}))
+	t.Run("Accented prefix", testCase("caf", "Café", matchScore{prefixMatch, -3}))
+	t.Run("Accented letters that share a first byte", testCase("è", "é", matchScore{editDistanceMatch, 1}))
+	t.Run("Accented subsequence", testCase("mkt", "Müllerkonto", matchScore{subsequenceMatch, 8}))
+	t.Run("Subsequence in another case", testCase("FRCST", "forecast", matchScore{subsequenceMatch, 3}))
+}
+
+func TestClosestLiteral(t *testing.T) {
+	token := MakeLiteralToken(1, "account", "wallet")
+
+	assert.Equal(t, "account", token.ClosestLiteral("acount"))
+	assert.Equal(t, "wallet", token.ClosestLiteral("walet"))
+	assert.Equal(t, "account", token.ClosestLiteral("zzzz"))
+}
+
+func TestEditDistance(t *testing.T) {
+	testCase := func(a, b string, expected int) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, EditDistance(a, b))
+			assert.Equal(t, expected, EditDistance(b, a))
+		}
+	}
+
+	t.Run("Equal", testCase("list", "list", 0))
+	t.Run("Empty", testCase("", "list", 4))
+	t.Run("Substitution", testCase("lost", "list", 1))
+	t.Run("Insertion", testCase("lisst", "list", 1))
+	t.Run("Transposition", testCase("lsit", "list", 1))
+	t.Run("Several edits", testCase("kitten", "sitting", 3))
+}
+
 func TestMakeOptionalArgToken(t *testing.T) {
 	token := MakeOptionalArgToken(1, "t", "test")
 
@@ -345,6 +435,7 @@ func TestItemNameValue(t *testing.T) {
 	t.Run("single word", testCase("test", "test"))
 	t.Run("double quotes", testCase("\"many words\"", "many words"))
 	t.Run("single quotes", testCase("'many words'", "many words"))
+	t.Run("quote inside", testCase("Smiths'", "Smiths'"))
 }
 
 func TestMoneyValue(t *testing.T) {
@@ -368,3 +459,106 @@ func TestMoneyValue(t *testing.T) {
 
 	t.Run("invalid", testCase("notmoney", models.MakeMoney(0)))
 }
+
+func TestTokenizeQuoting(t *testing.T) {
+	testCase := func(input string, expected []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, Tokenize(input))
+		}
+	}
+
+	t.Run("Quoted option value", testCase(`new account cash -d="weekly groceries"`, []string{"new", "account", "cash", "-d=weekly groceries"}))
+	t.Run("Single quoted option value", testCase(`-d='weekly groceries'`, []string{"-d=weekly groceries"}))
+	t.Run("Apostrophe in double quotes", testCase(`"Bob's account"`, []string{"Bob's account"}))
+	t.Run("Double quote in single quotes", testCase(`'say "hi"'`, []string{`say "hi"`}))
+	t.Run("Escaped double quote", testCase(`"say \"hi\""`, []string{`say "hi"`}))
+	t.Run("Escaped backslash", testCase(`"a\\b"`, []string{`a\b`}))
+	t.Run("Other backslashes in double quotes are kept", testCase(`"a\nb"`, []string{`a\nb`}))
+	t.Run("Backslashes in single quotes are kept", testCase(`'a\"b'`, []string{`a\"b`}))
+	t.Run("Escaped space", testCase(`my\ account x`, []string{"my account", "x"}))
+	t.Run("Escaped quote outside quotes", testCase(`O\'Brien`, []string{"O'Brien"}))
+	t.Run("Quotes in the middle of a token", testCase(`ab"c d"e`, []string{"abc de"}))
+	t.Run("Empty quotes", testCase(`a "" b`, []string{"a", "", "b"}))
+	t.Run("Empty option value", testCase(`-d="" -b=5`, []string{"-d=", "", "-b=5"}))
+	t.Run("Empty single quoted option value", testCase(`--description='' x`, []string{"--description=", "", "x"}))
+	t.Run("Empty quotes in an option value", testCase(`-d=a"" -m=""b`, []string{"-d=a", "-m=b"}))
+	t.Run("Unicode", testCase("new account Müller-Konto  -d=Café\t", []string{"new", "account", "Müller-Konto", "-d=Café"}))
+	t.Run("Extra whitespace", testCase("  a \t b  ", []string{"a", "b"}))
+	t.Run("Empty", testCase("", nil))
+}
+
+func TestJoinTokens(t *testing.T) {
+	testCase := func(tokens []string, expected string) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, JoinTokens(tokens))
+			assert.Equal(t, tokens, Tokenize(expected))
+		}
+	}
+
+	t.Run("Plain", testCase([]string{"list", "account", "-c=food"}, "list account -c=food"))
+	t.Run("Space", testCase([]string{"new", "My Account"}, `new "My Account"`))
+	t.Run("Double quote", testCase([]string{"new", `Say "hi"`}, `new 'Say "hi"'`))
+	t.Run("Apostrophe", testCase([]string{"new", "O'Brien"}, `new "O'Brien"`))
+	t.Run("Both quotes and a backslash", testCase([]string{"new", `Say "it's" \o/`}, `new "Say \"it's\" \\o/"`))
+	t.Run("Option with a space", testCase([]string{"new", "-d=weekly groceries"}, `new "-d=weekly groceries"`))
+	t.Run("Unicode", testCase([]string{"new", "Müller Konto"}, `new "Müller Konto"`))
+	t.Run("Empty option value", testCase([]string{"new", "-d=", ""}, `new -d= ""`))
+}
+
+func TestTokenizeWithSpans(t *testing.T) {
+	tokens, spans, err := TokenizeWithSpans(`list  "my acc" Café`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"list", "my acc", "Café"}, tokens)
+	assert.Equal(t, []Span{{0, 4}, {6, 14}, {15, 20}}, spans)
+
+	tokens, spans, err = TokenizeWithSpans(`new -d="" x`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"new", "-d=", "", "x"}, tokens)
+	assert.Equal(t, []Span{{0, 3}, {4, 7}, {7, 9}, {10, 11}}, spans)
+
+	tokens, spans, err = TokenizeWithSpans(`new account "unfinished name`)
+
+	assert.Equal(t, []string{"new", "account", "unfinished name"}, tokens)
+	assert.Equal(t, Span{12, 28}, spans[2])
+	if assert.NotNil(t, err) {
+		assert.Equal(t, 2, err.TokenIndex)
+		assert.Equal(t, `missing closing quote "`, err.Message)
+	}
+}
+
+func TestSplitOption(t *testing.T) {
+	testCase := func(token, expectedOption, expectedValue string, expectedOk bool) func(t *testing.T) {
+		return func(t *testing.T) {
+			option, value, ok := splitOption(token)
+			assert.Equal(t, expectedOption, option)
+			assert.Equal(t, expectedValue, value)
+			assert.Equal(t, expectedOk, ok)
+		}
+	}
+
+	t.Run("Short option", testCase("-d=value", "-d=", "value", true))
+	t.Run("Long option", testCase("--description=a=b", "--description=", "a=b", true))
+	t.Run("Empty value", testCase("-d=", "-d=", "", true))
+	t.Run("Flag", testCase("--hard", "--hard", "", false))
+	t.Run("Not an option", testCase("a=b", "a=b", "", false))
+}
+
+func TestItemNamePattern(t *testing.T) {
+	testCase := func(name string, expected bool) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, ItemNamePattern.MatchString(name))
+		}
+	}
+
+	t.Run("Ascii", testCase("cash", true))
+	t.Run("Accented", testCase("Café", true))
+	t.Run("Accent first", testCase("Épargne", true))
+	t.Run("Hyphen and umlaut", testCase("Müller-Konto", true))
+	t.Run("Other scripts", testCase("貯金", true))
+	t.Run("Digits", testCase("Savings 2024", true))
+	t.Run("Apostrophe", testCase("Bob's", true))
+	t.Run("Starts with digit", testCase("2024", false))
+	t.Run("Flag", testCase("--hard", false))
+}
@@ -0,0 +1,78 @@
+package parse
+
+import (
+	"strings"
+
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+// maxValueSuggestions limits how many known values are suggested for one argument
+const maxValueSuggestions = 10
+
+// knownValues lists the values of this kind already in the ledger that start with prefix, most recently used first.
+// Kinds without stored values, such as money, have none.
+func (kind ArgKind) knownValues(s *session.Session, prefix string) []string {
+	if s == nil || s.Db == nil {
+		return []string{}
+	}
+
+	var names []string
+	switch kind {
+	case AccountArg:
+		names = recentAccountNames(s)
+	case CategoryArg:
+		names = recentCategoryNames(s)
+	case MacroArg:
+		names = macroNames(s)
+	default:
+		return []string{}
+	}
+
+	values := []string{}
+	for _, name := range names {
+		if len(values) == maxValueSuggestions {
+			break
+		}
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) && !strings.EqualFold(name, prefix) {
+			values = append(values, name)
+		}
+	}
+	return values
+}
+
+// recentAccountNames lists open accounts by the last time their balance changed
+func recentAccountNames(s *session.Session) []string {
+	var accounts []models.Account
+	s.Db.Joins("CurrentState").
+		Where("accounts.is_active = ?", true).
+		Order("CurrentState__created_at desc, accounts.id desc").
+		Find(&accounts)
+
+	names := []string{}
+	for _, account := range accounts {
+		names = append(names, account.Name)
+	}
+	return names
+}
+
+// recentCategoryNames lists categories by the last time the balance of one of their accounts changed. Categories
+// without accounts come last.
+func recentCategoryNames(s *session.Session) []string {
+	var categories []models.Category
+	s.Db.Order(`(SELECT MAX(account_states.created_at) FROM accounts
+			JOIN account_states ON accounts.current_state_id = account_states.id
+			WHERE accounts.category_id = categories.id) desc`).
+		Order("categories.updated_at desc, categories.fully_qualified_name").
+		Find(&categories)
+
+	names := []string{}
+	for _, category := range categories {
+		if category.FullyQualifiedName != "" {
+			names = append(names, category.FullyQualifiedName)
+		} else {
+			names = append(names, category.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+func valuesTestSession() session.Session {
+	s := session.InMemorySession(models.MigrateSchema)
+
+	groceries := models.MakeCategory("groceries", "", nil)
+	s.Db.Create(&groceries)
+	bills := models.MakeCategory("bills", "", nil)
+	s.Db.Create(&bills)
+	rent := models.MakeCategory("rent", "", &bills)
+	s.Db.Create(&rent)
+
+	s.Db.Create(&models.Account{Name: "cash", IsActive: true, CurrentState: models.AccountState{CreatedAt: 300}})
+	s.Db.Create(&models.Account{Name: "chequing", IsActive: true, CategoryID: &rent.ID, CurrentState: models.AccountState{CreatedAt: 100}})
+	s.Db.Create(&models.Account{Name: "checking", IsActive: true, CategoryID: &groceries.ID, CurrentState: models.AccountState{CreatedAt: 200}})
+	s.Db.Create(&models.Account{Name: "closed", IsActive: false, CurrentState: models.AccountState{CreatedAt: 400}})
+
+	return s
+}
+
+func TestKnownValues(t *testing.T) {
+	s := valuesTestSession()
+
+	testCase := func(kind ArgKind, prefix string, expected []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			assert.Equal(t, expected, kind.knownValues(&s, prefix))
+		}
+	}
+
+	t.Run("Accounts by recent use", testCase(AccountArg, "", []string{"cash", "checking", "chequing"}))
+	t.Run("Accounts by prefix", testCase(AccountArg, "CH", []string{"checking", "chequing"}))
+	t.Run("Exact account is not suggested", testCase(AccountArg, "cash", []string{}))
+	t.Run("Categories by recent use", testCase(CategoryArg, "", []string{"groceries", "bills/rent", "bills"}))
+	t.Run("Categories by prefix", testCase(CategoryArg, "bills/", []string{"bills/rent"}))
+	t.Run("Money has no known values", testCase(MoneyArg, "", []string{}))
+	t.Run("No session", func(t *testing.T) {
+		assert.Empty(t, AccountArg.knownValues(nil, ""))
+	})
+}
+
+func TestValueSuggestions(t *testing.T) {
+	s := valuesTestSession()
+
+	testCase := func(input string, expectedNextArgs []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, suggestion := ParseExpression(input, &s)
+
+			assert.Equal(t, expectedNextArgs, suggestion.NextArgs)
+		}
+	}
+
+	t.Run("Missing positional", testCase("delete account", []string{"cash", "checking", "chequing", "<name>", "--help"}))
+	t.Run("Partial positional", testCase("delete account ch", []string{"checking", "chequing", "--hard"}))
+	t.Run("Missing option value", testCase("new transaction 5 --from=", []string{"cash", "checking", "chequing", "<from>"}))
+	t.Run("Partial option value", testCase("new account savings --category=b", []string{"bills/rent", "bills", "--description", "--balance"}))
+}
+
+func TestCompleteKnownValues(t *testing.T) {
+	s := valuesTestSession()
+
+	completion := Complete("report forecast --account=che", 29, &s)
+
+	assert.Equal(t, []string{"checking", "chequing"}, completion.Completions)
+}
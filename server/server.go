@@ -0,0 +1,301 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"samvasta.com/bujit/actions"
+	actions_accounts "samvasta.com/bujit/actions/accounts"
+	actions_categories "samvasta.com/bujit/actions/categories"
+	actions_pipeline "samvasta.com/bujit/actions/pipeline"
+	actions_transactions "samvasta.com/bujit/actions/transactions"
+	"samvasta.com/bujit/cli/jsonview"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/parse"
+	"samvasta.com/bujit/session"
+	"samvasta.com/bujit/util"
+)
+
+// maxBodySize limits request bodies, which are only ever a small JSON object
+const maxBodySize = 1 << 20
+
+// Server exposes the ledger actions over HTTP. Every response is a jsonview.Document.
+type Server struct {
+	session *session.Session
+	mux     *http.ServeMux
+	mu      sync.Mutex // actions share one database connection, so only one runs at a time
+}
+
+func NewServer(session *session.Session) *Server {
+	server := &Server{session: session, mux: http.NewServeMux()}
+
+	server.mux.HandleFunc("/accounts", server.handleAccounts)
+	server.mux.HandleFunc("/accounts/", server.handleAccount)
+	server.mux.HandleFunc("/categories", server.handleCategories)
+	server.mux.HandleFunc("/transactions", server.handleTransactions)
+	server.mux.HandleFunc("/command", server.handleCommand)
+
+	return server
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+type createAccountRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Balance     float64 `json:"balance"`
+}
+
+type commandRequest struct {
+	Command string `json:"command"`
+}
+
+type createTransactionRequest struct {
+	Amount float64 `json:"amount"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Memo   string  `json:"memo"`
+	Date   string  `json:"date"` // any date the command line accepts, such as 2026-10-16 or yesterday. Defaults to today
+}
+
+// GET /accounts?name=&category= lists accounts, POST /accounts creates one
+func (server *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		server.run(w, r, http.StatusOK, actions_accounts.ListAccountAction{
+			Name:         query.Get("name"),
+			CategoryName: query.Get("category"),
+			Session:      server.session,
+		})
+	case http.MethodPost:
+		var body createAccountRequest
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		action := actions_accounts.CreateAccountAction{
+			Name:            body.Name,
+			Description:     body.Description,
+			CategoryName:    body.Category,
+			StartingBalance: models.MakeMoney(body.Balance),
+			Session:         server.session,
+		}
+		if !action.IsValid() {
+			writeError(w, r, http.StatusBadRequest, "name is required")
+			return
+		}
+		server.run(w, r, http.StatusCreated, action)
+	default:
+		methodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// DELETE /accounts/<name>?hard=true closes or deletes an account
+func (server *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/accounts/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, r, http.StatusNotFound, "not found")
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, r, http.MethodDelete)
+		return
+	}
+
+	hard, _ := strconv.ParseBool(r.URL.Query().Get("hard"))
+	server.run(w, r, http.StatusOK, actions_accounts.DeleteAccountAction{Name: name, IsHardDelete: hard, Session: server.session})
+}
+
+// GET /categories?name= lists categories
+func (server *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	server.run(w, r, http.StatusOK, actions_categories.ListCategoryAction{Name: r.URL.Query().Get("name"), Session: server.session})
+}
+
+// GET /transactions?account=&limit=&since=&until= lists transactions, POST /transactions creates one
+func (server *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		limit := 0
+		if value := query.Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+				writeError(w, r, http.StatusBadRequest, "limit must be a whole number")
+				return
+			}
+		}
+		since, ok := parseDate(w, r, "since", query.Get("since"))
+		if !ok {
+			return
+		}
+		until, ok := parseDate(w, r, "until", query.Get("until"))
+		if !ok {
+			return
+		}
+		action := actions_transactions.ListTransactionAction{
+			AccountName: query.Get("account"),
+			Limit:       limit,
+			Since:       since.Start,
+			Until:       until.End,
+			Session:     server.session,
+		}
+		if !action.IsValid() {
+			writeError(w, r, http.StatusBadRequest, "since must be before until")
+			return
+		}
+		server.run(w, r, http.StatusOK, action)
+	case http.MethodPost:
+		var body createTransactionRequest
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		date, ok := parseDate(w, r, "date", body.Date)
+		if !ok {
+			return
+		}
+		action := actions_transactions.CreateTransactionAction{
+			Amount:          models.MakeMoney(body.Amount),
+			SourceName:      body.From,
+			DestinationName: body.To,
+			Memo:            body.Memo,
+			EffectiveAt:     date.Start,
+			Session:         server.session,
+		}
+		if !action.IsValid() {
+			writeError(w, r, http.StatusBadRequest, "amount must be more than 0 and from or to is required")
+			return
+		}
+		server.run(w, r, http.StatusCreated, action)
+	default:
+		methodNotAllowed(w, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+// parseDate reads a date parameter the same way the command line does. An empty value is an empty range. ok is false,
+// after writing an error, when the value is not a date.
+func parseDate(w http.ResponseWriter, r *http.Request, name, value string) (dates util.DateRange, ok bool) {
+	if value == "" {
+		return dates, true
+	}
+	dates, err := util.ParseDate(value, util.Today())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s %q: %s", name, value, err))
+		return dates, false
+	}
+	return dates, true
+}
+
+// POST /command runs {"command": "..."} exactly as it would be typed at the prompt, except for commands that read
+// files or run commands from the history
+func (server *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r, http.MethodPost)
+		return
+	}
+
+	var body commandRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	command := strings.TrimSpace(body.Command)
+
+	server.mu.Lock()
+	action, suggestion := parse.ParseExpression(command, server.session)
+	server.mu.Unlock()
+
+	if action == nil {
+		writeDocument(w, http.StatusBadRequest, jsonview.ParseErrorDocument(command, suggestion.Problem(command)))
+		return
+	}
+	if !isAllowedOverHTTP(action) {
+		writeDocument(w, http.StatusForbidden, jsonview.ParseErrorDocument(command, "source and history commands cannot be run over HTTP"))
+		return
+	}
+
+	server.runCommand(w, command, http.StatusOK, action)
+}
+
+// isAllowedOverHTTP is false when the action, or any command chained, piped or formatted with it, runs a script file
+// or a command from the history. Either could read or run anything on the machine the server runs on.
+func isAllowedOverHTTP(action actions.Actioner) bool {
+	switch a := action.(type) {
+	case actions.FormatAction:
+		return isAllowedOverHTTP(a.Action)
+	case parse.SourceAction:
+		return false
+	case parse.HistoryAction:
+		return a.Number == 0
+	case actions_pipeline.SequenceAction:
+		for _, step := range a.Actions {
+			if !isAllowedOverHTTP(step) {
+				return false
+			}
+		}
+	case actions_pipeline.PipeAction:
+		return isAllowedOverHTTP(a.Action)
+	}
+	return true
+}
+
+// run executes the action and writes its document, using the method and path as the command
+func (server *Server) run(w http.ResponseWriter, r *http.Request, successStatus int, action actions.Actioner) {
+	server.runCommand(w, r.Method+" "+r.URL.RequestURI(), successStatus, action)
+}
+
+func (server *Server) runCommand(w http.ResponseWriter, command string, successStatus int, action actions.Actioner) {
+	server.mu.Lock()
+	result, consequences := action.Execute()
+	server.mu.Unlock()
+
+	status := successStatus
+	if !result.IsSuccessful {
+		status = http.StatusUnprocessableEntity
+	}
+	writeDocument(w, status, jsonview.MakeDocument(command, result, consequences))
+}
+
+// decodeBody reads a JSON request body. The body must be sent as application/json, which browsers only do for other
+// sites after asking the server first, so a web page cannot change the ledger behind the user's back.
+func decodeBody(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, r, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeDocument(w, status, jsonview.ParseErrorDocument(r.Method+" "+r.URL.RequestURI(), message))
+}
+
+func writeDocument(w http.ResponseWriter, status int, doc jsonview.Document) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, jsonview.Line(doc))
+}
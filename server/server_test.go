@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"samvasta.com/bujit/models"
+	"samvasta.com/bujit/session"
+)
+
+type document struct {
+	Command      string            `json:"command"`
+	Success      bool              `json:"success"`
+	Output       []json.RawMessage `json:"output"`
+	Consequences []struct {
+		Type   string                 `json:"type"`
+		Kind   string                 `json:"kind"`
+		Object map[string]interface{} `json:"object"`
+	} `json:"consequences"`
+}
+
+func request(t *testing.T, server *Server, method, target, body string) (int, document) {
+	return requestWithType(t, server, method, target, "application/json", body)
+}
+
+func requestWithType(t *testing.T, server *Server, method, target, contentType, body string) (int, document) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	server.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var doc document
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &doc), recorder.Body.String())
+	return recorder.Code, doc
+}
+
+func TestAccounts(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	server := NewServer(&s)
+
+	code, doc := request(t, server, http.MethodPost, "/accounts", `{"name": "cash", "balance": 12.5}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.True(t, doc.Success)
+	assert.Equal(t, "create", doc.Consequences[0].Type)
+	assert.Equal(t, "account", doc.Consequences[0].Kind)
+
+	request(t, server, http.MethodPost, "/accounts", `{"name": "savings"}`)
+
+	code, doc = request(t, server, http.MethodGet, "/accounts?name=cash", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "GET /accounts?name=cash", doc.Command)
+	assert.Len(t, doc.Consequences, 1)
+	assert.Equal(t, "12.50 USD", doc.Consequences[0].Object["currentBalance"])
+	assert.Len(t, doc.Output, 1)
+
+	code, doc = request(t, server, http.MethodDelete, "/accounts/savings", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "delete", doc.Consequences[0].Type)
+
+	code, doc = request(t, server, http.MethodDelete, "/accounts/missing", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.False(t, doc.Success)
+}
+
+func TestAccountsBadRequest(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	server := NewServer(&s)
+
+	code, doc := request(t, server, http.MethodPost, "/accounts", `{"balance": 1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.False(t, doc.Success)
+
+	code, _ = request(t, server, http.MethodPost, "/accounts", `{"name": "cash", "unknown": 1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = request(t, server, http.MethodPut, "/accounts", `{}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestTransactions(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	server := NewServer(&s)
+
+	request(t, server, http.MethodPost, "/accounts", `{"name": "checking", "balance": 100}`)
+	request(t, server, http.MethodPost, "/accounts", `{"name": "savings"}`)
+
+	code, doc := request(t, server, http.MethodPost, "/transactions", `{"amount": 25.5, "from": "checking", "to": "savings", "memo": "rainy day"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Len(t, doc.Consequences, 3)
+	assert.Equal(t, "transaction", doc.Consequences[0].Kind)
+	assert.Equal(t, "update", doc.Consequences[1].Type)
+	assert.Equal(t, "74.50 USD", doc.Consequences[1].Object["currentBalance"])
+
+	code, doc = request(t, server, http.MethodGet, "/transactions?account=savings&limit=10", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, doc.Consequences, 1)
+	assert.Equal(t, "rainy day", doc.Consequences[0].Object["memo"])
+
+	code, _ = request(t, server, http.MethodGet, "/transactions?limit=many", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = request(t, server, http.MethodPost, "/transactions", `{"amount": 0, "from": "checking"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, doc = request(t, server, http.MethodPost, "/transactions", `{"amount": 5, "from": "checking", "memo": "coffee", "date": "2020-02-14"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "2020-02-14", doc.Consequences[0].Object["date"])
+
+	code, doc = request(t, server, http.MethodGet, "/transactions?since=2020-02&until=2020-q1", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, doc.Consequences, 1)
+	assert.Equal(t, "coffee", doc.Consequences[0].Object["memo"])
+
+	code, _ = request(t, server, http.MethodGet, "/transactions?since=someday", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = request(t, server, http.MethodPost, "/transactions", `{"amount": 5, "from": "checking", "date": "2020-02-30"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestCategories(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	server := NewServer(&s)
+
+	request(t, server, http.MethodPost, "/accounts", `{"name": "cash", "category": "wallet"}`)
+
+	code, doc := request(t, server, http.MethodGet, "/categories", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, doc.Consequences, 1)
+	assert.Equal(t, "category", doc.Consequences[0].Kind)
+	assert.Equal(t, "wallet", doc.Consequences[0].Object["name"])
+}
+
+func TestCommand(t *testing.T) {
+	s := session.InMemorySession(models.MigrateSchema)
+	server := NewServer(&s)
+
+	code, doc := request(t, server, http.MethodPost, "/command", `{"command": "new account cash -b=5"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, doc.Success)
+	assert.Equal(t, "new account cash -b=5", doc.Command)
+
+	code, doc = request(t, server, http.MethodPost, "/command", `{"command": "lsit account"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.False(t, doc.Success)
+
+	code, doc = requestWithType(t, server, http.MethodPost, "/command", "text/plain", `{"command": "new account savings"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, code)
+	assert.False(t, doc.Success)
+
+	for _, command := range []string{
+		"source scripts/monthly.bujit",
+		"list account; source scripts/monthly.bujit",
+		"history 1",
+		"source scripts/monthly.bujit --format=md",
+		"list account; source scripts/monthly.bujit --format=html",
+		"source scripts/monthly.bujit --format=md | count",
+		"history 1 --format=md",
+	} {
+		code, doc = request(t, server, http.MethodPost, "/command", `{"command": "`+command+`"}`)
+		assert.Equal(t, http.StatusForbidden, code, command)
+		assert.False(t, doc.Success, command)
+	}
+
+	code, _ = request(t, server, http.MethodGet, "/command", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultHistoryLimit is how many commands are kept in a history file
+const DefaultHistoryLimit = 1000
+
+// History is the commands typed at the prompt, oldest first. Each command is kept once, where it was last typed.
+type History struct {
+	Path    string // file the history is saved to. Empty keeps it in memory only
+	Limit   int    // most commands kept. 0 or less keeps every command
+	Entries []string
+}
+
+// LoadHistory reads the history saved at path. A file that does not exist yet is an empty history.
+func LoadHistory(path string, limit int) (*History, error) {
+	history := &History{Path: path, Limit: limit, Entries: []string{}}
+	if path == "" {
+		return history, nil
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return history, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		history.push(scanner.Text())
+	}
+	return history, scanner.Err()
+}
+
+// push adds the command to the end, removing an earlier copy of it and the oldest commands over the limit
+func (history *History) push(command string) bool {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return false
+	}
+
+	for i, entry := range history.Entries {
+		if entry == command {
+			history.Entries = append(history.Entries[:i], history.Entries[i+1:]...)
+			break
+		}
+	}
+	history.Entries = append(history.Entries, command)
+
+	if history.Limit > 0 && len(history.Entries) > history.Limit {
+		history.Entries = history.Entries[len(history.Entries)-history.Limit:]
+	}
+	return true
+}
+
+// Add records a command and saves the history. Blank commands are not recorded.
+func (history *History) Add(command string) error {
+	if history == nil || !history.push(command) || history.Path == "" {
+		return nil
+	}
+	return history.save()
+}
+
+func (history *History) save() error {
+	if err := os.MkdirAll(filepath.Dir(history.Path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(history.Path, []byte(strings.Join(history.Entries, "\n")+"\n"), 0600)
+}
+
+// Entry is the command with the given number, counting from 1 for the oldest
+func (history *History) Entry(number int) (string, bool) {
+	if history == nil || number < 1 || number > len(history.Entries) {
+		return "", false
+	}
+	return history.Entries[number-1], true
+}
+
+// Search finds the newest command before index that contains text. Returns the index of the command and the byte
+// offset of text in it.
+func (history *History) Search(text string, before int) (index int, offset int, ok bool) {
+	if history == nil {
+		return 0, 0, false
+	}
+	if before > len(history.Entries) {
+		before = len(history.Entries)
+	}
+	for i := before - 1; i >= 0; i-- {
+		if offset := strings.Index(history.Entries[i], text); offset >= 0 {
+			return i, offset, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.db.history")
+
+	history, err := LoadHistory(path, 3)
+	assert.Nil(t, err)
+	assert.Empty(t, history.Entries)
+
+	history.Add("list account")
+	history.Add("  ")
+	history.Add("new account cash")
+	history.Add("list account")
+	assert.Equal(t, []string{"new account cash", "list account"}, history.Entries)
+
+	history.Add("version")
+	history.Add("help")
+	assert.Equal(t, []string{"list account", "version", "help"}, history.Entries)
+
+	saved, _ := os.ReadFile(path)
+	assert.Equal(t, "list account\nversion\nhelp\n", string(saved))
+
+	loaded, err := LoadHistory(path, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"version", "help"}, loaded.Entries)
+
+	entry, ok := loaded.Entry(1)
+	assert.True(t, ok)
+	assert.Equal(t, "version", entry)
+	_, ok = loaded.Entry(3)
+	assert.False(t, ok)
+}
+
+func TestHistorySearch(t *testing.T) {
+	history := &History{Entries: []string{"new account cash", "list account", "new account visa"}}
+
+	index, offset, ok := history.Search("account", 3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, index)
+	assert.Equal(t, 4, offset)
+
+	index, offset, ok = history.Search("account", index)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, 5, offset)
+
+	_, _, ok = history.Search("visa", 2)
+	assert.False(t, ok)
+
+	var none *History
+	_, _, ok = none.Search("list", 10)
+	assert.False(t, ok)
+	assert.Nil(t, none.Add("list"))
+}
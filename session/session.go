@@ -9,6 +9,7 @@ type Session struct {
 	CurrencyPrefix string
 	CurrencySuffix string
 	Db             *gorm.DB
+	History        *History // commands typed at the prompt, nil when there is no prompt
 }
 
 type Sessioner interface {
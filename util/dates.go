@@ -0,0 +1,235 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is the days from Start up to but not including End. Both are midnight UTC.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsDay is true when the range is exactly one day
+func (r DateRange) IsDay() bool {
+	return r.End.Equal(r.Start.AddDate(0, 0, 1))
+}
+
+func (r DateRange) String() string {
+	if r.IsDay() {
+		return r.Start.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s to %s", r.Start.Format("2006-01-02"), r.End.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+// Day is the calendar date of t as midnight UTC
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today is the current calendar date as midnight UTC
+func Today() time.Time {
+	return Day(time.Now())
+}
+
+var (
+	isoDatePattern  = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	monthPattern    = regexp.MustCompile(`^(\d{4})-(\d{1,2})$`)
+	yearPattern     = regexp.MustCompile(`^(\d{4})$`)
+	quarterPattern  = regexp.MustCompile(`^(?:(\d{4})[ -]?)?q([1-4])(?:[ -]?(\d{4}))?$`)
+	relativePattern = regexp.MustCompile(`^([+-])(\d+)\s*([dwmy])$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseDate reads a day or a period relative to today. It accepts
+//
+//	2026-10-16               a day
+//	2026-10                  a month
+//	2026                     a year
+//	q3, 2026-q3              a quarter, of this year unless the year is given
+//	today, yesterday, tomorrow
+//	friday                   the last friday on or before today
+//	last friday, next friday the friday before or after today
+//	this week, last month, next year, this quarter, ...
+//	-3d, +2w, -1m, -1y       days, weeks, months or years before or after today
+//
+// Words may be separated by spaces, - or _ and case does not matter.
+func ParseDate(value string, today time.Time) (DateRange, error) {
+	today = Day(today)
+	text := strings.ToLower(strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '_'
+	}), " "))
+
+	day := func(t time.Time) (DateRange, error) {
+		return DateRange{t, t.AddDate(0, 0, 1)}, nil
+	}
+
+	switch text {
+	case "today":
+		return day(today)
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	case "tomorrow":
+		return day(today.AddDate(0, 0, 1))
+	}
+
+	if weekday, ok := weekdays[text]; ok {
+		return day(today.AddDate(0, 0, -daysBack(today.Weekday(), weekday)))
+	}
+
+	if words := strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == '-' }); len(words) == 2 {
+		if r, ok := relativeToToday(words[0], words[1], today); ok {
+			return r, nil
+		}
+	}
+
+	if m := relativePattern.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "d":
+			return day(today.AddDate(0, 0, n))
+		case "w":
+			return day(today.AddDate(0, 0, 7*n))
+		case "m":
+			return day(today.AddDate(0, n, 0))
+		default:
+			return day(today.AddDate(n, 0, 0))
+		}
+	}
+
+	if m := isoDatePattern.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		dayOfMonth, _ := strconv.Atoi(m[3])
+		t := time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.UTC)
+		if month < 1 || month > 12 || t.Day() != dayOfMonth {
+			return DateRange{}, errors.New("there is no such day")
+		}
+		return day(t)
+	}
+
+	if m := monthPattern.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return DateRange{}, errors.New("there is no such month")
+		}
+		return monthRange(year, time.Month(month)), nil
+	}
+
+	if m := yearPattern.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return yearRange(year), nil
+	}
+
+	if m := quarterPattern.FindStringSubmatch(text); m != nil && !(m[1] != "" && m[3] != "") {
+		year := today.Year()
+		if m[1] != "" {
+			year, _ = strconv.Atoi(m[1])
+		} else if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+		}
+		quarter, _ := strconv.Atoi(m[2])
+		return quarterRange(year, quarter), nil
+	}
+
+	return DateRange{}, errors.New("not a date, try 2026-10-16, yesterday, last friday, -3d, 2026-10 or q3")
+}
+
+// relativeToToday reads phrases such as "last friday", "next week" or "this quarter"
+func relativeToToday(which, unit string, today time.Time) (DateRange, bool) {
+	var offset int
+	switch which {
+	case "last":
+		offset = -1
+	case "this":
+		offset = 0
+	case "next":
+		offset = 1
+	default:
+		return DateRange{}, false
+	}
+
+	if weekday, ok := weekdays[unit]; ok {
+		var t time.Time
+		switch offset {
+		case -1:
+			t = today.AddDate(0, 0, -daysBack(today.Weekday(), weekday))
+			if t.Equal(today) {
+				t = t.AddDate(0, 0, -7)
+			}
+		case 1:
+			t = today.AddDate(0, 0, 7-daysBack(today.Weekday(), weekday))
+		default:
+			// the day in the current week, which starts on monday
+			t = weekRange(today).Start.AddDate(0, 0, (int(weekday)+6)%7)
+		}
+		return DateRange{t, t.AddDate(0, 0, 1)}, true
+	}
+
+	switch unit {
+	case "week":
+		week := weekRange(today)
+		return DateRange{week.Start.AddDate(0, 0, 7*offset), week.End.AddDate(0, 0, 7*offset)}, true
+	case "month":
+		month := today.AddDate(0, 0, 1-today.Day()).AddDate(0, offset, 0)
+		return monthRange(month.Year(), month.Month()), true
+	case "quarter":
+		quarter := (int(today.Month())-1)/3 + 1 + offset
+		year := today.Year()
+		for quarter < 1 {
+			quarter += 4
+			year--
+		}
+		for quarter > 4 {
+			quarter -= 4
+			year++
+		}
+		return quarterRange(year, quarter), true
+	case "year":
+		return yearRange(today.Year() + offset), true
+	}
+	return DateRange{}, false
+}
+
+// daysBack is how many days ago, from 0 to 6, the weekday last was
+func daysBack(from, weekday time.Weekday) int {
+	return (int(from) - int(weekday) + 7) % 7
+}
+
+func weekRange(today time.Time) DateRange {
+	start := today.AddDate(0, 0, -daysBack(today.Weekday(), time.Monday))
+	return DateRange{start, start.AddDate(0, 0, 7)}
+}
+
+func monthRange(year int, month time.Month) DateRange {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return DateRange{start, start.AddDate(0, 1, 0)}
+}
+
+func quarterRange(year, quarter int) DateRange {
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+	return DateRange{start, start.AddDate(0, 3, 0)}
+}
+
+func yearRange(year int) DateRange {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return DateRange{start, start.AddDate(1, 0, 0)}
+}
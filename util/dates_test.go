@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDate(t *testing.T) {
+	// a monday
+	today := time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+
+	testCase := func(value string, start, end time.Time) func(t *testing.T) {
+		return func(t *testing.T) {
+			dates, err := ParseDate(value, today)
+			assert.NoError(t, err)
+			assert.Equal(t, start, dates.Start)
+			assert.Equal(t, end, dates.End)
+		}
+	}
+
+	t.Run("iso day", testCase("2026-10-16", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("short iso day", testCase("2026-2-3", date(2026, 2, 3), date(2026, 2, 4)))
+	t.Run("month", testCase("2026-10", date(2026, 10, 1), date(2026, 11, 1)))
+	t.Run("year", testCase("2025", date(2025, 1, 1), date(2026, 1, 1)))
+	t.Run("quarter", testCase("q3", date(2026, 7, 1), date(2026, 10, 1)))
+	t.Run("quarter with year", testCase("2025-Q4", date(2025, 10, 1), date(2026, 1, 1)))
+	t.Run("quarter with year after", testCase("q1 2027", date(2027, 1, 1), date(2027, 4, 1)))
+	t.Run("today", testCase("today", date(2026, 10, 19), date(2026, 10, 20)))
+	t.Run("yesterday", testCase("Yesterday", date(2026, 10, 18), date(2026, 10, 19)))
+	t.Run("tomorrow", testCase("tomorrow", date(2026, 10, 20), date(2026, 10, 21)))
+	t.Run("weekday", testCase("friday", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("weekday is today", testCase("mon", date(2026, 10, 19), date(2026, 10, 20)))
+	t.Run("last weekday", testCase("last friday", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("last weekday is not today", testCase("last monday", date(2026, 10, 12), date(2026, 10, 13)))
+	t.Run("last weekday with dash", testCase("last-friday", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("last weekday with underscore", testCase("last_fri", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("next weekday", testCase("next monday", date(2026, 10, 26), date(2026, 10, 27)))
+	t.Run("this weekday", testCase("this sunday", date(2026, 10, 25), date(2026, 10, 26)))
+	t.Run("days ago", testCase("-3d", date(2026, 10, 16), date(2026, 10, 17)))
+	t.Run("weeks ahead", testCase("+2w", date(2026, 11, 2), date(2026, 11, 3)))
+	t.Run("months ago", testCase("-1m", date(2026, 9, 19), date(2026, 9, 20)))
+	t.Run("years ago", testCase("-1y", date(2025, 10, 19), date(2025, 10, 20)))
+	t.Run("this week", testCase("this week", date(2026, 10, 19), date(2026, 10, 26)))
+	t.Run("last month", testCase("last month", date(2026, 9, 1), date(2026, 10, 1)))
+	t.Run("next quarter", testCase("next quarter", date(2027, 1, 1), date(2027, 4, 1)))
+	t.Run("last year", testCase("last year", date(2025, 1, 1), date(2026, 1, 1)))
+}
+
+func TestParseDateLastMonthAtEndOfMonth(t *testing.T) {
+	dates, err := ParseDate("last month", date(2026, 3, 31))
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 2, 1), dates.Start)
+}
+
+func TestParseDateErrors(t *testing.T) {
+	today := date(2026, 10, 19)
+
+	for _, value := range []string{"", "someday", "2026-02-30", "2026-13", "q5", "2025-q1-2026", "last fortnight", "3d"} {
+		_, err := ParseDate(value, today)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDateRangeString(t *testing.T) {
+	assert.Equal(t, "2026-10-16", DateRange{date(2026, 10, 16), date(2026, 10, 17)}.String())
+	assert.Equal(t, "2026-10-01 to 2026-10-31", DateRange{date(2026, 10, 1), date(2026, 11, 1)}.String())
+}